@@ -21,15 +21,27 @@ import {
   CalendarEvent,
   ClimbRecordEntry,
   DistanceRecordEntry,
+  DuplicatePair,
+  EffortMetric,
+  EffortRecordEntry,
+  ElevationCorrection,
+  Exercise,
+  ExerciseEffort,
+  ImportPreviewItem,
+  ImportSelection,
+  MergeSensorsResult,
+  MergeWorkoutResult,
   Totals,
   Workout,
   WorkoutBreakdown,
   WorkoutDetail,
+  WorkoutHRV,
   WorkoutLike,
   WorkoutListParams,
   WorkoutRangeStats,
   WorkoutRecord,
   WorkoutReply,
+  WorkoutTimeShift,
 } from '../../core/types/workout';
 import { Measurement } from '../../core/types/measurement';
 import { Equipment } from '../../core/types/equipment';
@@ -39,6 +51,7 @@ import {
   HeatmapCoordinateList,
   Statistics,
   StatisticsParams,
+  TrainingLoad,
 } from '../../core/types/statistics';
 import { RegisterRequest, SignInRequest } from '../../core/types/auth';
 
@@ -95,6 +108,18 @@ export class Api {
     if (params?.order_dir) {
       httpParams = httpParams.set('order_dir', params.order_dir);
     }
+    for (const key of [
+      'min_normalized_power',
+      'min_intensity_factor',
+      'max_intensity_factor',
+      'min_training_stress_score',
+      'max_training_stress_score',
+    ] as const) {
+      const value = params?.[key];
+      if (value) {
+        httpParams = httpParams.set(key, value.toString());
+      }
+    }
     return this.http.get<PaginatedAPIResponse<Workout>>(`${this.baseUrl}/workouts`, {
       params: httpParams,
     });
@@ -144,6 +169,25 @@ export class Api {
     );
   }
 
+  public getWorkoutHRV(
+    id: number,
+    params?: { window?: number; step?: number },
+  ): Observable<APIResponse<WorkoutHRV>> {
+    let httpParams = new HttpParams();
+
+    if (params?.window) {
+      httpParams = httpParams.set('window', params.window.toString());
+    }
+
+    if (params?.step) {
+      httpParams = httpParams.set('step', params.step.toString());
+    }
+
+    return this.http.get<APIResponse<WorkoutHRV>>(`${this.baseUrl}/workouts/${id}/hrv`, {
+      params: httpParams,
+    });
+  }
+
   public getRecentWorkouts(limit?: number, offset?: number): Observable<APIResponse<Workout[]>> {
     let httpParams = new HttpParams();
     if (limit) {
@@ -161,6 +205,24 @@ export class Api {
     return this.http.post<APIResponse<Workout[]>>(`${this.baseUrl}/workouts`, formData);
   }
 
+  public previewWorkoutImport(formData: FormData): Observable<APIResponse<ImportPreviewItem[]>> {
+    return this.http.post<APIResponse<ImportPreviewItem[]>>(
+      `${this.baseUrl}/workouts/preview`,
+      formData,
+    );
+  }
+
+  // Creates only the selected previewed workouts; formData holds the same
+  // files as the preview
+  public confirmWorkoutImport(
+    formData: FormData,
+    items: ImportSelection[],
+  ): Observable<APIResponse<Workout[]>> {
+    formData.set('items', JSON.stringify(items));
+
+    return this.http.post<APIResponse<Workout[]>>(`${this.baseUrl}/workouts`, formData);
+  }
+
   public createWorkoutManual(workout: {
     name: string;
     date: string;
@@ -219,6 +281,104 @@ export class Api {
     );
   }
 
+  public getDuplicateWorkouts(): Observable<APIResponse<DuplicatePair[]>> {
+    return this.http.get<APIResponse<DuplicatePair[]>>(`${this.baseUrl}/workouts/duplicates`);
+  }
+
+  // Merges two recordings of the same activity; the one with the best GPS
+  // track is kept and the other one is deleted
+  public mergeWorkout(id: number, otherId: number): Observable<APIResponse<MergeWorkoutResult>> {
+    return this.http.post<APIResponse<MergeWorkoutResult>>(`${this.baseUrl}/workouts/${id}/merge`, {
+      workout_id: otherId,
+    });
+  }
+
+  // Merges the sensor streams of another file (e.g. of a heart rate strap)
+  // into the workout; the form data has the file, and optionally the
+  // comma separated metrics and an offset in seconds
+  public mergeWorkoutSensors(
+    id: number,
+    formData: FormData,
+  ): Observable<APIResponse<MergeSensorsResult>> {
+    return this.http.post<APIResponse<MergeSensorsResult>>(
+      `${this.baseUrl}/workouts/${id}/sensors`,
+      formData,
+    );
+  }
+
+  // Point indexes are those of the workout's map data details
+  public trimWorkout(
+    id: number,
+    startIndex: number,
+    endIndex: number,
+  ): Observable<APIResponse<Workout>> {
+    return this.http.post<APIResponse<Workout>>(`${this.baseUrl}/workouts/${id}/trim`, {
+      start_index: startIndex,
+      end_index: endIndex,
+    });
+  }
+
+  public removeWorkoutPoints(
+    id: number,
+    startIndex: number,
+    endIndex: number,
+  ): Observable<APIResponse<Workout>> {
+    return this.http.post<APIResponse<Workout>>(`${this.baseUrl}/workouts/${id}/remove-points`, {
+      start_index: startIndex,
+      end_index: endIndex,
+    });
+  }
+
+  public splitWorkout(id: number, index: number): Observable<APIResponse<Workout[]>> {
+    return this.http.post<APIResponse<Workout[]>>(`${this.baseUrl}/workouts/${id}/split`, {
+      index,
+    });
+  }
+
+  public revertWorkoutEdits(id: number): Observable<APIResponse<Workout>> {
+    return this.http.post<APIResponse<Workout>>(`${this.baseUrl}/workouts/${id}/revert-edits`, {});
+  }
+
+  public correctWorkoutElevation(
+    id: number,
+    mode: ElevationCorrection,
+  ): Observable<APIResponse<Workout>> {
+    return this.http.post<APIResponse<Workout>>(
+      `${this.baseUrl}/workouts/${id}/elevation-correction`,
+      { mode },
+    );
+  }
+
+  public shiftWorkoutTime(
+    id: number,
+    shift: WorkoutTimeShift,
+  ): Observable<APIResponse<Workout>> {
+    return this.http.post<APIResponse<Workout>>(
+      `${this.baseUrl}/workouts/${id}/time-shift`,
+      shift,
+    );
+  }
+
+  public shiftWorkoutsTime(
+    workoutIds: number[],
+    shift: WorkoutTimeShift,
+  ): Observable<APIResponse<Workout[]>> {
+    return this.http.post<APIResponse<Workout[]>>(
+      `${this.baseUrl}/workouts/time-shift`,
+      { ...shift, workout_ids: workoutIds },
+    );
+  }
+
+  public setWorkoutTimezone(
+    id: number,
+    timezone: string,
+  ): Observable<APIResponse<Workout>> {
+    return this.http.post<APIResponse<Workout>>(
+      `${this.baseUrl}/workouts/${id}/timezone`,
+      { timezone },
+    );
+  }
+
   public likeWorkout(id: number): Observable<
     APIResponse<{ workout_id: number; likes_count: number; liked: boolean }>
   > {
@@ -257,8 +417,14 @@ export class Api {
     );
   }
 
-  public downloadWorkout(id: number): Observable<HttpResponse<Blob>> {
+  public downloadWorkout(id: number, format?: string): Observable<HttpResponse<Blob>> {
+    let httpParams = new HttpParams();
+    if (format) {
+      httpParams = httpParams.set('format', format);
+    }
+
     return this.http.get(`${this.baseUrl}/workouts/${id}/download`, {
+      params: httpParams,
       observe: 'response',
       responseType: 'blob',
     });
@@ -286,6 +452,7 @@ export class Api {
     ftp?: number;
     resting_heart_rate?: number;
     max_heart_rate?: number;
+    lthr?: number;
   }): Observable<APIResponse<Measurement>> {
     return this.http.post<APIResponse<Measurement>>(`${this.baseUrl}/measurements`, measurement);
   }
@@ -468,6 +635,77 @@ export class Api {
     );
   }
 
+  public getEffortRecordRanking(params: {
+    workout_type: string;
+    metric: EffortMetric;
+    label: string;
+    handle?: string;
+    start?: string;
+    end?: string;
+    page?: number;
+    per_page?: number;
+  }): Observable<PaginatedAPIResponse<EffortRecordEntry>> {
+    let httpParams = new HttpParams()
+      .set('workout_type', params.workout_type)
+      .set('metric', params.metric)
+      .set('label', params.label);
+
+    if (params.start) {
+      httpParams = httpParams.set('start', params.start);
+    }
+
+    if (params.end) {
+      httpParams = httpParams.set('end', params.end);
+    }
+
+    if (params.page) {
+      httpParams = httpParams.set('page', params.page.toString());
+    }
+
+    if (params.per_page) {
+      httpParams = httpParams.set('per_page', params.per_page.toString());
+    }
+
+    if (params.handle) {
+      httpParams = httpParams.set('handle', params.handle);
+    }
+
+    return this.http.get<PaginatedAPIResponse<EffortRecordEntry>>(
+      `${this.baseUrl}/records/ranking`,
+      { params: httpParams },
+    );
+  }
+
+  public getEffortCurve(params: {
+    metric: EffortMetric;
+    workout_type?: string;
+    handle?: string;
+    start?: string;
+    end?: string;
+  }): Observable<APIResponse<EffortRecordEntry[]>> {
+    let httpParams = new HttpParams().set('metric', params.metric);
+
+    if (params.workout_type) {
+      httpParams = httpParams.set('workout_type', params.workout_type);
+    }
+
+    if (params.start) {
+      httpParams = httpParams.set('start', params.start);
+    }
+
+    if (params.end) {
+      httpParams = httpParams.set('end', params.end);
+    }
+
+    if (params.handle) {
+      httpParams = httpParams.set('handle', params.handle);
+    }
+
+    return this.http.get<APIResponse<EffortRecordEntry[]>>(`${this.baseUrl}/records/efforts`, {
+      params: httpParams,
+    });
+  }
+
   public getClimbRanking(params: {
     workout_type: string;
     handle?: string;
@@ -504,6 +742,51 @@ export class Api {
     );
   }
 
+  public getExerciseRanking(params: {
+    exercise: string;
+    metric?: 'one-rep-max' | 'max-weight' | 'volume';
+    handle?: string;
+    start?: string;
+    end?: string;
+    page?: number;
+    per_page?: number;
+  }): Observable<PaginatedAPIResponse<ExerciseEffort>> {
+    let httpParams = new HttpParams().set('exercise', params.exercise);
+
+    if (params.metric) {
+      httpParams = httpParams.set('metric', params.metric);
+    }
+
+    if (params.start) {
+      httpParams = httpParams.set('start', params.start);
+    }
+
+    if (params.end) {
+      httpParams = httpParams.set('end', params.end);
+    }
+
+    if (params.page) {
+      httpParams = httpParams.set('page', params.page.toString());
+    }
+
+    if (params.per_page) {
+      httpParams = httpParams.set('per_page', params.per_page.toString());
+    }
+
+    if (params.handle) {
+      httpParams = httpParams.set('handle', params.handle);
+    }
+
+    return this.http.get<PaginatedAPIResponse<ExerciseEffort>>(
+      `${this.baseUrl}/records/exercises/ranking`,
+      { params: httpParams },
+    );
+  }
+
+  public getExercises(): Observable<APIResponse<Exercise[]>> {
+    return this.http.get<APIResponse<Exercise[]>>(`${this.baseUrl}/exercises`);
+  }
+
   // Profile endpoints
   public getProfile(): Observable<APIResponse<FullUserProfile>> {
     return this.http.get<APIResponse<FullUserProfile>>(`${this.baseUrl}/profile`);
@@ -599,6 +882,15 @@ export class Api {
     return this.http.put<APIResponse<AppInfo>>(`${this.baseUrl}/admin/config`, config);
   }
 
+  public correctAllWorkoutsElevation(
+    mode: ElevationCorrection,
+  ): Observable<APIResponse<{ message: string }>> {
+    return this.http.post<APIResponse<{ message: string }>>(
+      `${this.baseUrl}/admin/elevation-correction`,
+      { mode },
+    );
+  }
+
   // Statistics endpoints
   public getStatistics(params?: StatisticsParams): Observable<APIResponse<Statistics>> {
     let httpParams = new HttpParams();
@@ -613,6 +905,16 @@ export class Api {
     });
   }
 
+  public getTrainingLoad(params?: Pick<StatisticsParams, 'since'>): Observable<APIResponse<TrainingLoad[]>> {
+    let httpParams = new HttpParams();
+    if (params?.since) {
+      httpParams = httpParams.set('since', params.since);
+    }
+    return this.http.get<APIResponse<TrainingLoad[]>>(`${this.baseUrl}/statistics/training-load`, {
+      params: httpParams,
+    });
+  }
+
   // Heatmap endpoints
   public getWorkoutsCoordinates(): Observable<APIResponse<HeatmapCoordinateList>> {
     return this.http.get<APIResponse<HeatmapCoordinateList>>(
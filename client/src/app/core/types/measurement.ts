@@ -11,6 +11,11 @@ export type Measurement = {
   ftp?: number;
   resting_heart_rate?: number;
   max_heart_rate?: number;
+  lthr?: number;
+  body_fat?: number;
+  muscle_mass?: number;
+  bone_mass?: number;
+  body_water?: number;
   user_id: number;
   created_at: string;
   updated_at: string;
@@ -6,6 +6,20 @@ export type Statistics = {
   user_id: number;
   bucket_format: string;
   buckets: Record<string, StatisticBuckets>;
+  heart_rate_zones?: WeeklyHeartRateZones[];
+};
+
+export type WeeklyHeartRateZones = {
+  week: string;
+  seconds: number[];
+};
+
+export type TrainingLoad = {
+  date: string;
+  load: number;
+  fitness: number;
+  fatigue: number;
+  form: number;
 };
 
 export type StatisticBuckets = {
@@ -35,6 +35,7 @@ export type UserPreferredUnits = {
   elevation: string;
   weight: string;
   height: string;
+  swim_pace?: string;
 };
 
 export type ProfileSettings = {
@@ -48,8 +49,13 @@ export type ProfileSettings = {
   api_active: boolean;
   api_key?: string;
   prefer_full_date: boolean;
+  clean_gps_tracks: boolean;
+  smooth_gps_tracks: boolean;
+  heart_rate_zone_model: HeartRateZoneModel;
 };
 
+export type HeartRateZoneModel = '' | 'percent-max' | 'threshold';
+
 export type FullUserProfile = {
   id: number;
   username: string;
@@ -83,6 +89,9 @@ export type ProfileUpdateRequest = {
   api_active: boolean;
   default_workout_visibility: '' | 'followers' | 'public';
   prefer_full_date: boolean;
+  clean_gps_tracks: boolean;
+  smooth_gps_tracks: boolean;
+  heart_rate_zone_model: HeartRateZoneModel;
 };
 
 export type AppConfig = {
@@ -18,6 +18,7 @@ export type Workout = {
   user?: UserProfile;
   visibility?: '' | 'followers' | 'public';
   locked: boolean;
+  commute: boolean;
   created_at: string;
   updated_at: string;
   has_file: boolean;
@@ -27,6 +28,7 @@ export type Workout = {
   liked_by_me: boolean;
   replies_count: number;
   attachments?: WorkoutAttachment[];
+  multisport_id?: number;
 
   // Optional map data
   address_string?: string;
@@ -48,6 +50,15 @@ export type Workout = {
   max_heart_rate?: number;
   average_power?: number;
   max_power?: number;
+  normalized_power?: number;
+  variability_index?: number;
+  intensity_factor?: number;
+  training_stress_score?: number;
+  grade_adjusted_speed?: number;
+  pace_decoupling?: number;
+  power_decoupling?: number;
+  training_load?: number;
+  training_load_method?: 'tss' | 'trimp' | 'met';
 };
 
 export type WorkoutAttachment = {
@@ -65,14 +76,81 @@ export type WorkoutDetail = {
   climbs?: ClimbSegment[];
   route_segment_matches?: RouteSegmentMatch[];
   records?: WorkoutIntervalRecord[];
+  efforts?: WorkoutEffortRecord[];
   laps?: WorkoutLap[];
+  swim?: SwimSummary;
+  sets?: WorkoutSet[];
+  exercises?: ExerciseEffort[];
+  duplicate_ids?: number[];
+  edited: boolean;
+  split: boolean;
+  elevation_correction: ElevationCorrection;
+  time_offset_seconds: number;
+  timezone_override: string;
+  heart_rate_zones?: HeartRateZones;
+  power_zones?: PowerZones;
 } & Workout;
 
+export type ElevationCorrection = '' | 'replace' | 'blend';
+
+export type WorkoutTimeShift = {
+  offset_seconds?: number;
+  zone?: string;
+};
+
+export type Exercise = {
+  id: number;
+  name: string;
+  category?: string;
+};
+
+export type WorkoutSet = {
+  exercise: string;
+  category?: string;
+  start: string;
+  duration: number;
+  rest: number;
+  repetitions: number;
+  weight: number;
+  rpe?: number;
+  volume: number;
+  estimated_one_rep_max?: number;
+};
+
+export type ExerciseEffort = {
+  exercise: string;
+  category?: string;
+  workout_id: number;
+  date: string;
+  sets: number;
+  repetitions: number;
+  max_weight: number;
+  one_rep_max: number;
+  volume: number;
+};
+
+export type SwimSummary = {
+  pool_length: number;
+  lengths: number;
+  intervals: number;
+  distance: number;
+  duration: number;
+  rest: number;
+  strokes: number;
+  average_swolf: number;
+  stroke_rate: number;
+  strokes_per_length: number;
+  swim_pace: number;
+  swim_pace_unit: string;
+};
+
 export type MapData = {
   creator: string;
   center: MapCenter;
   extra_metrics?: string[];
   details?: MapDataDetails;
+  corrected_points?: number;
+  dem_points?: number;
 };
 
 export type MapCenter = {
@@ -100,6 +178,18 @@ export type ZoneRangeDefinition = {
   max?: number | null;
 };
 
+export type HeartRateZones = {
+  model: string;
+  ranges: ZoneRangeDefinition[];
+  seconds: number[];
+};
+
+export type PowerZones = {
+  ftp: number;
+  ranges: ZoneRangeDefinition[];
+  seconds: number[];
+};
+
 export type ZoneRangeMap = {
   'heart-rate'?: ZoneRangeDefinition[];
   power?: ZoneRangeDefinition[];
@@ -125,6 +215,10 @@ export type WorkoutLap = {
   max_heart_rate: number;
   average_power: number;
   max_power: number;
+  normalized_power: number;
+  grade_adjusted_speed: number;
+  pace_decoupling: number;
+  power_decoupling: number;
 };
 
 export type WorkoutBreakdownItem = {
@@ -146,13 +240,33 @@ export type WorkoutBreakdownItem = {
   max_heart_rate: number;
   average_power: number;
   max_power: number;
+  grade_adjusted_speed?: number;
+  grade_adjusted_pace?: number;
+  pace_decoupling?: number;
+  power_decoupling?: number;
+  extra_metrics?: Record<string, MetricStats>;
+  heart_rate_zones?: number[];
+  lengths?: number;
+  stroke?: string;
+  strokes?: number;
+  swolf?: number;
+  stroke_rate?: number;
+  swim_pace?: number;
+  rest?: number;
   is_best?: boolean;
   is_worst?: boolean;
 };
 
+export type MetricStats = {
+  average: number;
+  min: number;
+  max: number;
+};
+
 export type WorkoutBreakdown = {
-  mode: 'laps' | 'unit';
+  mode: 'laps' | 'unit' | 'lengths' | 'intervals';
   items?: WorkoutBreakdownItem[];
+  swim_pace_unit?: string;
 };
 
 export type WorkoutRangeStatsUnits = {
@@ -201,13 +315,48 @@ export type WorkoutRangeStats = {
   min_power?: number;
   max_power?: number;
 
+  normalized_power?: number;
+  variability_index?: number;
+
+  grade_adjusted_speed?: number;
+  pace_decoupling?: number;
+  power_decoupling?: number;
+
   average_temperature?: number;
   min_temperature?: number;
   max_temperature?: number;
 
+  extra_metrics?: Record<string, MetricStats>;
+
+  heart_rate_zones?: HeartRateZones;
+  power_zones?: PowerZones;
+
   units: WorkoutRangeStatsUnits;
 };
 
+export type WorkoutHRVWindow = {
+  start: string;
+  offset: number;
+  beats: number;
+  artifacts: number;
+  heart_rate: number;
+  rmssd: number;
+  sdnn: number;
+  dfa_alpha1?: number;
+};
+
+export type WorkoutHRV = {
+  beats: number;
+  artifacts: number;
+  mean_rr: number;
+  heart_rate: number;
+  rmssd: number;
+  sdnn: number;
+  dfa_alpha1: number;
+  window: number;
+  windows: WorkoutHRVWindow[];
+};
+
 export type ClimbSegment = {
   index: number;
   type: string;
@@ -259,6 +408,19 @@ export type WorkoutIntervalRecord = {
   rank: number;
 };
 
+export type EffortMetric = 'power' | 'speed' | 'heart-rate';
+
+export type WorkoutEffortRecord = {
+  metric: EffortMetric;
+  label: string;
+  duration_seconds: number;
+  value: number;
+  distance: number;
+  start_index: number;
+  end_index: number;
+  rank: number;
+};
+
 export type Equipment = {
   id: number;
   name: string;
@@ -297,6 +459,18 @@ export type DistanceRecordEntry = {
   end_index?: number;
 };
 
+export type EffortRecordEntry = {
+  metric: EffortMetric;
+  label: string;
+  duration_seconds: number;
+  value: number;
+  distance: number;
+  workout_id: number;
+  date: string;
+  start_index?: number;
+  end_index?: number;
+};
+
 export type ClimbRecordEntry = {
   elevation_gain: number;
   distance: number;
@@ -318,6 +492,56 @@ export type WorkoutRecord = {
   total_up?: RecordEntry;
   distance_records?: DistanceRecordEntry[];
   biggest_climb?: ClimbRecordEntry;
+  exercise_records?: ExerciseRecordEntry[];
+};
+
+export type ExerciseRecordEntry = {
+  exercise: string;
+  category?: string;
+  one_rep_max?: RecordEntry;
+  max_weight?: RecordEntry;
+  volume?: RecordEntry;
+};
+
+export type ImportPreviewItem = {
+  file_index: number;
+  index: number;
+  filename: string;
+  name: string;
+  date: string;
+  type: string;
+  sub_type?: string;
+  total_distance: number;
+  total_duration: number;
+  multisport: boolean;
+  duplicate: boolean;
+};
+
+export type ImportSelection = {
+  file_index: number;
+  index: number;
+  name?: string;
+  type?: string;
+  visibility?: '' | 'followers' | 'public';
+  equipment_ids?: number[];
+};
+
+export type DuplicatePair = {
+  workout: Workout;
+  other: Workout;
+  overlap: number;
+};
+
+export type MergeWorkoutResult = {
+  workout: Workout;
+  deleted_workout_id: number;
+};
+
+export type SensorMetric = 'heart-rate' | 'power' | 'cadence' | 'temperature';
+
+export type MergeSensorsResult = {
+  workout: Workout;
+  metrics: SensorMetric[];
 };
 
 export type CalendarEvent = {
@@ -333,6 +557,11 @@ export type WorkoutListParams = PaginationParams & {
   since?: string;
   order_by?: string;
   order_dir?: string;
+  min_normalized_power?: number;
+  min_intensity_factor?: number;
+  max_intensity_factor?: number;
+  min_training_stress_score?: number;
+  max_training_stress_score?: number;
 };
 
 export type WorkoutReply = {
@@ -42,6 +42,7 @@ export class Measurements extends PaginatedListView<Measurement> {
     ftp: null as number | null,
     resting_heart_rate: null as number | null,
     max_heart_rate: null as number | null,
+    lthr: null as number | null,
   });
 
   // Form update helpers
@@ -80,6 +81,11 @@ export class Measurements extends PaginatedListView<Measurement> {
     this.measurementForm.set({ ...form, max_heart_rate: value ? parseFloat(value) : null });
   }
 
+  public updateFormLTHR(value: string): void {
+    const form = this.measurementForm();
+    this.measurementForm.set({ ...form, lthr: value ? parseFloat(value) : null });
+  }
+
   public async loadData(page?: number): Promise<void> {
     if (page) {
       this.currentPage.set(page);
@@ -129,6 +135,7 @@ export class Measurements extends PaginatedListView<Measurement> {
       ftp: null,
       resting_heart_rate: null,
       max_heart_rate: null,
+      lthr: null,
     });
     this.showCreateModal.set(true);
   }
@@ -153,6 +160,7 @@ export class Measurements extends PaginatedListView<Measurement> {
         ftp?: number;
         resting_heart_rate?: number;
         max_heart_rate?: number;
+        lthr?: number;
       } = { date: form.date };
       if (form.weight !== null && form.weight > 0) {
         payload.weight = form.weight;
@@ -172,6 +180,9 @@ export class Measurements extends PaginatedListView<Measurement> {
       if (form.max_heart_rate !== null && form.max_heart_rate > 0) {
         payload.max_heart_rate = form.max_heart_rate;
       }
+      if (form.lthr !== null && form.lthr > 0) {
+        payload.lthr = form.lthr;
+      }
 
       await firstValueFrom(this.api.createOrUpdateMeasurement(payload));
       this.closeCreateModal();
@@ -192,6 +203,7 @@ export class Measurements extends PaginatedListView<Measurement> {
       ftp: measurement.ftp || null,
       resting_heart_rate: measurement.resting_heart_rate || null,
       max_heart_rate: measurement.max_heart_rate || null,
+      lthr: measurement.lthr || null,
     });
     this.showEditModal.set(true);
   }
@@ -217,6 +229,7 @@ export class Measurements extends PaginatedListView<Measurement> {
         ftp?: number;
         resting_heart_rate?: number;
         max_heart_rate?: number;
+        lthr?: number;
       } = { date: form.date };
       if (form.weight !== null && form.weight > 0) {
         payload.weight = form.weight;
@@ -236,6 +249,9 @@ export class Measurements extends PaginatedListView<Measurement> {
       if (form.max_heart_rate !== null && form.max_heart_rate > 0) {
         payload.max_heart_rate = form.max_heart_rate;
       }
+      if (form.lthr !== null && form.lthr > 0) {
+        payload.lthr = form.lthr;
+      }
 
       await firstValueFrom(this.api.createOrUpdateMeasurement(payload));
       this.closeEditModal();
@@ -32,6 +32,9 @@ export class ProfileStore {
     theme: ['browser'],
     auto_import_directory: [''],
     prefer_full_date: [false],
+    clean_gps_tracks: [false],
+    smooth_gps_tracks: [false],
+    heart_rate_zone_model: [''],
     default_workout_visibility: [''],
     preferred_units: this.fb.group({
       speed: ['km/h'],
@@ -39,6 +42,7 @@ export class ProfileStore {
       elevation: ['m'],
       weight: ['kg'],
       height: ['cm'],
+      swim_pace: [''],
     }),
   });
 
@@ -66,6 +70,9 @@ export class ProfileStore {
           theme: response.results.profile.theme,
           auto_import_directory: response.results.profile.auto_import_directory,
           prefer_full_date: response.results.profile.prefer_full_date,
+          clean_gps_tracks: response.results.profile.clean_gps_tracks,
+          smooth_gps_tracks: response.results.profile.smooth_gps_tracks,
+          heart_rate_zone_model: response.results.profile.heart_rate_zone_model,
           default_workout_visibility: response.results.profile.default_workout_visibility,
           preferred_units: response.results.profile.preferred_units,
         });
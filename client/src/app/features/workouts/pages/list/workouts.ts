@@ -84,6 +84,10 @@ export class Workouts extends PaginatedListView<Workout> {
     { value: 'total_down', label: this.translate.stream('Elev Down') },
     { value: 'average_speed_no_pause', label: this.translate.stream('Average speed no pause') },
     { value: 'max_speed', label: this.translate.stream('Max speed') },
+    { value: 'average_power', label: this.translate.stream('Average power') },
+    { value: 'normalized_power', label: this.translate.stream('Normalized power') },
+    { value: 'intensity_factor', label: this.translate.stream('Intensity factor') },
+    { value: 'training_stress_score', label: this.translate.stream('Training stress score') },
   ];
 
   public readonly orderDirOptions: FilterOption[] = [
@@ -137,6 +141,22 @@ export class Workouts extends PaginatedListView<Workout> {
         const speed = this.formatSpeed(workout.max_speed);
         return speed !== null ? `${speed} km/h` : '-';
       }
+      case 'average_power': {
+        const formatted = this.formatNumber(workout.average_power, { maximumFractionDigits: 0 });
+        return formatted !== null ? `${formatted} W` : '-';
+      }
+      case 'normalized_power': {
+        const formatted = this.formatNumber(workout.normalized_power, { maximumFractionDigits: 0 });
+        return formatted !== null ? `${formatted} W` : '-';
+      }
+      case 'intensity_factor': {
+        const formatted = this.formatNumber(workout.intensity_factor, { maximumFractionDigits: 2 });
+        return formatted !== null ? formatted : '-';
+      }
+      case 'training_stress_score': {
+        const formatted = this.formatNumber(workout.training_stress_score, { maximumFractionDigits: 0 });
+        return formatted !== null ? formatted : '-';
+      }
       default:
         return '-';
     }
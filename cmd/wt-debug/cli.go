@@ -34,6 +34,7 @@ func newCLI() (*cli, error) {
 	}
 
 	a.ConfigureGeocoder()
+	a.ConfigureDEM()
 
 	c := &cli{
 		app: a,
@@ -81,6 +81,14 @@ func GenerateWorkoutFIT(workout *model.Workout) ([]byte, error) {
 		session.SetMaxPower(clampUint16(math.Round(workout.Data.MaxPower)))
 	}
 
+	if workout.Data.AverageHeartRate > 0 {
+		session.SetAvgHeartRate(clampUint8(math.Round(workout.Data.AverageHeartRate)))
+	}
+
+	if workout.Data.MaxHeartRate > 0 {
+		session.SetMaxHeartRate(clampUint8(math.Round(workout.Data.MaxHeartRate)))
+	}
+
 	activity.Sessions = append(activity.Sessions, session)
 	activity.Activity = mesgdef.NewActivity(nil).
 		SetType(typedef.ActivityManual).
@@ -181,6 +189,14 @@ func buildWorkoutRecords(workout *model.Workout, fallbackStart time.Time) []*mes
 			rec.SetPower(clampUint16(math.Round(power)))
 		}
 
+		if hr, ok := p.ExtraMetrics["heart-rate"]; ok && hr > 0 {
+			rec.SetHeartRate(clampUint8(math.Round(hr)))
+		}
+
+		if temperature, ok := p.ExtraMetrics["temperature"]; ok && !math.IsNaN(temperature) {
+			rec.SetTemperature(clampInt8(math.Round(temperature)))
+		}
+
 		records = append(records, rec)
 	}
 
@@ -238,6 +254,14 @@ func buildWorkoutLaps(workout *model.Workout, start, end time.Time) []*mesgdef.L
 			l.SetMaxPower(clampUint16(math.Round(lap.MaxPower)))
 		}
 
+		if lap.AverageHeartRate > 0 {
+			l.SetAvgHeartRate(clampUint8(math.Round(lap.AverageHeartRate)))
+		}
+
+		if lap.MaxHeartRate > 0 {
+			l.SetMaxHeartRate(clampUint8(math.Round(lap.MaxHeartRate)))
+		}
+
 		laps = append(laps, l)
 	}
 
@@ -313,3 +337,20 @@ func clampUint8(v float64) uint8 {
 
 	return uint8(v)
 }
+
+func clampInt8(v float64) int8 {
+	if math.IsNaN(v) {
+		return 0
+	}
+
+	// math.MaxInt8 is the FIT "invalid" marker for sint8 fields
+	if v >= math.MaxInt8 {
+		return math.MaxInt8 - 1
+	}
+
+	if v <= math.MinInt8 {
+		return math.MinInt8
+	}
+
+	return int8(v)
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/fsouza/slognil"
 	"github.com/invopop/ctxi18n/i18n"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/dem"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/geocoder"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
 	_ "github.com/jovandeginste/workout-tracker/v2/pkg/model/migrations"
@@ -75,6 +76,11 @@ func (a *App) Configure() error {
 	a.repositories = repository.New(a.db)
 
 	a.ConfigureGeocoder()
+	a.ConfigureDEM()
+
+	if err := model.SetTrackLimits(a.Config.TrackLimits); err != nil {
+		return err
+	}
 
 	if err := model.InitTZFinder(); err != nil {
 		return err
@@ -100,6 +106,14 @@ func (a *App) ConfigureGeocoder() {
 	geocoder.SetClient(a.logger, a.Version.UserAgent())
 }
 
+func (a *App) ConfigureDEM() {
+	if a.Config.DEMDirectory != "" {
+		a.logger.Info("Using elevation model files from '" + a.Config.DEMDirectory + "'")
+	}
+
+	dem.SetDirectory(a.Config.DEMDirectory)
+}
+
 func (a *App) ConfigureDatabase() error {
 	a.Config.SetDSN(a.logger)
 
@@ -236,6 +236,8 @@ func (a *App) apiV2Routes(e *echo.Group) {
 	a.registerStatisticsController(apiGroup)
 	a.registerProfileController(apiGroup)
 	a.registerAdminController(apiGroup)
+	a.registerArchiveImportController(apiGroup)
+	a.registerMultisportController(apiGroup)
 
 	apiGroup.POST("/lookup-address", a.apiV2LookupAddressHandler).Name = "lookup-address"
 }
@@ -38,6 +38,8 @@ func (a *App) registerUserController(apiGroup *echo.Group) {
 	apiGroup.GET("/records", uc.GetRecords).Name = "records"
 	apiGroup.GET("/records/climbs/ranking", uc.GetClimbRecordsRanking).Name = "records-climbs-ranking"
 	apiGroup.GET("/records/ranking", uc.GetRecordsRanking).Name = "records-ranking"
+	apiGroup.GET("/records/efforts", uc.GetEffortCurve).Name = "records-efforts"
+	apiGroup.GET("/records/exercises/ranking", uc.GetExerciseRecordsRanking).Name = "records-exercises-ranking"
 	apiGroup.GET("/:id", uc.GetUserByID).Name = "user-show"
 }
 
@@ -54,6 +56,7 @@ func (a *App) registerStatisticsController(apiGroup *echo.Group) {
 	sc := controller.NewStatisticsController(&a.container)
 
 	apiGroup.GET("/statistics", sc.GetStatistics).Name = "statistics"
+	apiGroup.GET("/statistics/training-load", sc.GetTrainingLoad).Name = "statistics-training-load"
 }
 
 func (a *App) registerProfileController(apiGroup *echo.Group) {
@@ -84,6 +87,7 @@ func (a *App) registerAdminController(apiGroup *echo.Group) {
 	adminGroup.PUT("/users/:id", ac.UpdateUser).Name = "admin-user-update"
 	adminGroup.DELETE("/users/:id", ac.DeleteUser).Name = "admin-user-delete"
 	adminGroup.PUT("/config", ac.UpdateConfig).Name = "admin-config-update"
+	adminGroup.POST("/elevation-correction", ac.CorrectElevation).Name = "admin-elevation-correction"
 }
 
 func (a *App) registerEquipmentController(apiGroup *echo.Group) {
@@ -99,15 +103,21 @@ func (a *App) registerEquipmentController(apiGroup *echo.Group) {
 func (a *App) registerWorkoutController(apiGroup *echo.Group) {
 	wc := controller.NewWorkoutController(&a.container)
 
+	apiGroup.GET("/exercises", wc.GetExercises).Name = "exercises-list"
+
 	workoutGroup := apiGroup.Group("/workouts")
 	workoutGroup.GET("", wc.GetWorkouts).Name = "workouts-list"
 	workoutGroup.POST("", wc.CreateWorkout).Name = "workouts-create"
+	workoutGroup.POST("/preview", wc.PreviewWorkouts).Name = "workouts-preview"
 	workoutGroup.GET("/recent", wc.GetRecentWorkouts).Name = "workouts-recent"
 	workoutGroup.GET("/calendar", wc.GetWorkoutCalendar).Name = "workouts-calendar"
+	workoutGroup.GET("/duplicates", wc.GetDuplicateWorkouts).Name = "workouts-duplicates"
+	workoutGroup.POST("/time-shift", wc.ShiftWorkoutsTime).Name = "workouts-time-shift"
 	workoutGroup.GET("/:id", wc.GetWorkout).Name = "workout-get"
 	workoutGroup.GET("/:id/likes", wc.GetWorkoutLikes).Name = "workout-likes"
 	workoutGroup.GET("/:id/breakdown", wc.GetWorkoutBreakdown).Name = "workout-breakdown"
 	workoutGroup.GET("/:id/stats-range", wc.GetWorkoutRangeStats).Name = "workout-range-stats"
+	workoutGroup.GET("/:id/hrv", wc.GetWorkoutHRV).Name = "workout-hrv"
 	workoutGroup.GET("/:id/replies", wc.GetWorkoutReplies).Name = "workout-replies"
 	workoutGroup.POST("/:id/like", wc.LikeWorkout).Name = "workout-like"
 	workoutGroup.POST("/like", wc.LikeWorkoutByObject).Name = "workout-like-object"
@@ -117,6 +127,15 @@ func (a *App) registerWorkoutController(apiGroup *echo.Group) {
 	workoutGroup.PUT("/:id", wc.UpdateWorkout).Name = "workout-update"
 	workoutGroup.POST("/:id/toggle-lock", wc.ToggleWorkoutLock).Name = "workout-toggle-lock"
 	workoutGroup.POST("/:id/refresh", wc.RefreshWorkout).Name = "workout-refresh"
+	workoutGroup.POST("/:id/merge", wc.MergeWorkout).Name = "workout-merge"
+	workoutGroup.POST("/:id/sensors", wc.MergeWorkoutSensors).Name = "workout-merge-sensors"
+	workoutGroup.POST("/:id/trim", wc.TrimWorkout).Name = "workout-trim"
+	workoutGroup.POST("/:id/remove-points", wc.RemoveWorkoutPoints).Name = "workout-remove-points"
+	workoutGroup.POST("/:id/split", wc.SplitWorkout).Name = "workout-split"
+	workoutGroup.POST("/:id/revert-edits", wc.RevertWorkoutEdits).Name = "workout-revert-edits"
+	workoutGroup.POST("/:id/elevation-correction", wc.CorrectWorkoutElevation).Name = "workout-elevation-correction"
+	workoutGroup.POST("/:id/time-shift", wc.ShiftWorkoutTime).Name = "workout-time-shift"
+	workoutGroup.POST("/:id/timezone", wc.SetWorkoutTimezone).Name = "workout-timezone"
 	workoutGroup.DELETE("/:id", wc.DeleteWorkout).Name = "workout-delete"
 }
 
@@ -149,3 +168,20 @@ func (a *App) registerRouteSegmentController(apiGroup *echo.Group) {
 	routeSegmentsGroup.GET("/:id/download", rsc.DownloadRouteSegment).Name = "route-segment-download"
 	apiGroup.POST("/workouts/:id/route-segment", rsc.CreateRouteSegmentFromWorkout).Name = "workout-route-segment-create"
 }
+
+func (a *App) registerArchiveImportController(apiGroup *echo.Group) {
+	ac := controller.NewArchiveImportController(&a.container)
+
+	importsGroup := apiGroup.Group("/imports")
+	importsGroup.GET("", ac.GetArchiveImports).Name = "imports-list"
+	importsGroup.POST("", ac.CreateArchiveImport).Name = "import-create"
+	importsGroup.GET("/:id", ac.GetArchiveImport).Name = "import-get"
+}
+
+func (a *App) registerMultisportController(apiGroup *echo.Group) {
+	mc := controller.NewMultisportController(&a.container)
+
+	multisportsGroup := apiGroup.Group("/multisports")
+	multisportsGroup.GET("", mc.GetMultisports).Name = "multisports-list"
+	multisportsGroup.GET("/:id", mc.GetMultisport).Name = "multisport-get"
+}
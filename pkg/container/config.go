@@ -47,6 +47,9 @@ func (c *Config) Load() error {
 	viper.SetDefault("worker_delay_seconds", 60)
 	viper.SetDefault("auto_import_enabled", false)
 	viper.SetDefault("activity_pub_active", false)
+	viper.SetDefault("import_directory", "")
+	viper.SetDefault("dem_directory", "")
+	viper.SetDefault("track_limits", "")
 
 	for _, envVar := range []string{
 		"host",
@@ -65,6 +68,9 @@ func (c *Config) Load() error {
 		"worker_delay_seconds",
 		"auto_import_enabled",
 		"activity_pub_active",
+		"import_directory",
+		"dem_directory",
+		"track_limits",
 	} {
 		if err := viper.BindEnv(envVar); err != nil {
 			return err
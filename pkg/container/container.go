@@ -88,6 +88,14 @@ func (c *Container) APOutboxDeliveryRepo() repository.APOutboxDelivery {
 	return c.repositories.APOutboxDelivery
 }
 
+func (c *Container) ArchiveImportRepo() repository.ArchiveImport {
+	if c.repositories == nil {
+		return nil
+	}
+
+	return c.repositories.ArchiveImport
+}
+
 func (c *Container) FollowerRepo() repository.Follower {
 	if c.repositories == nil {
 		return nil
@@ -120,6 +128,14 @@ func (c *Container) MeasurementRepo() repository.Measurement {
 	return c.repositories.Measurement
 }
 
+func (c *Container) MultisportRepo() repository.Multisport {
+	if c.repositories == nil {
+		return nil
+	}
+
+	return c.repositories.Multisport
+}
+
 func (c *Container) WorkoutRepo() repository.Workout {
 	if c.repositories == nil {
 		return nil
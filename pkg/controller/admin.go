@@ -5,7 +5,9 @@ import (
 	"strconv"
 
 	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model/dto"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/worker"
 	"github.com/labstack/echo/v4"
 )
 
@@ -15,6 +17,7 @@ type AdminController interface {
 	UpdateUser(c echo.Context) error
 	DeleteUser(c echo.Context) error
 	UpdateConfig(c echo.Context) error
+	CorrectElevation(c echo.Context) error
 }
 
 type adminController struct {
@@ -211,3 +214,39 @@ func (ac *adminController) UpdateConfig(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, resp)
 }
+
+// CorrectElevation changes how the elevation of all workouts is corrected
+// with the elevation model, in the background (admin only)
+// @Summary      Correct elevation of all workouts (admin)
+// @Tags         admin
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        data  body  dto.ElevationCorrectionRequest  true  "Elevation correction"
+// @Accept       json
+// @Produce      json
+// @Success      202  {object}  dto.Response[map[string]string]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /admin/elevation-correction [post]
+func (ac *adminController) CorrectElevation(c echo.Context) error {
+	var req dto.ElevationCorrectionRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	mode := model.ElevationCorrection(req.Mode)
+	if err := mode.Validate(); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := worker.EnqueueElevationCorrection(c.Request().Context(), ac.context, mode); err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[map[string]string]{
+		Results: map[string]string{"message": "The elevation of all workouts will be corrected soon"},
+	}
+
+	return c.JSON(http.StatusAccepted, resp)
+}
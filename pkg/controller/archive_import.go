@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"os"
+	"strconv"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/importers"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model/dto"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/worker"
+	"github.com/labstack/echo/v4"
+)
+
+type ArchiveImportController interface {
+	GetArchiveImports(c echo.Context) error
+	GetArchiveImport(c echo.Context) error
+	CreateArchiveImport(c echo.Context) error
+}
+
+type archiveImportController struct {
+	context *container.Container
+}
+
+func NewArchiveImportController(c *container.Container) ArchiveImportController {
+	return &archiveImportController{context: c}
+}
+
+// GetArchiveImports returns the archive imports of the current user
+// @Summary      List archive imports
+// @Tags         imports
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Produce      json
+// @Success      200  {object}  dto.Response[[]dto.ArchiveImportResponse]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /imports [get]
+func (ac *archiveImportController) GetArchiveImports(c echo.Context) error {
+	user := ac.context.GetUser(c)
+
+	archiveImports, err := ac.context.ArchiveImportRepo().ListByUserID(user.ID)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[[]dto.ArchiveImportResponse]{
+		Results: dto.NewArchiveImportsResponse(archiveImports),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetArchiveImport returns the progress of a single archive import
+// @Summary      Get archive import
+// @Tags         imports
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id   path  int  true  "Archive import ID"
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.ArchiveImportResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /imports/{id} [get]
+func (ac *archiveImportController) GetArchiveImport(c echo.Context) error {
+	user := ac.context.GetUser(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	archiveImport, err := ac.context.ArchiveImportRepo().GetByUserID(user.ID, id)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	resp := dto.Response[dto.ArchiveImportResponse]{
+		Results: dto.NewArchiveImportResponse(archiveImport),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CreateArchiveImport uploads a bulk export archive and imports it in the background
+// @Summary      Import export archive
+// @Tags         imports
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Accept       multipart/form-data
+// @Param        file     formData  file    true  "Export archive"
+// @Param        program  formData  string  true  "Program the archive was exported from" Enums(strava, garmin, apple-health)
+// @Produce      json
+// @Success      202  {object}  dto.Response[dto.ArchiveImportResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /imports [post]
+func (ac *archiveImportController) CreateArchiveImport(c echo.Context) error {
+	user := ac.context.GetUser(c)
+
+	program := importers.ArchiveProgram(c.FormValue("program"))
+	if !program.IsValid() {
+		return renderApiError(c, http.StatusBadRequest, fmt.Errorf("%w: %q", importers.ErrUnsupportedArchive, program))
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, errors.New("no file uploaded"))
+	}
+
+	archivePath, err := ac.storeArchive(file)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	archiveImport := &model.ArchiveImport{
+		UserID:   user.ID,
+		Program:  string(program),
+		Filename: file.Filename,
+		Path:     archivePath,
+		Status:   model.ArchiveImportStatusPending,
+	}
+
+	if err := ac.context.ArchiveImportRepo().Save(archiveImport); err != nil {
+		_ = os.Remove(archivePath)
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	if err := worker.EnqueueArchiveImport(c.Request().Context(), ac.context, archiveImport.ID); err != nil {
+		_ = ac.context.ArchiveImportRepo().Delete(archiveImport)
+		_ = os.Remove(archivePath)
+
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[dto.ArchiveImportResponse]{
+		Results: dto.NewArchiveImportResponse(archiveImport),
+	}
+
+	return c.JSON(http.StatusAccepted, resp)
+}
+
+// storeArchive copies the uploaded archive to the import directory; archives
+// can be several gigabytes, so they are not kept in memory or in the database
+func (ac *archiveImportController) storeArchive(file *multipart.FileHeader) (string, error) {
+	dir := ac.context.GetConfig().ImportDirectory
+	if dir == "" {
+		dir = os.TempDir()
+	}
+
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return "", err
+	}
+
+	src, err := file.Open()
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+
+	dst, err := os.CreateTemp(dir, "archive-*.zip")
+	if err != nil {
+		return "", err
+	}
+
+	if _, err := io.Copy(dst, src); err != nil {
+		dst.Close()
+		_ = os.Remove(dst.Name())
+
+		return "", err
+	}
+
+	if err := dst.Close(); err != nil {
+		_ = os.Remove(dst.Name())
+		return "", err
+	}
+
+	return dst.Name(), nil
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model/dto"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/worker"
 	"github.com/labstack/echo/v4"
 )
 
@@ -88,17 +89,11 @@ func (mc *measurementController) CreateMeasurement(c echo.Context) error {
 		return renderApiError(c, http.StatusBadRequest, err)
 	}
 
-	m, err := mc.context.MeasurementRepo().GetByUserIDForDateOrNew(user.ID, d.Time())
+	m, err := worker.SaveMeasurement(c.Request().Context(), mc.context, user, d.Time(), d.Update)
 	if err != nil {
 		return renderApiError(c, http.StatusInternalServerError, err)
 	}
 
-	d.Update(m)
-
-	if err := mc.context.MeasurementRepo().Save(m); err != nil {
-		return renderApiError(c, http.StatusInternalServerError, err)
-	}
-
 	resp := dto.Response[dto.MeasurementResponse]{
 		Results: dto.NewMeasurementResponse(m),
 	}
@@ -136,5 +131,9 @@ func (mc *measurementController) DeleteMeasurement(c echo.Context) error {
 		return renderApiError(c, http.StatusInternalServerError, err)
 	}
 
+	if err := worker.RefreshMeasurementWorkouts(c.Request().Context(), mc.context, u, m, u.NewMeasurement(time.Time(m.Date))); err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
 	return c.NoContent(http.StatusNoContent)
 }
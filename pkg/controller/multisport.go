@@ -0,0 +1,81 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model/dto"
+	"github.com/labstack/echo/v4"
+)
+
+type MultisportController interface {
+	GetMultisports(c echo.Context) error
+	GetMultisport(c echo.Context) error
+}
+
+type multisportController struct {
+	context *container.Container
+}
+
+func NewMultisportController(c *container.Container) MultisportController {
+	return &multisportController{context: c}
+}
+
+// GetMultisports returns the multisport activities of the current user
+// @Summary      List multisport activities
+// @Tags         multisports
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Produce      json
+// @Success      200  {object}  dto.Response[[]dto.MultisportResponse]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /multisports [get]
+func (mc *multisportController) GetMultisports(c echo.Context) error {
+	user := mc.context.GetUser(c)
+
+	multisports, err := mc.context.MultisportRepo().ListByUserID(user.ID)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[[]dto.MultisportResponse]{
+		Results: dto.NewMultisportsResponse(multisports),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetMultisport returns a multisport activity with its combined totals, the
+// stats of every leg and the transitions
+// @Summary      Get multisport activity
+// @Tags         multisports
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id   path  int  true  "Multisport activity ID"
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.MultisportResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /multisports/{id} [get]
+func (mc *multisportController) GetMultisport(c echo.Context) error {
+	user := mc.context.GetUser(c)
+
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	multisport, err := mc.context.MultisportRepo().GetByUserID(user.ID, id)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	resp := dto.Response[dto.MultisportResponse]{
+		Results: dto.NewMultisportResponse(multisport),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -9,6 +9,7 @@ import (
 	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model/dto"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/worker"
 	"github.com/labstack/echo/v4"
 	"gorm.io/datatypes"
 )
@@ -99,6 +100,15 @@ func (pc *profileController) UpdateProfile(c echo.Context) error {
 		return renderApiError(c, http.StatusBadRequest, errors.New("invalid default workout visibility"))
 	}
 	user.Profile.DefaultWorkoutVisibility = updateData.DefaultWorkoutVisibility
+
+	zoneModel := model.HeartRateZoneModel(updateData.HeartRateZoneModel)
+	if err := zoneModel.Validate(); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	zonesChanged := user.Profile.HeartRateZoneModel != zoneModel
+	user.Profile.HeartRateZoneModel = zoneModel
+
 	if !pc.context.GetConfig().AutoImportEnabled {
 		if updateData.AutoImportDirectory != "" {
 			return renderApiError(c, http.StatusBadRequest, errors.New("auto import is disabled"))
@@ -110,6 +120,8 @@ func (pc *profileController) UpdateProfile(c echo.Context) error {
 	}
 	user.Profile.APIActive = updateData.APIActive
 	user.Profile.PreferFullDate = updateData.PreferFullDate
+	user.Profile.CleanGPSTracks = updateData.CleanGPSTracks
+	user.Profile.SmoothGPSTracks = updateData.SmoothGPSTracks
 	user.Profile.UserID = user.ID
 
 	if err := user.Profile.Save(pc.context.GetDB()); err != nil {
@@ -120,6 +132,28 @@ func (pc *profileController) UpdateProfile(c echo.Context) error {
 		return renderApiError(c, http.StatusInternalServerError, err)
 	}
 
+	// Existing workouts are cleaned again by the update worker
+	changed, err := user.UpdateTrackCleaning(pc.context.GetDB())
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	// The time in the heart rate zones is calculated again as well
+	if zonesChanged {
+		ids, err := user.MarkHeartRateWorkoutsDirty(pc.context.GetDB(), time.Time{})
+		if err != nil {
+			return renderApiError(c, http.StatusInternalServerError, err)
+		}
+
+		changed = append(changed, ids...)
+	}
+
+	for _, id := range changed {
+		if err := worker.EnqueueWorkoutUpdate(c.Request().Context(), pc.context, id); err != nil {
+			return renderApiError(c, http.StatusInternalServerError, err)
+		}
+	}
+
 	resp := dto.Response[dto.UserProfileResponse]{
 		Results: dto.NewUserProfileResponse(user),
 	}
@@ -11,6 +11,7 @@ import (
 
 type StatisticsController interface {
 	GetStatistics(c echo.Context) error
+	GetTrainingLoad(c echo.Context) error
 }
 
 type statisticsController struct {
@@ -61,3 +62,39 @@ func (sc *statisticsController) GetStatistics(c echo.Context) error {
 
 	return c.JSON(http.StatusOK, resp)
 }
+
+// GetTrainingLoad returns the user's training load, fitness, fatigue and form per day
+// @Summary      Get training load
+// @Tags         statistics
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Produce      json
+// @Param        since  query  string false "Relative start (e.g. '1 year')"
+// @Success      200  {object}  dto.Response[[]dto.TrainingLoadResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /statistics/training-load [get]
+func (sc *statisticsController) GetTrainingLoad(c echo.Context) error {
+	user := sc.context.GetUser(c)
+
+	var statConfig model.StatConfig
+	if err := c.Bind(&statConfig); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if statConfig.Since == "" {
+		statConfig.Since = "1 year"
+	}
+
+	loads, err := user.GetTrainingLoads(statConfig)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[[]dto.TrainingLoadResponse]{
+		Results: dto.NewTrainingLoadResponses(loads),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
@@ -27,7 +27,9 @@ type UserController interface {
 	GetTotals(c echo.Context) error
 	GetRecords(c echo.Context) error
 	GetRecordsRanking(c echo.Context) error
+	GetEffortCurve(c echo.Context) error
 	GetClimbRecordsRanking(c echo.Context) error
+	GetExerciseRecordsRanking(c echo.Context) error
 	GetUserByID(c echo.Context) error
 }
 
@@ -177,13 +179,15 @@ func (uc *userController) GetRecords(c echo.Context) error {
 // @Security     ApiKeyQuery
 // @Security     CookieAuth
 // @Param        workout_type  query     string  true   "Workout type (e.g. running)"
-// @Param        label         query     string  true   "Distance label (e.g. 10 km)"
+// @Param        label         query     string  true   "Distance label (e.g. 10 km), or duration label (e.g. 5 min) with a metric"
+// @Param        metric        query     string  false  "Rank best efforts by duration of the metric instead: power, speed or heart-rate"
 // @Param        start         query     string  false  "Start date (YYYY-MM-DD)"
 // @Param        end           query     string  false  "End date (YYYY-MM-DD, inclusive)"
 // @Param        page          query     int     false  "Page"
 // @Param        per_page      query     int     false  "Per page"
 // @Produce      json
 // @Success      200  {object}  dto.PaginatedResponse[dto.DistanceRecordResponse]
+// @Success      200  {object}  dto.PaginatedResponse[dto.EffortRecordResponse]
 // @Failure      400  {object}  dto.Response[any]
 // @Failure      500  {object}  dto.Response[any]
 // @Router       /records/ranking [get]
@@ -213,6 +217,30 @@ func (uc *userController) GetRecordsRanking(c echo.Context) error {
 		return renderApiError(c, http.StatusBadRequest, err)
 	}
 
+	if metric := c.QueryParam("metric"); metric != "" {
+		efforts, totalCount, err := model.GetEffortRecordRanking(
+			uc.visibleEffortRecords(targetUser, viewer, viewerActorIRI, wt, startDate, endDate),
+			model.EffortMetric(metric), label, pagination.PerPage, pagination.GetOffset(),
+		)
+		if errors.Is(err, model.ErrInvalidEffortMetric) || errors.Is(err, model.ErrInvalidEffortLabel) {
+			return renderApiError(c, http.StatusBadRequest, err)
+		}
+
+		if err != nil {
+			return renderApiError(c, http.StatusInternalServerError, err)
+		}
+
+		resp := dto.PaginatedResponse[dto.EffortRecordResponse]{
+			Results:    dto.NewEffortRecordResponses(efforts),
+			Page:       pagination.Page,
+			PerPage:    pagination.PerPage,
+			TotalPages: pagination.CalculateTotalPages(totalCount),
+			TotalCount: totalCount,
+		}
+
+		return c.JSON(http.StatusOK, resp)
+	}
+
 	records, totalCount, err := uc.getVisibleDistanceRanking(targetUser, viewer, viewerActorIRI, wt, label, startDate, endDate, pagination.PerPage, pagination.GetOffset())
 	if err != nil {
 		return renderApiError(c, http.StatusInternalServerError, err)
@@ -229,6 +257,60 @@ func (uc *userController) GetRecordsRanking(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// GetEffortCurve returns the best effort of a metric for each duration, and
+// the workouts that set them
+// @Summary      Get the mean-maximal curve of a metric
+// @Tags         user
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        metric        query     string  true   "Metric: power, speed or heart-rate"
+// @Param        workout_type  query     string  false  "Workout type (e.g. cycling); all types when empty, required for speed"
+// @Param        start         query     string  false  "Start date (YYYY-MM-DD)"
+// @Param        end           query     string  false  "End date (YYYY-MM-DD, inclusive)"
+// @Produce      json
+// @Success      200  {object}  dto.Response[[]dto.EffortRecordResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /records/efforts [get]
+func (uc *userController) GetEffortCurve(c echo.Context) error {
+	targetUser, viewer, viewerActorIRI, err := uc.resolveTargetUserFromHandle(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	metric := model.EffortMetric(c.QueryParam("metric"))
+	if err := metric.Validate(); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	wt := model.WorkoutType("")
+	if workoutType := c.QueryParam("workout_type"); workoutType != "" {
+		wt = model.AsWorkoutType(workoutType)
+	}
+
+	// The speeds of different sports can not be compared
+	if metric == model.EffortMetricSpeed && wt == "" {
+		return renderApiError(c, http.StatusBadRequest, errors.New("workout_type is required for the speed metric"))
+	}
+
+	efforts, err := model.GetEffortCurve(uc.visibleEffortRecords(targetUser, viewer, viewerActorIRI, wt, startDate, endDate), metric)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[[]dto.EffortRecordResponse]{
+		Results: dto.NewEffortRecordResponses(efforts),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
 // GetClimbRecordsRanking returns ranked climb segments ordered by elevation gain
 // @Summary      Get ranked climb records
 // @Tags         user
@@ -285,6 +367,74 @@ func (uc *userController) GetClimbRecordsRanking(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
+// GetExerciseRecordsRanking returns ranked efforts of a strength exercise
+// @Summary      Get ranked exercise records
+// @Tags         user
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        exercise  query     string  true   "Exercise (e.g. barbell_bench_press)"
+// @Param        metric    query     string  false  "Metric: one-rep-max (default), max-weight or volume"
+// @Param        start     query     string  false  "Start date (YYYY-MM-DD)"
+// @Param        end       query     string  false  "End date (YYYY-MM-DD, inclusive)"
+// @Param        page      query     int     false  "Page"
+// @Param        per_page  query     int     false  "Per page"
+// @Produce      json
+// @Success      200  {object}  dto.PaginatedResponse[dto.ExerciseEffortResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /records/exercises/ranking [get]
+func (uc *userController) GetExerciseRecordsRanking(c echo.Context) error {
+	targetUser, viewer, viewerActorIRI, err := uc.resolveTargetUserFromHandle(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	exercise := c.QueryParam("exercise")
+	if exercise == "" {
+		return renderApiError(c, http.StatusBadRequest, errors.New("exercise is required"))
+	}
+
+	metric := c.QueryParam("metric")
+	switch metric {
+	case "", "one-rep-max", "max-weight", "volume":
+	default:
+		return renderApiError(c, http.StatusBadRequest, fmt.Errorf("invalid metric: %s", metric))
+	}
+
+	var pagination dto.PaginationParams
+	if err := c.Bind(&pagination); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+	pagination.SetDefaults()
+
+	startDate, endDate, err := parseDateRange(c)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	workouts, err := uc.getVisibleStrengthWorkouts(targetUser, viewer, viewerActorIRI, startDate, endDate)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	efforts := model.ExerciseRanking(workouts, exercise, metric)
+	totalCount := int64(len(efforts))
+
+	start := min(pagination.GetOffset(), len(efforts))
+	end := min(start+pagination.PerPage, len(efforts))
+
+	resp := dto.PaginatedResponse[dto.ExerciseEffortResponse]{
+		Results:    dto.NewExerciseEffortResponses(efforts[start:end]),
+		Page:       pagination.Page,
+		PerPage:    pagination.PerPage,
+		TotalPages: pagination.CalculateTotalPages(totalCount),
+		TotalCount: totalCount,
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
 // GetUserByID returns a specific user's workout records
 // @Summary      Get user profile by ID
 // @Tags         user
@@ -968,9 +1118,49 @@ func (uc *userController) getVisibleRecords(targetUser, viewer *model.User, view
 		}
 	}
 
+	workouts, err := uc.getVisibleStrengthWorkouts(targetUser, viewer, viewerActorIRI, startDate, endDate)
+	if err != nil {
+		return nil, err
+	}
+
+	if exerciseRecords := model.ExerciseRecords(workouts); len(exerciseRecords) > 0 {
+		rs = append(rs, &model.WorkoutRecord{
+			WorkoutType:     model.WorkoutTypeWeightLifting,
+			ExerciseRecords: exerciseRecords,
+			Active:          true,
+		})
+	}
+
 	return rs, nil
 }
 
+// getVisibleStrengthWorkouts returns the visible workouts with sets
+func (uc *userController) getVisibleStrengthWorkouts(targetUser, viewer *model.User, viewerActorIRI string, startDate, endDate *time.Time) ([]*model.Workout, error) {
+	q := model.ScopeVisibleWorkouts(
+		model.PreloadWorkoutData(uc.context.GetDB()),
+		targetUser.ID,
+		viewer.ID,
+		viewerActorIRI,
+	).Where("workouts.id IN (?)", uc.context.GetDB().Table("map_data_sets").
+		Select("map_data.workout_id").
+		Joins("join map_data on map_data.id = map_data_sets.map_data_id"))
+
+	if startDate != nil {
+		q = q.Where("workouts.date >= ?", *startDate)
+	}
+
+	if endDate != nil {
+		q = q.Where("workouts.date <= ?", *endDate)
+	}
+
+	var workouts []*model.Workout
+	if err := q.Order("workouts.date ASC").Find(&workouts).Error; err != nil {
+		return nil, err
+	}
+
+	return workouts, nil
+}
+
 func (uc *userController) getVisibleRecordForType(targetUser, viewer *model.User, viewerActorIRI string, t model.WorkoutType, startDate, endDate *time.Time) (*model.WorkoutRecord, error) {
 	if t == "" {
 		t = model.WorkoutTypeRunning
@@ -1115,6 +1305,31 @@ func (uc *userController) getVisibleDistanceRanking(
 	return result, totalCount, nil
 }
 
+// visibleEffortRecords returns the query of the best efforts of the visible
+// workouts of the type (or all types) in the date range
+func (uc *userController) visibleEffortRecords(targetUser, viewer *model.User, viewerActorIRI string, t model.WorkoutType, startDate, endDate *time.Time) *gorm.DB {
+	q := model.ScopeVisibleWorkouts(
+		model.EffortRecordsQuery(uc.context.GetDB()),
+		targetUser.ID,
+		viewer.ID,
+		viewerActorIRI,
+	)
+
+	if t != "" {
+		q = q.Where("workouts.type = ?", t)
+	}
+
+	if startDate != nil {
+		q = q.Where("workouts.date >= ?", *startDate)
+	}
+
+	if endDate != nil {
+		q = q.Where("workouts.date <= ?", *endDate)
+	}
+
+	return q
+}
+
 func (uc *userController) getVisibleClimbRanking(targetUser, viewer *model.User, viewerActorIRI string, t model.WorkoutType, startDate, endDate *time.Time, limit, offset int) ([]model.ClimbRecord, int64, error) {
 	if !t.IsDistance() {
 		return nil, 0, fmt.Errorf("climb ranking is only supported for distance workout types: %s", t)
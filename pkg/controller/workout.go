@@ -1,7 +1,10 @@
 package controller
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
@@ -12,6 +15,7 @@ import (
 
 	ap "github.com/jovandeginste/workout-tracker/v2/pkg/activitypub"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/converters"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model/dto"
 	"github.com/jovandeginste/workout-tracker/v2/pkg/worker"
@@ -30,13 +34,27 @@ type WorkoutController interface {
 	CreateReply(c echo.Context) error
 	GetWorkoutBreakdown(c echo.Context) error
 	GetWorkoutRangeStats(c echo.Context) error
+	GetWorkoutHRV(c echo.Context) error
+	GetExercises(c echo.Context) error
 	GetWorkoutCalendar(c echo.Context) error
 	CreateWorkout(c echo.Context) error
+	PreviewWorkouts(c echo.Context) error
 	GetRecentWorkouts(c echo.Context) error
 	DeleteWorkout(c echo.Context) error
 	UpdateWorkout(c echo.Context) error
 	ToggleWorkoutLock(c echo.Context) error
 	RefreshWorkout(c echo.Context) error
+	GetDuplicateWorkouts(c echo.Context) error
+	MergeWorkout(c echo.Context) error
+	MergeWorkoutSensors(c echo.Context) error
+	TrimWorkout(c echo.Context) error
+	RemoveWorkoutPoints(c echo.Context) error
+	SplitWorkout(c echo.Context) error
+	RevertWorkoutEdits(c echo.Context) error
+	CorrectWorkoutElevation(c echo.Context) error
+	ShiftWorkoutTime(c echo.Context) error
+	ShiftWorkoutsTime(c echo.Context) error
+	SetWorkoutTimezone(c echo.Context) error
 	DownloadWorkout(c echo.Context) error
 	DownloadWorkoutAttachment(c echo.Context) error
 }
@@ -251,7 +269,18 @@ func (wc *workoutController) GetWorkout(c echo.Context) error {
 		return renderApiError(c, http.StatusInternalServerError, err)
 	}
 
+	efforts, err := model.GetWorkoutEffortRecordsWithRank(wc.context.GetDB(), workout.UserID, workout.Type, workout.ID)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
 	result := dto.NewWorkoutDetailResponse(workout, records)
+	result.Efforts = dto.NewWorkoutEffortRecordResponses(efforts)
+
+	if swim, ok := workout.SwimSummary(); ok {
+		result.Swim = dto.NewSwimSummaryResponse(swim, wc.context.GetUser(c).PreferredUnits())
+	}
+
 	published, err := wc.context.APOutboxRepo().PublishedMap(workout.UserID, []uint64{workout.ID})
 	if err == nil {
 		result.ActivityPubPublished = published[workout.ID]
@@ -272,6 +301,13 @@ func (wc *workoutController) GetWorkout(c echo.Context) error {
 		result.RepliesCount = replyCount
 	}
 
+	if workout.UserID == wc.context.GetUser(c).ID {
+		duplicates, err := workout.ProbableDuplicates(wc.context.GetDB())
+		if err == nil {
+			result.DuplicateIDs = workoutIDs(duplicates)
+		}
+	}
+
 	resp := dto.Response[dto.WorkoutDetailResponse]{
 		Results: result,
 	}
@@ -635,6 +671,7 @@ func (wc *workoutController) CreateReply(c echo.Context) error {
 // @Param        id     path   int     true  "Workout ID"
 // @Param        unit   query  string  false "Unit"
 // @Param        count  query  number  false "Count"
+// @Param        mode   query  string  false "Mode: auto, laps, unit, lengths or intervals"
 // @Produce      json
 // @Success      200  {object}  dto.Response[dto.WorkoutBreakdownResponse]
 // @Failure      400  {object}  dto.Response[any]
@@ -666,6 +703,13 @@ func (wc *workoutController) GetWorkoutBreakdown(c echo.Context) error {
 
 	resp := dto.Response[dto.WorkoutBreakdownResponse]{}
 
+	if workout.Data != nil && len(workout.Data.Lengths) > 0 && params.Mode != "unit" {
+		resp.Results = swimBreakdown(workout, params.Mode, requester.PreferredUnits())
+		addBreakdownHeartRateZones(workout, resp.Results.Items)
+
+		return c.JSON(http.StatusOK, resp)
+	}
+
 	preferLaps := (params.Mode == "" || params.Mode == "auto" || params.Mode == "laps") && workout.Data != nil && len(workout.Data.Laps) > 1
 
 	if preferLaps {
@@ -673,6 +717,7 @@ func (wc *workoutController) GetWorkoutBreakdown(c echo.Context) error {
 			Mode:  "laps",
 			Items: dto.NewWorkoutBreakdownItemsFromLaps(workout.Data.Laps, workout.Data.Details.Points, requester.PreferredUnits()),
 		}
+		addBreakdownHeartRateZones(workout, resp.Results.Items)
 
 		return c.JSON(http.StatusOK, resp)
 	}
@@ -681,19 +726,70 @@ func (wc *workoutController) GetWorkoutBreakdown(c echo.Context) error {
 		return renderApiError(c, http.StatusBadRequest, errors.New("workout has no map data"))
 	}
 
-	breakdown, err := workout.StatisticsPer(params.Count, requester.PreferredUnits().Distance())
+	count, unit := params.Count, requester.PreferredUnits().Distance()
+
+	// Swims are split per 100 meters or yards
+	if workout.Type == model.WorkoutTypeSwimming {
+		count *= 100
+		unit = "m"
+
+		if requester.PreferredUnits().SwimPace() == "min/100yd" {
+			unit = "yd"
+		}
+	}
+
+	breakdown, err := workout.StatisticsPer(count, unit)
 	if err != nil {
 		return renderApiError(c, http.StatusBadRequest, err)
 	}
 
 	resp.Results = dto.WorkoutBreakdownResponse{
 		Mode:  "unit",
-		Items: dto.NewWorkoutBreakdownItemsFromUnit(breakdown.Items, breakdown.Unit, params.Count, requester.PreferredUnits()),
+		Items: dto.NewWorkoutBreakdownItemsFromUnit(breakdown.Items, breakdown.Unit, count, requester.PreferredUnits()),
+	}
+	addBreakdownHeartRateZones(workout, resp.Results.Items)
+
+	if workout.Type == model.WorkoutTypeSwimming {
+		resp.Results.SwimPaceUnit = requester.PreferredUnits().SwimPace()
 	}
 
 	return c.JSON(http.StatusOK, resp)
 }
 
+// addBreakdownHeartRateZones adds the time spent in the heart rate zones of
+// the workout's owner to the breakdown items
+func addBreakdownHeartRateZones(workout *model.Workout, items []dto.WorkoutBreakdownItemResponse) {
+	if workout.User == nil || workout.Data == nil || !workout.HasHeartRate() {
+		return
+	}
+
+	dto.SetBreakdownHeartRateZones(items, workout.Data.Details, workout.User.HeartRateZonesAt(workout.Date))
+}
+
+// swimBreakdown returns the breakdown of a pool swim, per interval or per
+// length; by default per interval, unless the swim has only one
+func swimBreakdown(workout *model.Workout, mode string, units *model.UserPreferredUnits) dto.WorkoutBreakdownResponse {
+	var points []model.MapPoint
+	if workout.Data.Details != nil {
+		points = workout.Data.Details.Points
+	}
+
+	resp := dto.WorkoutBreakdownResponse{SwimPaceUnit: units.SwimPace()}
+	intervals := workout.SwimIntervals()
+
+	if mode == "lengths" || (mode != "intervals" && len(intervals) <= 1) {
+		resp.Mode = "lengths"
+		resp.Items = dto.NewWorkoutBreakdownItemsFromLengths(workout.Data.Lengths, points, units)
+
+		return resp
+	}
+
+	resp.Mode = "intervals"
+	resp.Items = dto.NewWorkoutBreakdownItemsFromSwimIntervals(intervals, points, units)
+
+	return resp
+}
+
 // GetWorkoutRangeStats returns aggregate statistics for a selection of map points
 // @Summary      Get workout range statistics
 // @Tags         workouts
@@ -745,7 +841,7 @@ func (wc *workoutController) GetWorkoutRangeStats(c echo.Context) error {
 		return renderApiError(c, http.StatusBadRequest, errors.New("invalid range"))
 	}
 
-	stats, ok := workout.Data.Details.StatsForRange(startIdx, endIdx)
+	stats, ok := workout.StatsForRange(startIdx, endIdx)
 	if !ok {
 		return renderApiError(c, http.StatusBadRequest, errors.New("invalid range"))
 	}
@@ -754,6 +850,94 @@ func (wc *workoutController) GetWorkoutRangeStats(c echo.Context) error {
 		Results: dto.NewWorkoutRangeStatsResponse(stats, startIdx, endIdx, requester.PreferredUnits()),
 	}
 
+	if workout.User != nil && workout.HasHeartRate() {
+		zones := workout.User.HeartRateZonesAt(workout.Date)
+		resp.Results.HeartRateZones = dto.NewHeartRateZonesResponse(zones, workout.Data.Details.TimeInHeartRateZones(zones, startIdx, endIdx))
+	}
+
+	if workout.User != nil && workout.HasPower() {
+		zones := workout.User.PowerZonesAt(workout.Date)
+		resp.Results.PowerZones = dto.NewPowerZonesResponse(zones, workout.Data.Details.TimeInPowerZones(zones, startIdx, endIdx))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetWorkoutHRV returns the heart-rate variability of a workout over time
+// @Summary      Get workout heart-rate variability
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id      path   int  true  "Workout ID"
+// @Param        window  query  int  false "Window length in seconds (default 120)"
+// @Param        step    query  int  false "Step between windows in seconds (default 30)"
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.WorkoutHRVResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /workouts/{id}/hrv [get]
+func (wc *workoutController) GetWorkoutHRV(c echo.Context) error {
+	params := struct {
+		Window int `query:"window"`
+		Step   int `query:"step"`
+	}{}
+
+	if err := c.Bind(&params); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if params.Window < 0 || params.Step < 0 {
+		return renderApiError(c, http.StatusBadRequest, errors.New("invalid window"))
+	}
+
+	window := time.Duration(params.Window) * time.Second
+	if window == 0 {
+		window = model.DefaultHRVWindow
+	}
+
+	step := time.Duration(params.Step) * time.Second
+	if step == 0 {
+		step = model.DefaultHRVStep
+	}
+
+	workout, err := wc.getReadableWorkout(c, false)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	hrv, err := workout.HRV(window, step)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	resp := dto.Response[dto.WorkoutHRVResponse]{
+		Results: dto.NewWorkoutHRVResponse(hrv, window),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// GetExercises returns the exercise catalogue and the exercises added by the current user
+// @Summary      Get exercise catalogue
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Produce      json
+// @Success      200  {object}  dto.Response[[]dto.ExerciseResponse]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /exercises [get]
+func (wc *workoutController) GetExercises(c echo.Context) error {
+	exercises, err := model.GetExercises(wc.context.GetDB(), wc.context.GetUser(c).ID)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[[]dto.ExerciseResponse]{
+		Results: dto.NewExerciseResponses(exercises),
+	}
+
 	return c.JSON(http.StatusOK, resp)
 }
 
@@ -931,6 +1115,7 @@ func (wc *workoutController) localActorIRI(c echo.Context, user *model.User) str
 // @Accept       multipart/form-data
 // @Accept       json
 // @Produce      json
+// @Param        items  formData  string  false  "JSON list of dto.ImportSelection; only these previewed workouts are created"
 // @Success      201  {object}  dto.Response[dto.WorkoutResponse]
 // @Failure      400  {object}  dto.Response[any]
 // @Failure      500  {object}  dto.Response[any]
@@ -963,17 +1148,40 @@ func (wc *workoutController) createWorkoutFromFile(c echo.Context, user *model.U
 		workoutType = model.WorkoutTypeAutoDetect
 	}
 
+	selections, err := parseImportSelections(c.FormValue("items"))
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
 	createdWorkouts := []dto.WorkoutResponse{}
 	errList := []error{}
 
-	for _, file := range files {
+	for fileIndex, file := range files {
 		content, parseErr := uploadedFile(file)
 		if parseErr != nil {
 			errList = append(errList, parseErr)
 			continue
 		}
 
-		ws, addErr := user.AddWorkout(wc.context.GetDB(), workoutType, notes, file.Filename, content)
+		if imported, err := wc.importMeasurements(c.Request().Context(), user, file.Filename, content); imported {
+			if err != nil {
+				errList = append(errList, err)
+			}
+
+			continue
+		}
+
+		var (
+			ws     []*model.Workout
+			addErr []error
+		)
+
+		if selections == nil {
+			ws, addErr = user.AddWorkout(wc.context.GetDB(), workoutType, notes, file.Filename, content)
+		} else {
+			ws, addErr = wc.addSelectedWorkouts(user, workoutType, notes, file.Filename, content, selections[fileIndex])
+		}
+
 		if len(addErr) > 0 {
 			for _, e := range addErr {
 				errList = append(errList, e)
@@ -1024,6 +1232,216 @@ func (wc *workoutController) createWorkoutFromFile(c echo.Context, user *model.U
 	return c.JSON(statusCode, resp)
 }
 
+// PreviewWorkouts parses uploaded files without saving them
+// @Summary      Preview workout import
+// @Description  Returns the workouts that would be created from the uploaded files. Confirm a subset by uploading the same files to POST /workouts, with an "items" field: a JSON list of dto.ImportSelection.
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        file  formData  file    true   "Workout files"
+// @Param        type  formData  string  false  "Workout type"
+// @Success      200  {object}  dto.Response[[]dto.ImportPreviewItem]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /workouts/preview [post]
+func (wc *workoutController) PreviewWorkouts(c echo.Context) error {
+	user := wc.context.GetUser(c)
+	db := wc.context.GetDB()
+
+	form, err := c.MultipartForm()
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	files := form.File["file"]
+	if len(files) == 0 {
+		return renderApiError(c, http.StatusBadRequest, errors.New("no file uploaded"))
+	}
+
+	workoutType := model.WorkoutType(c.FormValue("type"))
+	if workoutType == "" {
+		workoutType = model.WorkoutTypeAutoDetect
+	}
+
+	items := []dto.ImportPreviewItem{}
+	errList := []error{}
+
+	// The starts of the workouts of the upload; a second workout with the same
+	// start collides with the first one
+	starts := map[time.Time]bool{}
+
+	for fileIndex, file := range files {
+		content, err := uploadedFile(file)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+
+		if _, err := converters.ParseMeasurements(file.Filename, content); err == nil {
+			// Measurements do not create workouts
+			continue
+		}
+
+		ws, err := model.NewWorkout(user, workoutType, "", file.Filename, content)
+		if err != nil {
+			errList = append(errList, fmt.Errorf("%w: %s: %s", model.ErrInvalidData, file.Filename, err))
+			continue
+		}
+
+		for i, w := range ws {
+			duplicate, err := w.IsDuplicate(db)
+			if err != nil {
+				return renderApiError(c, http.StatusInternalServerError, err)
+			}
+
+			item := dto.NewImportPreviewItem(fileIndex, i, file.Filename, w)
+			item.Duplicate = duplicate || starts[w.Date.UTC()]
+			starts[w.Date.UTC()] = true
+
+			items = append(items, item)
+		}
+	}
+
+	resp := dto.Response[[]dto.ImportPreviewItem]{
+		Results: items,
+	}
+
+	if len(errList) > 0 {
+		resp.AddError(errList...)
+	}
+
+	statusCode := http.StatusOK
+	if len(items) == 0 && len(errList) > 0 {
+		statusCode = http.StatusBadRequest
+	}
+
+	return c.JSON(statusCode, resp)
+}
+
+// parseImportSelections parses the previewed workouts to create, per file
+// and per workout of the file; it returns nil when there is no selection
+func parseImportSelections(raw string) (map[int]map[int]dto.ImportSelection, error) {
+	if raw == "" {
+		return nil, nil
+	}
+
+	var items []dto.ImportSelection
+	if err := json.Unmarshal([]byte(raw), &items); err != nil {
+		return nil, fmt.Errorf("invalid items: %w", err)
+	}
+
+	selections := map[int]map[int]dto.ImportSelection{}
+
+	for _, item := range items {
+		if selections[item.FileIndex] == nil {
+			selections[item.FileIndex] = map[int]dto.ImportSelection{}
+		}
+
+		selections[item.FileIndex][item.Index] = item
+	}
+
+	return selections, nil
+}
+
+// addSelectedWorkouts creates the selected workouts of the file, with the
+// values chosen by the user
+func (wc *workoutController) addSelectedWorkouts(user *model.User, workoutType model.WorkoutType, notes string, filename string, content []byte, selected map[int]dto.ImportSelection) ([]*model.Workout, []error) {
+	if len(selected) == 0 {
+		return nil, nil
+	}
+
+	ws, err := model.NewWorkout(user, workoutType, notes, filename, content)
+	if err != nil {
+		return nil, []error{fmt.Errorf("%w: %s", model.ErrInvalidData, err)}
+	}
+
+	created := []*model.Workout{}
+	errs := []error{}
+
+	for i, w := range ws {
+		s, ok := selected[i]
+		if !ok {
+			continue
+		}
+
+		o, err := wc.importOverrides(user, &s)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		if err := user.CreateWorkout(wc.context.GetDB(), w, o); err != nil {
+			errs = append(errs, err)
+			continue
+		}
+
+		created = append(created, w)
+	}
+
+	return created, errs
+}
+
+// importOverrides converts the values chosen for a previewed workout
+func (wc *workoutController) importOverrides(user *model.User, s *dto.ImportSelection) (*model.WorkoutOverrides, error) {
+	o := &model.WorkoutOverrides{}
+
+	if s.Name != nil {
+		o.Name = strings.TrimSpace(*s.Name)
+	}
+
+	if s.Type != nil {
+		o.Type = *s.Type
+	}
+
+	if s.Visibility != nil {
+		if !s.Visibility.IsValid() {
+			return nil, fmt.Errorf("invalid visibility: %s", *s.Visibility)
+		}
+
+		o.Visibility = s.Visibility
+	}
+
+	if s.EquipmentIDs != nil {
+		equipment, err := wc.context.EquipmentRepo().GetByUserIDs(user.ID, *s.EquipmentIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		o.Equipment = append([]*model.Equipment{}, equipment...)
+	}
+
+	return o, nil
+}
+
+// importMeasurements merges the daily measurements of a weight-scale or
+// monitoring file into the user's measurements; it returns false if the file
+// is not such a file
+func (wc *workoutController) importMeasurements(ctx context.Context, user *model.User, filename string, content []byte) (bool, error) {
+	ms, err := converters.ParseMeasurements(filename, content)
+	if errors.Is(err, converters.ErrNotMeasurementFile) {
+		return false, nil
+	}
+
+	if err != nil {
+		return true, err
+	}
+
+	if len(ms) == 0 {
+		return true, fmt.Errorf("%w: %s", converters.ErrNoMeasurementsFound, filename)
+	}
+
+	for _, m := range ms {
+		if err := worker.MergeMeasurement(ctx, wc.context, user, m); err != nil {
+			return true, err
+		}
+	}
+
+	return true, nil
+}
+
 func (wc *workoutController) createWorkoutManual(c echo.Context, user *model.User) error {
 	d := &dto.ManualWorkout{Units: user.PreferredUnits()}
 	if err := c.Bind(d); err != nil {
@@ -1321,37 +1739,549 @@ func (wc *workoutController) RefreshWorkout(c echo.Context) error {
 	return c.JSON(http.StatusOK, resp)
 }
 
-// DownloadWorkout downloads the original workout file
-// @Summary      Download workout file
+// GetDuplicateWorkouts returns the pairs of workouts of the current user that
+// overlap so much in time they are probably recordings of the same activity
+// @Summary      Get probable duplicate workouts
 // @Tags         workouts
 // @Security     ApiKeyAuth
 // @Security     ApiKeyQuery
 // @Security     CookieAuth
-// @Param        id   path  int  true  "Workout ID"
-// @Produce      octet-stream
-// @Success      200  {string}  string  "binary workout file"
+// @Produce      json
+// @Success      200  {object}  dto.Response[[]dto.DuplicatePairResponse]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /workouts/duplicates [get]
+func (wc *workoutController) GetDuplicateWorkouts(c echo.Context) error {
+	user := wc.context.GetUser(c)
+
+	pairs, err := model.FindProbableDuplicates(wc.context.GetDB(), user.ID)
+	if err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[[]dto.DuplicatePairResponse]{
+		Results: dto.NewDuplicatePairResponses(pairs),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// MergeWorkout merges another recording of the same activity into a workout;
+// the workout with the best GPS track is kept, borrows the heart rate, power
+// and cadence it is missing from the other one, and the other one is deleted
+// @Summary      Merge workouts
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id    path  int                      true  "Workout ID"
+// @Param        body  body  dto.MergeWorkoutRequest  true  "The workout to merge"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.MergeWorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
 // @Failure      404  {object}  dto.Response[any]
-// @Router       /workouts/{id}/download [get]
-func (wc *workoutController) DownloadWorkout(c echo.Context) error {
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /workouts/{id}/merge [post]
+func (wc *workoutController) MergeWorkout(c echo.Context) error {
+	user := wc.context.GetUser(c)
+
 	workout, err := wc.getOwnedWorkout(c)
 	if err != nil {
 		return renderApiError(c, http.StatusNotFound, err)
 	}
 
-	if !workout.HasFile() {
-		return renderApiError(c, http.StatusNotFound, errors.New("workout has no file"))
+	var req dto.MergeWorkoutRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
 	}
 
-	basename := workout.GPX.Filename
-	if basename == "" {
-		basename = "workout_" + strconv.FormatUint(workout.ID, 10) + ".gpx"
+	other, err := wc.context.WorkoutRepo().GetByUserID(user.ID, req.WorkoutID)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
 	}
 
-	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\""+basename+"\"")
-
+	kept, deleted, err := model.MergeWorkouts(wc.context.GetDB(), workout, other)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := wc.context.APOutboxRepo().DeleteEntryForWorkout(user.ID, deleted.ID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	if err := worker.EnqueueWorkoutUpdate(c.Request().Context(), wc.context, kept.ID); err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[dto.MergeWorkoutResponse]{
+		Results: dto.MergeWorkoutResponse{
+			Workout:          dto.NewWorkoutResponse(kept),
+			DeletedWorkoutID: deleted.ID,
+		},
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// MergeWorkoutSensors merges the sensor streams of another file, e.g. of a
+// heart rate strap or bike computer, into a workout; the samples are aligned
+// by their time and replace the values of the workout
+// @Summary      Merge sensor streams into a workout
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id       path      int     true   "Workout ID"
+// @Param        file     formData  file    true   "The file with the sensor streams"
+// @Param        metrics  formData  string  false  "Comma separated metrics to merge (heart-rate, power, cadence, temperature); all by default"
+// @Param        offset   formData  int     false  "Seconds to shift the samples of the file by"
+// @Accept       multipart/form-data
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.MergeSensorsResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /workouts/{id}/sensors [post]
+func (wc *workoutController) MergeWorkoutSensors(c echo.Context) error {
+	user := wc.context.GetUser(c)
+
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	file, err := c.FormFile("file")
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	content, err := uploadedFile(file)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	var metrics []string
+
+	for m := range strings.SplitSeq(c.FormValue("metrics"), ",") {
+		if m = strings.TrimSpace(m); m != "" {
+			metrics = append(metrics, m)
+		}
+	}
+
+	var offset int
+
+	if v := c.FormValue("offset"); v != "" {
+		if offset, err = strconv.Atoi(v); err != nil {
+			return renderApiError(c, http.StatusBadRequest, err)
+		}
+	}
+
+	recordings, err := model.NewWorkout(user, model.WorkoutTypeAutoDetect, "", file.Filename, content)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, fmt.Errorf("%w: %s: %s", model.ErrInvalidData, file.Filename, err))
+	}
+
+	merged, err := workout.MergeSensorStreams(wc.context.GetDB(), file.Filename, recordings, metrics, time.Duration(offset)*time.Second)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := worker.EnqueueWorkoutUpdate(c.Request().Context(), wc.context, workout.ID); err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[dto.MergeSensorsResponse]{
+		Results: dto.MergeSensorsResponse{
+			Workout: dto.NewWorkoutResponse(workout),
+			Metrics: merged,
+		},
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// TrimWorkout keeps only a range of the points of a workout
+// @Summary      Trim workout
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id    path  int                           true  "Workout ID"
+// @Param        body  body  dto.WorkoutPointRangeRequest  true  "The points to keep"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /workouts/{id}/trim [post]
+func (wc *workoutController) TrimWorkout(c echo.Context) error {
+	return wc.editWorkoutPoints(c, (*model.Workout).TrimPoints)
+}
+
+// RemoveWorkoutPoints removes a range of the points of a workout
+// @Summary      Remove workout points
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id    path  int                           true  "Workout ID"
+// @Param        body  body  dto.WorkoutPointRangeRequest  true  "The points to remove"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /workouts/{id}/remove-points [post]
+func (wc *workoutController) RemoveWorkoutPoints(c echo.Context) error {
+	return wc.editWorkoutPoints(c, (*model.Workout).RemovePoints)
+}
+
+func (wc *workoutController) editWorkoutPoints(c echo.Context, edit func(*model.Workout, *gorm.DB, int, int) error) error {
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	var req dto.WorkoutPointRangeRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := edit(workout, wc.context.GetDB(), req.StartIndex, req.EndIndex); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := worker.EnqueueWorkoutUpdate(c.Request().Context(), wc.context, workout.ID); err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[dto.WorkoutResponse]{
+		Results: dto.NewWorkoutResponse(workout),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SplitWorkout splits a workout in two at a point; the point and all points
+// after it are moved to a new workout
+// @Summary      Split workout
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id    path  int                      true  "Workout ID"
+// @Param        body  body  dto.WorkoutSplitRequest  true  "The point to split at"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[[]dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /workouts/{id}/split [post]
+func (wc *workoutController) SplitWorkout(c echo.Context) error {
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	var req dto.WorkoutSplitRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	second, err := workout.SplitAt(wc.context.GetDB(), req.Index)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	for _, w := range []*model.Workout{workout, second} {
+		if err := worker.EnqueueWorkoutUpdate(c.Request().Context(), wc.context, w.ID); err != nil {
+			return renderApiError(c, http.StatusInternalServerError, err)
+		}
+	}
+
+	resp := dto.Response[[]dto.WorkoutResponse]{
+		Results: dto.NewWorkoutsResponse([]*model.Workout{workout, second}),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// RevertWorkoutEdits restores all points of the workout's file
+// @Summary      Revert workout edits
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id   path  int  true  "Workout ID"
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /workouts/{id}/revert-edits [post]
+func (wc *workoutController) RevertWorkoutEdits(c echo.Context) error {
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	if err := workout.RevertEdits(wc.context.GetDB()); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := worker.EnqueueWorkoutUpdate(c.Request().Context(), wc.context, workout.ID); err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[dto.WorkoutResponse]{
+		Results: dto.NewWorkoutResponse(workout),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// CorrectWorkoutElevation changes how the elevation of the workout is
+// corrected with the elevation model, and refreshes the workout
+// @Summary      Correct workout elevation
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id    path  int                             true  "Workout ID"
+// @Param        data  body  dto.ElevationCorrectionRequest  true  "Elevation correction"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /workouts/{id}/elevation-correction [post]
+func (wc *workoutController) CorrectWorkoutElevation(c echo.Context) error {
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	var req dto.ElevationCorrectionRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := workout.SetElevationCorrection(wc.context.GetDB(), model.ElevationCorrection(req.Mode)); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := worker.EnqueueWorkoutUpdate(c.Request().Context(), wc.context, workout.ID); err != nil {
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	resp := dto.Response[dto.WorkoutResponse]{
+		Results: dto.NewWorkoutResponse(workout),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ShiftWorkoutTime shifts the timestamps of the workout by an offset, or
+// re-interprets them as the local time of a zone
+// @Summary      Shift workout time
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id    path  int                          true  "Workout ID"
+// @Param        data  body  dto.WorkoutTimeShiftRequest  true  "Time shift"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Failure      409  {object}  dto.Response[any]
+// @Router       /workouts/{id}/time-shift [post]
+func (wc *workoutController) ShiftWorkoutTime(c echo.Context) error {
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	var req dto.WorkoutTimeShiftRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	shift, err := model.NewTimeShift(time.Duration(req.OffsetSeconds)*time.Second, req.Zone)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := workout.ShiftTime(wc.context.GetDB(), shift); err != nil {
+		if errors.Is(err, model.ErrWorkoutAlreadyExists) {
+			return renderApiError(c, http.StatusConflict, err)
+		}
+
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	resp := dto.Response[dto.WorkoutResponse]{
+		Results: dto.NewWorkoutResponse(workout),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// ShiftWorkoutsTime shifts the timestamps of several workouts by an offset,
+// or re-interprets them as the local time of a zone; the workouts that could
+// not be shifted are reported as errors
+// @Summary      Shift the time of workouts
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        data  body  dto.WorkoutsTimeShiftRequest  true  "Time shift"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[[]dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /workouts/time-shift [post]
+func (wc *workoutController) ShiftWorkoutsTime(c echo.Context) error {
+	var req dto.WorkoutsTimeShiftRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	shift, err := model.NewTimeShift(time.Duration(req.OffsetSeconds)*time.Second, req.Zone)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	user := wc.context.GetUser(c)
+	workouts := make([]*model.Workout, 0, len(req.WorkoutIDs))
+
+	for _, id := range req.WorkoutIDs {
+		w, err := wc.context.WorkoutRepo().GetByUserID(user.ID, id)
+		if err != nil {
+			return renderApiError(c, http.StatusNotFound, err)
+		}
+
+		w.User = user
+		workouts = append(workouts, w)
+	}
+
+	errs := model.ShiftWorkoutsTime(wc.context.GetDB(), workouts, shift)
+
+	resp := dto.Response[[]dto.WorkoutResponse]{
+		Results: []dto.WorkoutResponse{},
+	}
+
+	for _, w := range workouts {
+		if err, ok := errs[w.ID]; ok {
+			resp.AddError(fmt.Errorf("workout %d: %w", w.ID, err))
+			continue
+		}
+
+		resp.Results = append(resp.Results, dto.NewWorkoutResponse(w))
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// SetWorkoutTimezone overrides the timezone derived from the location of
+// the workout
+// @Summary      Set workout timezone
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id    path  int                         true  "Workout ID"
+// @Param        data  body  dto.WorkoutTimezoneRequest  true  "Timezone"
+// @Accept       json
+// @Produce      json
+// @Success      200  {object}  dto.Response[dto.WorkoutResponse]
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Router       /workouts/{id}/timezone [post]
+func (wc *workoutController) SetWorkoutTimezone(c echo.Context) error {
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	var req dto.WorkoutTimezoneRequest
+	if err := c.Bind(&req); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	if err := workout.SetTimezone(wc.context.GetDB(), req.Timezone); err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	resp := dto.Response[dto.WorkoutResponse]{
+		Results: dto.NewWorkoutResponse(workout),
+	}
+
+	return c.JSON(http.StatusOK, resp)
+}
+
+// DownloadWorkout downloads the original workout file, or renders the
+// workout in another format from its stored data
+// @Summary      Download workout file
+// @Tags         workouts
+// @Security     ApiKeyAuth
+// @Security     ApiKeyQuery
+// @Security     CookieAuth
+// @Param        id      path   int     true   "Workout ID"
+// @Param        format  query  string  false  "Export format (original|gpx|tcx|fit|geojson)"
+// @Produce      octet-stream
+// @Success      200  {string}  string  "binary workout file"
+// @Failure      400  {object}  dto.Response[any]
+// @Failure      404  {object}  dto.Response[any]
+// @Failure      500  {object}  dto.Response[any]
+// @Router       /workouts/{id}/download [get]
+func (wc *workoutController) DownloadWorkout(c echo.Context) error {
+	workout, err := wc.getOwnedWorkout(c)
+	if err != nil {
+		return renderApiError(c, http.StatusNotFound, err)
+	}
+
+	if f := c.QueryParam("format"); f != "" && f != "original" {
+		return wc.exportWorkout(c, workout, f)
+	}
+
+	if !workout.HasFile() {
+		return renderApiError(c, http.StatusNotFound, errors.New("workout has no file"))
+	}
+
+	basename := workout.GPX.Filename
+	if basename == "" {
+		basename = "workout_" + strconv.FormatUint(workout.ID, 10) + ".gpx"
+	}
+
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\""+basename+"\"")
+
 	return c.Blob(http.StatusOK, "application/binary", workout.GPX.Content)
 }
 
+func (wc *workoutController) exportWorkout(c echo.Context, workout *model.Workout, f string) error {
+	format, err := converters.ParseExportFormat(f)
+	if err != nil {
+		return renderApiError(c, http.StatusBadRequest, err)
+	}
+
+	content, err := converters.Export(workout, format)
+	if err != nil {
+		if errors.Is(err, ap.ErrWorkoutMissingData) {
+			return renderApiError(c, http.StatusNotFound, err)
+		}
+
+		return renderApiError(c, http.StatusInternalServerError, err)
+	}
+
+	basename := "workout_" + strconv.FormatUint(workout.ID, 10) + format.Extension()
+	c.Response().Header().Set(echo.HeaderContentDisposition, "attachment; filename=\""+basename+"\"")
+
+	return c.Blob(http.StatusOK, format.MIMEType(), content)
+}
+
 // DownloadWorkoutAttachment downloads a workout attachment
 // @Summary      Download workout attachment
 // @Tags         workouts
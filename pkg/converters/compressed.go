@@ -0,0 +1,120 @@
+package converters
+
+import (
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// maxDecompressedSize limits the size of a single decompressed file
+const maxDecompressedSize = 512 << 20
+
+var (
+	ErrDecompressedTooLarge   = errors.New("decompressed file is too large")
+	SupportedCompressionTypes = []string{".gz", ".bz2"}
+)
+
+var (
+	gzipMagic  = []byte{0x1f, 0x8b}
+	bzip2Magic = []byte("BZh")
+)
+
+// IsSupportedFile returns whether the filename has a supported file type,
+// optionally followed by a supported compression suffix
+func IsSupportedFile(filename string) bool {
+	filename = strings.ToLower(filename)
+
+	if ext := path.Ext(filename); slices.Contains(SupportedCompressionTypes, ext) {
+		filename = strings.TrimSuffix(filename, ext)
+	}
+
+	return slices.Contains(SupportedFileTypes, path.Ext(filename))
+}
+
+// compressionType detects the compression of the content, by its magic bytes
+// first and its suffix second; it returns an empty string for uncompressed
+// content
+func compressionType(filename string, content []byte) string {
+	switch {
+	case bytes.HasPrefix(content, gzipMagic):
+		return ".gz"
+	case bytes.HasPrefix(content, bzip2Magic):
+		return ".bz2"
+	}
+
+	if ext := strings.ToLower(path.Ext(filename)); slices.Contains(SupportedCompressionTypes, ext) {
+		return ext
+	}
+
+	return ""
+}
+
+// decompress returns the decompressed content and the filename without the
+// compression suffix
+func decompress(compression string, filename string, content []byte) (string, []byte, error) {
+	var (
+		r   io.Reader
+		err error
+	)
+
+	switch compression {
+	case ".gz":
+		r, err = gzip.NewReader(bytes.NewReader(content))
+		if err != nil {
+			return "", nil, err
+		}
+	case ".bz2":
+		r = bzip2.NewReader(bytes.NewReader(content))
+	default:
+		return "", nil, fmt.Errorf("%w: %s", ErrUnsupportedFile, filename)
+	}
+
+	decompressed, err := io.ReadAll(io.LimitReader(r, maxDecompressedSize+1))
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(decompressed) > maxDecompressedSize {
+		return "", nil, fmt.Errorf("%w: %s", ErrDecompressedTooLarge, filename)
+	}
+
+	if strings.EqualFold(path.Ext(filename), compression) {
+		filename = filename[:len(filename)-len(compression)]
+	}
+
+	return filename, decompressed, nil
+}
+
+// parseCompressed parses the decompressed content based on the inner
+// extension. The workouts keep the original compressed bytes, with the
+// compression suffix in the filename, so they can be reparsed later on.
+// Workouts extracted from an archive keep their own file instead.
+func parseCompressed(compression string, filename string, content []byte) ([]*model.Workout, error) {
+	inner, decompressed, err := decompress(compression, filename, content)
+	if err != nil {
+		return nil, err
+	}
+
+	workouts, err := parseContent(inner, decompressed)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.EqualFold(path.Ext(inner), ".zip") {
+		return workouts, nil
+	}
+
+	for _, w := range workouts {
+		w.SetContent(inner+compression, content)
+	}
+
+	return workouts, nil
+}
@@ -0,0 +1,70 @@
+package converters
+
+import (
+	"bytes"
+	"compress/gzip"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func gzipContent(t *testing.T, content []byte) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	zw := gzip.NewWriter(buf)
+
+	_, err := zw.Write(content)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+func TestParseCollection_Gzip(t *testing.T) {
+	content, err := Export(exportWorkout(), ExportFormatFIT)
+	require.NoError(t, err)
+
+	compressed := gzipContent(t, content)
+
+	workouts, err := ParseCollection("activities/123.fit.gz", compressed)
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Len(t, w.Data.Details.Points, exportPointCount)
+	assert.Equal(t, "123.fit.gz", w.GPX.Filename)
+	assert.Equal(t, compressed, w.GPX.Content)
+
+	// The stored file can be parsed again
+	reparsed, err := ParseCollection(w.GPX.Filename, w.GPX.Content)
+	require.NoError(t, err)
+	assert.Len(t, reparsed, 1)
+}
+
+func TestParseCollection_GzipMagicBytes(t *testing.T) {
+	content, err := ExportGPX(exportWorkout())
+	require.NoError(t, err)
+
+	workouts, err := ParseCollection("workout.gpx", gzipContent(t, content))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	assert.Len(t, workouts[0].Data.Details.Points, exportPointCount)
+	assert.Equal(t, "workout.gpx.gz", workouts[0].GPX.Filename)
+}
+
+func TestIsSupportedFile(t *testing.T) {
+	for name, expected := range map[string]bool{
+		"ride.fit":     true,
+		"ride.FIT.GZ":  true,
+		"ride.tcx.bz2": true,
+		"ride.kml":     true,
+		"ride.gz":      false,
+		"ride.txt.gz":  false,
+		"ride.txt":     false,
+	} {
+		assert.Equal(t, expected, IsSupportedFile(name), name)
+	}
+}
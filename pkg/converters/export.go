@@ -0,0 +1,162 @@
+package converters
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
+	"time"
+
+	ap "github.com/jovandeginste/workout-tracker/v2/pkg/activitypub"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// ExportCreator is the creator name written into exported files
+const ExportCreator = "Workout Tracker"
+
+type ExportFormat string
+
+const (
+	ExportFormatGPX     ExportFormat = "gpx"
+	ExportFormatTCX     ExportFormat = "tcx"
+	ExportFormatFIT     ExportFormat = "fit"
+	ExportFormatGeoJSON ExportFormat = "geojson"
+)
+
+var ErrUnsupportedExportFormat = errors.New("unsupported export format")
+
+// ExportFormats lists all formats a workout can be rendered to
+var ExportFormats = []ExportFormat{
+	ExportFormatGPX,
+	ExportFormatTCX,
+	ExportFormatFIT,
+	ExportFormatGeoJSON,
+}
+
+func ParseExportFormat(s string) (ExportFormat, error) {
+	f := ExportFormat(strings.ToLower(strings.TrimSpace(s)))
+	if !slices.Contains(ExportFormats, f) {
+		return "", fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, s)
+	}
+
+	return f, nil
+}
+
+func (f ExportFormat) Extension() string {
+	return "." + string(f)
+}
+
+func (f ExportFormat) MIMEType() string {
+	switch f {
+	case ExportFormatGPX:
+		return "application/gpx+xml"
+	case ExportFormatTCX:
+		return "application/vnd.garmin.tcx+xml"
+	case ExportFormatFIT:
+		return ap.FitMIMEType
+	case ExportFormatGeoJSON:
+		return "application/geo+json"
+	default:
+		return "application/binary"
+	}
+}
+
+// Export renders the workout in the requested format, using the stored map
+// data (points, laps and extra metrics) rather than the original file.
+func Export(workout *model.Workout, format ExportFormat) ([]byte, error) {
+	if workout == nil || workout.Data == nil {
+		return nil, ap.ErrWorkoutMissingData
+	}
+
+	switch format {
+	case ExportFormatGPX:
+		return ExportGPX(workout)
+	case ExportFormatTCX:
+		return ExportTCX(workout)
+	case ExportFormatFIT:
+		return ap.GenerateWorkoutFIT(workout)
+	case ExportFormatGeoJSON:
+		return ExportGeoJSON(workout)
+	default:
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedExportFormat, format)
+	}
+}
+
+func exportPoints(workout *model.Workout) []model.MapPoint {
+	if workout.Data == nil || workout.Data.Details == nil {
+		return nil
+	}
+
+	return workout.Data.Details.Points
+}
+
+func exportStart(workout *model.Workout) time.Time {
+	if !workout.Date.IsZero() {
+		return workout.Date
+	}
+
+	if workout.Data != nil && !workout.Data.Start.IsZero() {
+		return workout.Data.Start
+	}
+
+	return time.Now()
+}
+
+// exportPointTime returns the timestamp of the point, falling back to an
+// offset from the workout start for points without a time
+func exportPointTime(start time.Time, i int, p *model.MapPoint) time.Time {
+	if !p.Time.IsZero() {
+		return p.Time
+	}
+
+	if p.TotalDuration > 0 {
+		return start.Add(p.TotalDuration)
+	}
+
+	return start.Add(time.Duration(i) * time.Second)
+}
+
+// exportValue returns the value, or 0 when it has no value (NaN), for formats
+// that leave out zero values
+func exportValue(v float64) float64 {
+	if math.IsNaN(v) {
+		return 0
+	}
+
+	return v
+}
+
+// exportMetricNames returns the extra metrics of the point that are written
+// as-is, in a stable order; elevation is written separately and metrics
+// without a value (NaN) are skipped
+func exportMetricNames(metrics model.ExtraMetrics) []string {
+	names := make([]string, 0, len(metrics))
+
+	for k, v := range metrics {
+		if k == "elevation" || math.IsNaN(v) {
+			continue
+		}
+
+		names = append(names, k)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// exportLapIndex returns the index of the lap the timestamp belongs to
+func exportLapIndex(laps []model.WorkoutLap, t time.Time) int {
+	idx := 0
+
+	for i, l := range laps {
+		if l.Start.IsZero() || t.Before(l.Start) {
+			break
+		}
+
+		idx = i
+	}
+
+	return idx
+}
@@ -0,0 +1,89 @@
+package converters
+
+import (
+	"encoding/json"
+	"math"
+	"slices"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// ExportGeoJSON renders the workout as a GeoJSON FeatureCollection holding
+// a single LineString. Per-point timestamps and extra metrics are stored in
+// the "coordinateProperties" property (as used by e.g. togeojson), laps in
+// the "laps" property.
+func ExportGeoJSON(workout *model.Workout) ([]byte, error) {
+	start := exportStart(workout)
+
+	points := exportPoints(workout)
+
+	coordinates := make([][]float64, 0, len(points))
+	times := make([]string, 0, len(points))
+	indexes := make([]int, 0, len(points))
+	metricNames := []string{}
+
+	for i, p := range points {
+		if p.Lat == 0 && p.Lng == 0 {
+			continue
+		}
+
+		c := []float64{p.Lng, p.Lat}
+		if ele := p.EnhancedElevation(); !math.IsNaN(ele) {
+			c = append(c, ele)
+		}
+
+		coordinates = append(coordinates, c)
+		times = append(times, exportPointTime(start, i, &p).UTC().Format(time.RFC3339))
+		indexes = append(indexes, i)
+
+		for _, name := range exportMetricNames(p.ExtraMetrics) {
+			if !slices.Contains(metricNames, name) {
+				metricNames = append(metricNames, name)
+			}
+		}
+	}
+
+	coordinateProperties := map[string]any{"times": times}
+
+	for _, name := range metricNames {
+		values := make([]*float64, len(indexes))
+
+		for j, i := range indexes {
+			if v, ok := points[i].ExtraMetrics[name]; ok && !math.IsNaN(v) {
+				values[j] = &v
+			}
+		}
+
+		coordinateProperties[name] = values
+	}
+
+	rawCoordinates, err := json.Marshal(coordinates)
+	if err != nil {
+		return nil, err
+	}
+
+	fc := geoJSONFeatureCollection{
+		Type: "FeatureCollection",
+		Features: []geoJSONFeature{{
+			Type: "Feature",
+			Geometry: geoJSONGeometry{
+				Type:        "LineString",
+				Coordinates: rawCoordinates,
+			},
+			Properties: map[string]any{
+				"name":                 workout.Name,
+				"type":                 workout.Type,
+				"notes":                workout.Notes,
+				"creator":              ExportCreator,
+				"time":                 start.UTC().Format(time.RFC3339),
+				"totalDistance":        workout.TotalDistance(),
+				"totalDuration":        workout.TotalDuration().Seconds(),
+				"laps":                 workout.Data.Laps,
+				"coordinateProperties": coordinateProperties,
+			},
+		}},
+	}
+
+	return json.MarshalIndent(fc, "", "  ")
+}
@@ -0,0 +1,60 @@
+package converters
+
+import (
+	"encoding/xml"
+	"math"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/spf13/cast"
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+// ExportGPX renders the workout as a GPX 1.1 track. GPX has no notion of
+// laps, so every lap ends up in the same track segment; extra metrics are
+// written as point extensions.
+func ExportGPX(workout *model.Workout) ([]byte, error) {
+	start := exportStart(workout)
+
+	g := &gpx.GPX{
+		Creator:     ExportCreator,
+		Name:        workout.Name,
+		Description: workout.Notes,
+		Time:        &start,
+	}
+
+	segment := gpx.GPXTrackSegment{}
+
+	for i, p := range exportPoints(workout) {
+		if p.Lat == 0 && p.Lng == 0 {
+			continue
+		}
+
+		pt := gpx.GPXPoint{
+			Point: gpx.Point{
+				Latitude:  p.Lat,
+				Longitude: p.Lng,
+			},
+			Timestamp: exportPointTime(start, i, &p).UTC(),
+		}
+
+		if ele := p.EnhancedElevation(); !math.IsNaN(ele) {
+			pt.Elevation = *gpx.NewNullableFloat64(ele)
+		}
+
+		for _, name := range exportMetricNames(p.ExtraMetrics) {
+			pt.Extensions.Nodes = append(pt.Extensions.Nodes, gpx.ExtensionNode{
+				XMLName: xml.Name{Local: name}, Data: cast.ToString(p.ExtraMetrics[name]),
+			})
+		}
+
+		segment.Points = append(segment.Points, pt)
+	}
+
+	g.Tracks = append(g.Tracks, gpx.GPXTrack{
+		Name:     workout.Name,
+		Type:     string(workout.Type),
+		Segments: []gpx.GPXTrackSegment{segment},
+	})
+
+	return g.ToXml(gpx.ToXmlParams{Version: "1.1", Indent: true})
+}
@@ -0,0 +1,94 @@
+package converters
+
+import (
+	"encoding/xml"
+	"time"
+
+	"github.com/galeone/tcx"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// ExportTCX renders the workout as a Training Center activity, with one
+// TCX lap per workout lap
+func ExportTCX(workout *model.Workout) ([]byte, error) {
+	start := exportStart(workout)
+
+	act := tcx.Activity{
+		Sport: tcxSport(workout.Type),
+		Id:    start.UTC(),
+		Notes: workout.Notes,
+		Creator: &tcx.Device{
+			Name: ExportCreator,
+		},
+	}
+
+	laps := workout.Data.Laps
+	if len(laps) == 0 {
+		laps = []model.WorkoutLap{{
+			Start:         start,
+			TotalDistance: workout.TotalDistance(),
+			TotalDuration: workout.TotalDuration(),
+			WorkoutStats:  workout.Data.WorkoutStats,
+		}}
+	}
+
+	act.Laps = make([]tcx.Lap, len(laps))
+	for i, l := range laps {
+		lapStart := l.Start
+		if lapStart.IsZero() {
+			lapStart = start
+		}
+
+		act.Laps[i] = tcx.Lap{
+			Start:         lapStart.UTC().Format(time.RFC3339),
+			TotalTime:     l.TotalDuration.Seconds(),
+			Dist:          l.TotalDistance,
+			MaxSpeed:      exportValue(l.MaxSpeed),
+			AvgHr:         exportValue(l.AverageHeartRate),
+			MaxHr:         exportValue(l.MaxHeartRate),
+			Intensity:     "Active",
+			TriggerMethod: "Manual",
+			Trk:           &tcx.Track{},
+		}
+	}
+
+	for i, p := range exportPoints(workout) {
+		t := exportPointTime(start, i, &p)
+		lap := act.Laps[exportLapIndex(laps, t)].Trk
+
+		lap.Pt = append(lap.Pt, tcx.Trackpoint{
+			Time:  t.UTC(),
+			Lat:   p.Lat,
+			Long:  p.Lng,
+			Alt:   exportValue(p.EnhancedElevation()),
+			Dist:  p.TotalDistance,
+			HR:    exportValue(p.ExtraMetrics.Get("heart-rate")),
+			Cad:   exportValue(p.ExtraMetrics.Get("cadence")),
+			Speed: exportValue(p.ExtraMetrics.Get("speed")),
+			Power: exportValue(p.ExtraMetrics.Get("power")),
+		})
+	}
+
+	db := tcx.TCXDB{
+		Acts: &tcx.Activities{Act: []tcx.Activity{act}},
+		Auth: &tcx.Author{Name: ExportCreator},
+	}
+
+	content, err := tcx.ToBytes(db)
+	if err != nil {
+		return nil, err
+	}
+
+	return append([]byte(xml.Header), content...), nil
+}
+
+func tcxSport(t model.WorkoutType) string {
+	switch t {
+	case model.WorkoutTypeRunning:
+		return "Running"
+	case model.WorkoutTypeCycling, model.WorkoutTypeECycling:
+		return "Biking"
+	default:
+		return "Other"
+	}
+}
@@ -0,0 +1,166 @@
+package converters
+
+import (
+	"encoding/json"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/geocoder"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func init() { //nolint:gochecknoinits
+	geocoder.ForceOffline()
+}
+
+const exportPointCount = 120
+
+func exportWorkout() *model.Workout {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	points := make([]model.MapPoint, exportPointCount)
+
+	for i := range points {
+		points[i] = model.MapPoint{
+			Time:          start.Add(time.Duration(i) * time.Second),
+			Lat:           51.0 + float64(i)*0.00003,
+			Lng:           4.0,
+			Elevation:     10 + float64(i)*0.1,
+			TotalDistance: float64(i) * 3.3,
+			TotalDuration: time.Duration(i) * time.Second,
+			ExtraMetrics: model.ExtraMetrics{
+				"heart-rate": float64(120 + i%30),
+				"cadence":    float64(80 + i%5),
+				"power":      float64(200 + i%40),
+			},
+		}
+
+		if i > 0 {
+			points[i].Distance = 3.3
+			points[i].Duration = time.Second
+		}
+	}
+
+	half := start.Add(exportPointCount / 2 * time.Second)
+
+	return &model.Workout{
+		Name: "Morning run",
+		Type: model.WorkoutTypeRunning,
+		Date: start,
+		Data: &model.MapData{
+			WorkoutData: model.WorkoutData{
+				Start:         start,
+				Stop:          points[len(points)-1].Time,
+				TotalDistance: points[len(points)-1].TotalDistance,
+				TotalDuration: points[len(points)-1].TotalDuration,
+				WorkoutStats:  model.WorkoutStats{AverageHeartRate: 134, MaxHeartRate: 149},
+				Laps: []model.WorkoutLap{
+					{Start: start, Stop: half, TotalDistance: 198, TotalDuration: time.Minute},
+					{Start: half, Stop: points[len(points)-1].Time, TotalDistance: 194.7, TotalDuration: time.Minute},
+				},
+			},
+			Details: &model.MapDataDetails{Points: points},
+		},
+	}
+}
+
+func exportAndParse(t *testing.T, format ExportFormat) *model.Workout {
+	t.Helper()
+
+	content, err := Export(exportWorkout(), format)
+	require.NoError(t, err)
+
+	workouts, err := ParseCollection("workout"+format.Extension(), content)
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+	require.NotNil(t, workouts[0].Data)
+	require.NotNil(t, workouts[0].Data.Details)
+
+	return workouts[0]
+}
+
+func assertRoundTripPoints(t *testing.T, w *model.Workout, metrics ...string) {
+	t.Helper()
+
+	points := w.Data.Details.Points
+	require.Len(t, points, exportPointCount)
+	assert.InDelta(t, 51.0, points[0].Lat, 0.00001)
+	assert.InDelta(t, 4.0, points[0].Lng, 0.00001)
+	assert.InDelta(t, 397, w.Data.TotalDistance, 10)
+
+	for _, m := range metrics {
+		assert.Contains(t, w.Data.ExtraMetrics, m)
+		assert.InDelta(t, exportWorkout().Data.Details.Points[7].ExtraMetrics[m], points[7].ExtraMetrics[m], 0.5, m)
+	}
+}
+
+func TestExport_GPX(t *testing.T) {
+	w := exportAndParse(t, ExportFormatGPX)
+
+	assert.Equal(t, "Morning run", w.Name)
+	assertRoundTripPoints(t, w, "heart-rate", "cadence", "power")
+}
+
+func TestExport_GPX_SkipsNaN(t *testing.T) {
+	w := exportWorkout()
+	w.Data.Details.Points[7].ExtraMetrics["temperature"] = math.NaN()
+
+	content, err := Export(w, ExportFormatGPX)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "NaN")
+}
+
+func TestExport_TCX(t *testing.T) {
+	w := exportAndParse(t, ExportFormatTCX)
+
+	assertRoundTripPoints(t, w, "heart-rate", "cadence", "power")
+}
+
+func TestExport_TCX_SkipsNaN(t *testing.T) {
+	w := exportWorkout()
+	w.Data.Details.Points[7].Elevation = math.NaN()
+	w.Data.Details.Points[7].ExtraMetrics["heart-rate"] = math.NaN()
+	w.Data.Details.Points[7].ExtraMetrics["power"] = math.NaN()
+
+	content, err := Export(w, ExportFormatTCX)
+	require.NoError(t, err)
+	assert.NotContains(t, string(content), "NaN")
+}
+
+func TestExport_FIT(t *testing.T) {
+	w := exportAndParse(t, ExportFormatFIT)
+
+	assertRoundTripPoints(t, w, "heart-rate", "cadence", "power")
+	assert.Len(t, w.Data.Laps, 2)
+	assert.InDelta(t, 134, w.Data.AverageHeartRate, 0.5)
+}
+
+func TestExport_GeoJSON(t *testing.T) {
+	content, err := Export(exportWorkout(), ExportFormatGeoJSON)
+	require.NoError(t, err)
+
+	var fc geoJSONFeatureCollection
+	require.NoError(t, json.Unmarshal(content, &fc))
+	require.Len(t, fc.Features, 1)
+	assert.Equal(t, "LineString", fc.Features[0].Geometry.Type)
+	assert.Len(t, fc.Features[0].Properties["laps"], 2)
+
+	w := exportAndParse(t, ExportFormatGeoJSON)
+
+	assert.Equal(t, "Morning run", w.Name)
+	assertRoundTripPoints(t, w, "heart-rate", "cadence", "power")
+}
+
+func TestExport_Unsupported(t *testing.T) {
+	_, err := ParseExportFormat("kml")
+	require.ErrorIs(t, err, ErrUnsupportedExportFormat)
+
+	f, err := ParseExportFormat("GPX")
+	require.NoError(t, err)
+	assert.Equal(t, ExportFormatGPX, f)
+
+	_, err = Export(&model.Workout{}, ExportFormatGPX)
+	require.Error(t, err)
+}
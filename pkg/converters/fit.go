@@ -12,11 +12,16 @@ import (
 	"github.com/muktihari/fit/decoder"
 	"github.com/muktihari/fit/kit/semicircles"
 	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
 	"github.com/muktihari/fit/profile/typedef"
 	"github.com/spf13/cast"
 	"github.com/tkrajina/gpxgo/gpx"
 )
 
+// ErrNoSessionsFound is returned for FIT files without any session, e.g.
+// monitoring or settings files
+var ErrNoSessionsFound = errors.New("no sessions found")
+
 func ParseFit(content []byte, filename string) ([]*model.Workout, error) {
 	dec := decoder.New(bytes.NewReader(content), decoder.WithIgnoreChecksum())
 
@@ -27,7 +32,11 @@ func ParseFit(content []byte, filename string) ([]*model.Workout, error) {
 
 	act := filedef.NewActivity(f.Messages...)
 	if len(act.Sessions) == 0 {
-		return nil, errors.New("no sessions found")
+		return nil, ErrNoSessionsFound
+	}
+
+	if isFitMultisport(act) {
+		return parseFitMultisport(act, content, filename), nil
 	}
 
 	activityTime := fitActivityStartTime(act)
@@ -40,36 +49,8 @@ func ParseFit(content []byte, filename string) ([]*model.Workout, error) {
 	workouts := make([]*model.Workout, 0, len(act.Sessions))
 
 	for _, session := range act.Sessions {
-		startTime := firstNonZeroTime(session.StartTime.Local(), activityTime)
-
-		moveDuration := durationFromSeconds(session.TotalTimerTimeScaled())
-		elapsedDuration := durationFromSeconds(session.TotalElapsedTimeScaled())
-		pauseDuration := maxDuration(elapsedDuration-moveDuration, 0)
-
-		w := &model.Workout{
-			Data: cloneMapData(data),
-			Date: startTime,
-		}
-
-		if w.Data != nil {
-			w.Data.WorkoutData.MergeNonZero(model.WorkoutData{
-				Name:          formatFitWorkoutName(session.Sport.String(), startTime),
-				Type:          session.Sport.String(),
-				Start:         startTime,
-				Stop:          startTime.Add(elapsedDuration),
-				TotalDistance: session.TotalDistanceScaled(),
-				TotalDuration: elapsedDuration,
-				PauseDuration: pauseDuration,
-				WorkoutStats:  stats,
-				Laps:          laps,
-			})
-		}
-
-		if session.SubSport != typedef.SubSportInvalid {
-			w.Data.WorkoutData.SubType = session.SubSport.String()
-		}
+		w := fitSessionWorkout(session, activityTime, data, laps, stats)
 
-		w.Name = w.Data.WorkoutData.Name
 		setContentAndName(w, filename, "fit", content)
 		w.UpdateAverages()
 		w.UpdateExtraMetrics()
@@ -80,6 +61,53 @@ func ParseFit(content []byte, filename string) ([]*model.Workout, error) {
 	return workouts, nil
 }
 
+// fitSessionWorkout converts a session, with the data of its records and
+// laps, to a workout
+func fitSessionWorkout(session *mesgdef.Session, activityTime time.Time, data *model.MapData, laps []model.WorkoutLap, stats model.WorkoutStats) *model.Workout {
+	startTime := firstNonZeroTime(session.StartTime.Local(), activityTime)
+
+	moveDuration := durationFromSeconds(session.TotalTimerTimeScaled())
+	elapsedDuration := durationFromSeconds(session.TotalElapsedTimeScaled())
+	pauseDuration := maxDuration(elapsedDuration-moveDuration, 0)
+
+	w := &model.Workout{
+		Data: cloneMapData(data),
+		Date: startTime,
+	}
+
+	if w.Data != nil {
+		w.Data.WorkoutData.MergeNonZero(model.WorkoutData{
+			Name:          formatFitWorkoutName(session.Sport.String(), startTime),
+			Type:          fitSportType(session),
+			Start:         startTime,
+			Stop:          startTime.Add(elapsedDuration),
+			TotalDistance: session.TotalDistanceScaled(),
+			TotalDuration: elapsedDuration,
+			PauseDuration: pauseDuration,
+			WorkoutStats:  stats,
+			Laps:          laps,
+		})
+	}
+
+	if session.SubSport != typedef.SubSportInvalid {
+		w.Data.WorkoutData.SubType = session.SubSport.String()
+	}
+
+	w.Name = w.Data.WorkoutData.Name
+
+	return w
+}
+
+// fitSportType returns the type of the session: the sport, or the sub sport
+// for strength training, since the sport (training) is too generic
+func fitSportType(session *mesgdef.Session) string {
+	if session.SubSport == typedef.SubSportStrengthTraining {
+		return session.SubSport.String()
+	}
+
+	return session.Sport.String()
+}
+
 //gocyclo:ignore
 func parseLaps(act *filedef.Activity) []model.WorkoutLap {
 	laps := make([]model.WorkoutLap, 0, len(act.Laps))
@@ -289,10 +317,12 @@ func buildGPXFromActivity(act *filedef.Activity) *gpx.GPX {
 		s := act.Sessions[0]
 		gpxFile.AppendTrack(&gpx.GPXTrack{
 			Name: s.SportProfileName,
-			Type: s.Sport.String(),
+			Type: fitSportType(s),
 		})
 	}
 
+	devFields := fitDeveloperFields(act)
+
 	for _, r := range act.Records {
 		p := &gpx.GPXPoint{
 			Timestamp: r.Timestamp,
@@ -339,6 +369,12 @@ func buildGPXFromActivity(act *filedef.Activity) *gpx.GPX {
 			gpxExtensionData["power"] = cast.ToString(r.Power)
 		}
 
+		for key, value := range fitRecordExtraMetrics(r, devFields) {
+			if _, ok := gpxExtensionData[key]; !ok {
+				gpxExtensionData[key] = cast.ToString(value)
+			}
+		}
+
 		for key, value := range gpxExtensionData {
 			p.Extensions.Nodes = append(p.Extensions.Nodes, gpx.ExtensionNode{
 				XMLName: xml.Name{Local: key}, Data: value,
@@ -357,11 +393,33 @@ func buildGPXFromActivity(act *filedef.Activity) *gpx.GPX {
 func mapDataFromActivity(act *filedef.Activity, gpxFile *gpx.GPX) *model.MapData {
 	data := model.MapDataFromGPX(gpxFile)
 
-	if data != nil && data.Details != nil && len(data.Details.Points) > 0 {
-		return data
+	if data == nil || data.Details == nil || len(data.Details.Points) == 0 {
+		data = buildMapDataWithoutPositions(act)
+	}
+
+	if data != nil && data.Details != nil {
+		data.Details.RRIntervals = fitRRIntervals(act)
+	}
+
+	if lengths := fitLengths(act); len(lengths) > 0 {
+		if data == nil {
+			data = &model.MapData{}
+		}
+
+		data.Lengths = lengths
+		data.PoolLength = fitPoolLength(act)
+	}
+
+	if sets := fitSets(act); len(sets) > 0 {
+		if data == nil {
+			data = &model.MapData{}
+		}
+
+		data.Sets = sets
+		data.UpdateSetTotals()
 	}
 
-	return buildMapDataWithoutPositions(act)
+	return data
 }
 
 // buildMapDataWithoutPositions constructs minimal map data using FIT records
@@ -389,6 +447,7 @@ func buildMapDataWithoutPositions(act *filedef.Activity) *model.MapData {
 	)
 
 	startTime := fitActivityStartTime(act)
+	devFields := fitDeveloperFields(act)
 
 	for i, r := range act.Records {
 		ts := r.Timestamp.Local()
@@ -473,6 +532,11 @@ func buildMapDataWithoutPositions(act *filedef.Activity) *model.MapData {
 		if speed > 0 {
 			extra.Set("speed", speed)
 		}
+		for key, value := range fitRecordExtraMetrics(r, devFields) {
+			if _, ok := extra[key]; !ok {
+				extra.Set(key, value)
+			}
+		}
 
 		elevationValue := elevation
 		if math.IsNaN(elevationValue) {
@@ -528,7 +592,7 @@ func buildMapDataWithoutPositions(act *filedef.Activity) *model.MapData {
 	// Populate workout type/name from the first session when available
 	if len(act.Sessions) > 0 {
 		s := act.Sessions[0]
-		data.WorkoutData.Type = s.Sport.String()
+		data.WorkoutData.Type = fitSportType(s)
 		data.WorkoutData.SubType = s.SubSport.String()
 		if data.WorkoutData.Name == "" {
 			data.WorkoutData.Name = formatFitWorkoutName(s.Sport.String(), startTime)
@@ -581,7 +645,10 @@ func cloneMapData(src *model.MapData) *model.MapData {
 
 	clone := *src
 	if src.Details != nil {
-		clone.Details = &model.MapDataDetails{Points: src.Details.Points}
+		clone.Details = &model.MapDataDetails{
+			Points:      src.Details.Points,
+			RRIntervals: src.Details.RRIntervals,
+		}
 	}
 
 	return &clone
@@ -600,8 +667,10 @@ func fitActivityStartTime(act *filedef.Activity) time.Time {
 		return time.Time{}
 	}
 
-	if t := act.Activity.LocalTimestamp.Local(); !t.IsZero() {
-		return t
+	if act.Activity != nil {
+		if t := act.Activity.LocalTimestamp.Local(); !t.IsZero() {
+			return t
+		}
 	}
 
 	for _, s := range act.Sessions {
@@ -0,0 +1,213 @@
+package converters
+
+import (
+	"math"
+	"strings"
+
+	"github.com/muktihari/fit/profile/basetype"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/spf13/cast"
+)
+
+// fitRecordFields are the standard record fields that are kept as extra
+// metrics, besides the ones with a dedicated statistic (heart rate, cadence,
+// power, ...); invalid fields are NaN and skipped
+var fitRecordFields = map[string]func(r *mesgdef.Record) float64{
+	"grade":                          func(r *mesgdef.Record) float64 { return r.GradeScaled() },
+	"vertical-oscillation":           func(r *mesgdef.Record) float64 { return r.VerticalOscillationScaled() }, // mm
+	"ground-contact-time":            func(r *mesgdef.Record) float64 { return r.StanceTimeScaled() },          // ms
+	"ground-contact-time-percent":    func(r *mesgdef.Record) float64 { return r.StanceTimePercentScaled() },
+	"ground-contact-time-balance":    func(r *mesgdef.Record) float64 { return r.StanceTimeBalanceScaled() },
+	"vertical-ratio":                 func(r *mesgdef.Record) float64 { return r.VerticalRatioScaled() },
+	"step-length":                    func(r *mesgdef.Record) float64 { return r.StepLengthScaled() }, // mm
+	"core-temperature":               func(r *mesgdef.Record) float64 { return r.CoreTemperatureScaled() },
+	"left-torque-effectiveness":      func(r *mesgdef.Record) float64 { return r.LeftTorqueEffectivenessScaled() },
+	"right-torque-effectiveness":     func(r *mesgdef.Record) float64 { return r.RightTorqueEffectivenessScaled() },
+	"left-pedal-smoothness":          func(r *mesgdef.Record) float64 { return r.LeftPedalSmoothnessScaled() },
+	"right-pedal-smoothness":         func(r *mesgdef.Record) float64 { return r.RightPedalSmoothnessScaled() },
+	"combined-pedal-smoothness":      func(r *mesgdef.Record) float64 { return r.CombinedPedalSmoothnessScaled() },
+	"saturated-hemoglobin-percent":   func(r *mesgdef.Record) float64 { return r.SaturatedHemoglobinPercentScaled() },
+	"total-hemoglobin-concentration": func(r *mesgdef.Record) float64 { return r.TotalHemoglobinConcScaled() },
+	"depth":                          func(r *mesgdef.Record) float64 { return r.DepthScaled() },
+	"cycle-length":                   func(r *mesgdef.Record) float64 { return r.CycleLength16Scaled() },
+	"motor-power": func(r *mesgdef.Record) float64 {
+		if r.MotorPower == math.MaxUint16 {
+			return math.NaN()
+		}
+
+		return float64(r.MotorPower)
+	},
+	"grit": func(r *mesgdef.Record) float64 { return float64(r.Grit) },
+	"flow": func(r *mesgdef.Record) float64 { return float64(r.Flow) },
+}
+
+// fitDeveloperFieldNames maps the (normalized) names of well-known developer
+// fields, e.g. from Stryd, CORE and Moxy, to the names of the extra metrics;
+// other developer fields keep their normalized name
+var fitDeveloperFieldNames = map[string]string{
+	"power":                                "power",
+	"form-power":                           "form-power",
+	"air-power":                            "air-power",
+	"leg-spring-stiffness":                 "leg-spring-stiffness",
+	"ground-time":                          "ground-contact-time",
+	"gct":                                  "ground-contact-time",
+	"stance-time":                          "ground-contact-time",
+	"vertical-oscillation":                 "vertical-oscillation",
+	"cadence":                              "cadence",
+	"core-temperature":                     "core-temperature",
+	"core-temp":                            "core-temperature",
+	"core-body-temperature":                "core-temperature",
+	"body-temperature":                     "core-temperature",
+	"skin-temperature":                     "skin-temperature",
+	"skin-temp":                            "skin-temperature",
+	"heat-strain-index":                    "heat-strain-index",
+	"hsi":                                  "heat-strain-index",
+	"smo2":                                 "saturated-hemoglobin-percent",
+	"current-saturated-hemoglobin-percent": "saturated-hemoglobin-percent",
+	"thb":                                  "total-hemoglobin-concentration",
+	"total-hemoglobin-concentration":       "total-hemoglobin-concentration",
+}
+
+// fitDeveloperField is the description of a developer field, used to convert
+// its values to an extra metric
+type fitDeveloperField struct {
+	name     string
+	units    string
+	scale    float64
+	offset   float64
+	baseType basetype.BaseType
+}
+
+// fitDeveloperFieldKey identifies a developer field: the index of the
+// developer (app) and the number of the field within that app
+type fitDeveloperFieldKey struct {
+	developerDataIndex uint8
+	number             uint8
+}
+
+// fitDeveloperFields returns the developer fields of the activity, by their
+// key, as described by the field descriptions
+func fitDeveloperFields(act *filedef.Activity) map[fitDeveloperFieldKey]fitDeveloperField {
+	fields := map[fitDeveloperFieldKey]fitDeveloperField{}
+
+	for _, d := range act.FieldDescriptions {
+		if d == nil || len(d.FieldName) == 0 {
+			continue
+		}
+
+		name := normalizeFitFieldName(d.FieldName[0])
+		if name == "" {
+			continue
+		}
+
+		if known, ok := fitDeveloperFieldNames[name]; ok {
+			name = known
+		}
+
+		f := fitDeveloperField{name: name, scale: 1, baseType: d.FitBaseTypeId}
+
+		if len(d.Units) > 0 {
+			f.units = strings.ToLower(strings.TrimSpace(d.Units[0]))
+		}
+
+		if d.Scale != 0 && d.Scale != math.MaxUint8 {
+			f.scale = float64(d.Scale)
+		}
+
+		if d.Offset != math.MaxInt8 {
+			f.offset = float64(d.Offset)
+		}
+
+		fields[fitDeveloperFieldKey{d.DeveloperDataIndex, d.FieldDefinitionNumber}] = f
+	}
+
+	return fields
+}
+
+// normalizeFitFieldName converts a developer field name (e.g. "Leg Spring
+// Stiffness", "core_temperature") to the style of the extra metrics
+func normalizeFitFieldName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = strings.NewReplacer(" ", "-", "_", "-").Replace(name)
+
+	return strings.Trim(name, "-")
+}
+
+// convert returns the value of the developer field in the units used by the
+// extra metrics: durations in ms, lengths in mm, temperatures in °C
+func (f fitDeveloperField) convert(value float64) float64 {
+	value = value/f.scale - f.offset
+
+	switch f.units {
+	case "s":
+		if f.name == "ground-contact-time" {
+			return value * 1000
+		}
+	case "cm":
+		if f.name == "vertical-oscillation" {
+			return value * 10
+		}
+	case "m":
+		if f.name == "vertical-oscillation" {
+			return value * 1000
+		}
+	case "°f", "f", "degf", "fahrenheit":
+		return (value - 32) * 5 / 9
+	}
+
+	return value
+}
+
+// fitRecordExtraMetrics returns the extra metrics of a record which are not
+// handled by the parser itself: the standard fields in fitRecordFields and
+// the (numeric) developer fields
+func fitRecordExtraMetrics(r *mesgdef.Record, devFields map[fitDeveloperFieldKey]fitDeveloperField) map[string]float64 {
+	metrics := map[string]float64{}
+
+	for name, value := range fitRecordFields {
+		if v := value(r); !math.IsNaN(v) && !math.IsInf(v, 0) {
+			metrics[name] = v
+		}
+	}
+
+	for _, df := range r.DeveloperFields {
+		f, ok := devFields[fitDeveloperFieldKey{df.DeveloperDataIndex, df.Num}]
+		if !ok || !df.Value.Valid(f.baseType) {
+			continue
+		}
+
+		// Native values (e.g. the power from the watch) take precedence
+		if _, ok := metrics[f.name]; ok || fitNativeMetric(r, f.name) {
+			continue
+		}
+
+		v, err := cast.ToFloat64E(df.Value.Any())
+		if err != nil {
+			continue
+		}
+
+		if v = f.convert(v); !math.IsNaN(v) && !math.IsInf(v, 0) {
+			metrics[f.name] = v
+		}
+	}
+
+	return metrics
+}
+
+// fitNativeMetric returns whether the record has a valid native value for a
+// metric which is parsed separately
+func fitNativeMetric(r *mesgdef.Record, name string) bool {
+	switch name {
+	case "power":
+		return r.Power != math.MaxUint16
+	case "cadence":
+		return r.Cadence != math.MaxUint8
+	case "heart-rate":
+		return r.HeartRate != math.MaxUint8
+	case "temperature":
+		return r.Temperature != math.MaxInt8
+	default:
+		return false
+	}
+}
@@ -0,0 +1,144 @@
+package converters
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/encoder"
+	"github.com/muktihari/fit/kit/semicircles"
+	"github.com/muktihari/fit/profile/basetype"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDeveloperFieldsFIT builds a run with Stryd-like developer fields and
+// running dynamics; the Stryd power is only recorded by the developer field
+func testDeveloperFieldsFIT(t *testing.T, positions bool) []byte {
+	t.Helper()
+
+	start := time.Date(2024, 5, 1, 7, 0, 0, 0, time.UTC)
+
+	act := filedef.NewActivity()
+	act.FileId.
+		SetType(typedef.FileActivity).
+		SetTimeCreated(start).
+		SetManufacturer(typedef.ManufacturerDevelopment)
+
+	act.DeveloperDataIds = append(act.DeveloperDataIds, mesgdef.NewDeveloperDataId(nil).
+		SetDeveloperDataIndex(0).
+		SetApplicationVersion(1))
+
+	for num, f := range []struct {
+		name  string
+		units string
+		scale uint8
+	}{
+		{name: "Power", units: "Watts", scale: 1},
+		{name: "Leg Spring Stiffness", units: "KN/m", scale: 1},
+		{name: "Ground Time", units: "s", scale: 100},
+		{name: "Unknown Field", units: "", scale: 1},
+	} {
+		act.FieldDescriptions = append(act.FieldDescriptions, mesgdef.NewFieldDescription(nil).
+			SetDeveloperDataIndex(0).
+			SetFieldDefinitionNumber(uint8(num)).
+			SetFitBaseTypeId(basetype.Uint16).
+			SetFieldName([]string{f.name}).
+			SetUnits([]string{f.units}).
+			SetScale(f.scale).
+			SetOffset(0))
+	}
+
+	for i := range 5 {
+		r := mesgdef.NewRecord(nil).
+			SetTimestamp(start.Add(time.Duration(i)*time.Second)).
+			SetDistanceScaled(float64(i)*3).
+			SetHeartRate(140).
+			SetVerticalOscillationScaled(80).
+			SetStanceTimeScaled(250).
+			SetDeveloperFields(
+				proto.DeveloperField{DeveloperDataIndex: 0, Num: 0, Value: proto.Uint16(uint16(300 + i))},
+				proto.DeveloperField{DeveloperDataIndex: 0, Num: 1, Value: proto.Uint16(10)},
+				proto.DeveloperField{DeveloperDataIndex: 0, Num: 2, Value: proto.Uint16(24)},
+				proto.DeveloperField{DeveloperDataIndex: 0, Num: 3, Value: proto.Uint16(7)},
+			)
+
+		if positions {
+			r.SetPositionLat(semicircles.ToSemicircles(51.0 + float64(i)*0.0001)).
+				SetPositionLong(semicircles.ToSemicircles(4.0))
+		}
+
+		act.Records = append(act.Records, r)
+	}
+
+	act.Sessions = append(act.Sessions, mesgdef.NewSession(nil).
+		SetTimestamp(start.Add(4*time.Second)).
+		SetStartTime(start).
+		SetSport(typedef.SportRunning).
+		SetTotalElapsedTimeScaled(4).
+		SetTotalTimerTimeScaled(4).
+		SetTotalDistanceScaled(12))
+
+	fitData := act.ToFIT(nil)
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, encoder.New(buf, encoder.WithProtocolVersion(proto.V2)).Encode(&fitData))
+
+	return buf.Bytes()
+}
+
+func TestParseFit_DeveloperFields(t *testing.T) {
+	for _, positions := range []bool{true, false} {
+		workouts, err := ParseCollection("stryd.fit", testDeveloperFieldsFIT(t, positions))
+		require.NoError(t, err)
+		require.Len(t, workouts, 1)
+
+		data := workouts[0].Data
+		require.NotNil(t, data.Details)
+		require.Len(t, data.Details.Points, 5)
+
+		p := data.Details.Points[2]
+		assert.InDelta(t, 302, p.ExtraMetrics["power"], 0.001)
+		assert.InDelta(t, 10, p.ExtraMetrics["leg-spring-stiffness"], 0.001)
+		assert.InDelta(t, 7, p.ExtraMetrics["unknown-field"], 0.001)
+		// The native ground contact time takes precedence over the developer field
+		assert.InDelta(t, 250, p.ExtraMetrics["ground-contact-time"], 0.001)
+		assert.InDelta(t, 80, p.ExtraMetrics["vertical-oscillation"], 0.001)
+		assert.InDelta(t, 140, p.ExtraMetrics["heart-rate"], 0.001)
+
+		assert.Contains(t, data.ExtraMetrics, "leg-spring-stiffness")
+		assert.Contains(t, data.ExtraMetrics, "ground-contact-time")
+
+		stats, ok := data.Details.StatsForRange(0, 4)
+		require.True(t, ok)
+		assert.InDelta(t, 302, stats.AveragePower, 0.001)
+		assert.InDelta(t, 10, stats.ExtraMetrics["leg-spring-stiffness"].Average, 0.001)
+		assert.InDelta(t, 80, stats.ExtraMetrics["vertical-oscillation"].Max, 0.001)
+		assert.NotContains(t, stats.ExtraMetrics, "power")
+	}
+}
+
+func TestFitDeveloperField_Convert(t *testing.T) {
+	tests := []struct {
+		field    fitDeveloperField
+		value    float64
+		expected float64
+	}{
+		{fitDeveloperField{name: "ground-contact-time", units: "s", scale: 100}, 24, 240},
+		{fitDeveloperField{name: "vertical-oscillation", units: "cm", scale: 10}, 85, 85},
+		{fitDeveloperField{name: "core-temperature", units: "°f", scale: 1}, 100.4, 38},
+		{fitDeveloperField{name: "form-power", units: "watts", scale: 1, offset: 10}, 80, 70},
+	}
+
+	for _, tt := range tests {
+		assert.InDelta(t, tt.expected, tt.field.convert(tt.value), 0.001, tt.field.name)
+	}
+}
+
+func TestNormalizeFitFieldName(t *testing.T) {
+	assert.Equal(t, "leg-spring-stiffness", normalizeFitFieldName(" Leg Spring Stiffness "))
+	assert.Equal(t, "core-temperature", normalizeFitFieldName("core_temperature"))
+}
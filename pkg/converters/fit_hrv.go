@@ -0,0 +1,57 @@
+package converters
+
+import (
+	"math"
+	"time"
+
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+)
+
+// fitRRIntervals returns the beat-to-beat (RR) intervals of the hrv messages,
+// in ms; chest straps record them in addition to the heart rate
+func fitRRIntervals(act *filedef.Activity) []float64 {
+	var intervals []float64
+
+	for _, h := range act.HRVs {
+		for _, t := range h.Time {
+			if t == math.MaxUint16 {
+				continue
+			}
+
+			// Scaled by 1000 (seconds)
+			intervals = append(intervals, float64(t))
+		}
+	}
+
+	return intervals
+}
+
+// fitHRVBetween returns the hrv messages with the beats between start and
+// end; hrv messages have no timestamp, so the beats are timed from the start
+// of the first session
+func fitHRVBetween(act *filedef.Activity, start, end time.Time) []*mesgdef.Hrv {
+	if len(act.HRVs) == 0 || len(act.Sessions) == 0 {
+		return nil
+	}
+
+	beat := act.Sessions[0].StartTime
+
+	var times []uint16
+
+	for _, interval := range fitRRIntervals(act) {
+		beat = beat.Add(time.Duration(interval * float64(time.Millisecond)))
+
+		if beat.Before(start) || (!end.IsZero() && !beat.Before(end)) {
+			continue
+		}
+
+		times = append(times, uint16(interval))
+	}
+
+	if len(times) == 0 {
+		return nil
+	}
+
+	return []*mesgdef.Hrv{mesgdef.NewHrv(nil).SetTime(times)}
+}
@@ -0,0 +1,82 @@
+package converters
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/encoder"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var hrvStart = time.Date(2024, 4, 1, 18, 0, 0, 0, time.UTC)
+
+// testHRVActivity builds a run of 10 seconds at 120 bpm, with the RR intervals
+// in hrv messages of 5 beats each
+func testHRVActivity() *filedef.Activity {
+	act := filedef.NewActivity()
+	act.FileId.
+		SetType(typedef.FileActivity).
+		SetTimeCreated(hrvStart).
+		SetManufacturer(typedef.ManufacturerDevelopment)
+
+	act.Sessions = append(act.Sessions, mesgdef.NewSession(nil).
+		SetTimestamp(hrvStart.Add(10*time.Second)).
+		SetStartTime(hrvStart).
+		SetSport(typedef.SportRunning).
+		SetTotalElapsedTimeScaled(10).
+		SetTotalTimerTimeScaled(10))
+
+	for i := range 11 {
+		act.Records = append(act.Records, mesgdef.NewRecord(nil).
+			SetTimestamp(hrvStart.Add(time.Duration(i)*time.Second)).
+			SetDistanceScaled(float64(i)*3).
+			SetHeartRate(120))
+	}
+
+	for range 4 {
+		act.HRVs = append(act.HRVs, mesgdef.NewHrv(nil).
+			SetTime([]uint16{500, 500, 500, 500, math.MaxUint16}))
+	}
+
+	return act
+}
+
+func TestParseFit_HRV(t *testing.T) {
+	fitData := testHRVActivity().ToFIT(nil)
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, encoder.New(buf).Encode(&fitData))
+
+	workouts, err := ParseCollection("hrv.fit", buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	require.True(t, w.HasHRV())
+	assert.Len(t, w.Data.Details.RRIntervals, 16)
+	assert.InDelta(t, 500, w.Data.Details.RRIntervals[0], 0.001)
+
+	hrv, err := w.HRV(0, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 120, hrv.HeartRate, 0.001)
+}
+
+func TestFitHRVBetween(t *testing.T) {
+	act := testHRVActivity()
+
+	// The beats at 0.5, 1.0, ..., 8.0 seconds
+	hrvs := fitHRVBetween(act, hrvStart.Add(2*time.Second), hrvStart.Add(4*time.Second))
+	require.Len(t, hrvs, 1)
+	assert.Len(t, hrvs[0].Time, 4)
+
+	hrvs = fitHRVBetween(act, hrvStart.Add(6*time.Second), time.Time{})
+	require.Len(t, hrvs, 1)
+	assert.Len(t, hrvs[0].Time, 5)
+
+	assert.Nil(t, fitHRVBetween(act, hrvStart.Add(time.Minute), time.Time{}))
+}
@@ -0,0 +1,252 @@
+package converters
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/muktihari/fit/decoder"
+	"github.com/muktihari/fit/kit/datetime"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/profile/untyped/mesgnum"
+	"github.com/muktihari/fit/proto"
+	"gorm.io/datatypes"
+)
+
+var (
+	// ErrNotMeasurementFile is returned for files that do not contain daily
+	// measurements, e.g. activities
+	ErrNotMeasurementFile = errors.New("not a measurement file")
+	// ErrNoMeasurementsFound is returned for measurement files without any
+	// valid measurement
+	ErrNoMeasurementsFound = errors.New("no measurements found")
+)
+
+// fitMeasurementFileTypes are the FIT file types with daily measurements
+var fitMeasurementFileTypes = []typedef.File{
+	typedef.FileWeight,
+	typedef.FileMonitoringA,
+	typedef.FileMonitoringB,
+	typedef.FileMonitoringDaily,
+}
+
+// ParseMeasurements parses the daily measurements of a FIT weight-scale or
+// monitoring file, optionally compressed; it returns ErrNotMeasurementFile
+// for any other file
+func ParseMeasurements(filename string, content []byte) ([]*model.Measurement, error) {
+	if compression := compressionType(filename, content); compression != "" {
+		inner, decompressed, err := decompress(compression, filename, content)
+		if err != nil {
+			return nil, err
+		}
+
+		filename, content = inner, decompressed
+	}
+
+	if !strings.EqualFold(path.Ext(filename), ".fit") {
+		return nil, ErrNotMeasurementFile
+	}
+
+	return ParseFitMeasurements(content)
+}
+
+// ParseFitMeasurements returns a measurement per day of a FIT weight-scale
+// or monitoring file, ordered by date
+func ParseFitMeasurements(content []byte) ([]*model.Measurement, error) {
+	dec := decoder.New(bytes.NewReader(content), decoder.WithIgnoreChecksum())
+
+	f, err := dec.Decode()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode FIT file: %w", err)
+	}
+
+	if len(f.Messages) == 0 || f.Messages[0].Num != mesgnum.FileId {
+		return nil, ErrNotMeasurementFile
+	}
+
+	if !slices.Contains(fitMeasurementFileTypes, mesgdef.NewFileId(&f.Messages[0]).Type) {
+		return nil, ErrNotMeasurementFile
+	}
+
+	days := fitMeasurementDays{}
+	days.addMessages(f.Messages)
+
+	return days.measurements(), nil
+}
+
+// fitMeasurementDays collects the measurements per day
+type fitMeasurementDays map[time.Time]*fitMeasurementDay
+
+type fitMeasurementDay struct {
+	model.Measurement
+
+	// The accumulated cycles per activity type; the steps of the day are
+	// the sum of the walking and running cycles
+	cycles map[typedef.ActivityType]uint32
+}
+
+// addMessages adds the weight-scale, monitoring and monitoring heart rate
+// messages; monitoring messages may only have the lower 16 bits of their
+// timestamp, relative to the previous full timestamp
+func (days fitMeasurementDays) addMessages(messages []proto.Message) {
+	var (
+		loc      = time.Local
+		lastTime time.Time
+	)
+
+	for i := range messages {
+		switch messages[i].Num {
+		case mesgnum.MonitoringInfo:
+			info := mesgdef.NewMonitoringInfo(&messages[i])
+			if !info.Timestamp.IsZero() && !info.LocalTimestamp.IsZero() {
+				loc = time.FixedZone("", int(info.LocalTimestamp.Sub(info.Timestamp).Seconds()))
+			}
+
+			lastTime = firstNonZeroTime(info.Timestamp, lastTime)
+		case mesgnum.WeightScale:
+			ws := mesgdef.NewWeightScale(&messages[i])
+			days.addWeightScale(ws, fitLocalDate(ws.Timestamp, loc))
+		case mesgnum.MonitoringHrData:
+			hr := mesgdef.NewMonitoringHrData(&messages[i])
+			days.addRestingHeartRate(hr, fitLocalDate(hr.Timestamp, loc))
+		case mesgnum.Monitoring:
+			m := mesgdef.NewMonitoring(&messages[i])
+
+			t := m.Timestamp
+			if t.IsZero() && !lastTime.IsZero() && m.Timestamp16 != math.MaxUint16 {
+				delta := m.Timestamp16 - uint16(datetime.ToUint32(lastTime))
+				t = lastTime.Add(time.Duration(delta) * time.Second)
+			}
+
+			if t.IsZero() {
+				continue
+			}
+
+			lastTime = t
+
+			days.addMonitoring(m, fitLocalDate(t, loc))
+		}
+	}
+}
+
+func (days fitMeasurementDays) day(date time.Time) *fitMeasurementDay {
+	d, ok := days[date]
+	if !ok {
+		d = &fitMeasurementDay{
+			Measurement: model.Measurement{Date: datatypes.Date(date)},
+			cycles:      map[typedef.ActivityType]uint32{},
+		}
+		days[date] = d
+	}
+
+	return d
+}
+
+// addWeightScale adds the body composition of the weigh-in; the last
+// weigh-in of a day wins
+func (days fitMeasurementDays) addWeightScale(ws *mesgdef.WeightScale, date time.Time) {
+	if ws.Timestamp.IsZero() {
+		return
+	}
+
+	m := &days.day(date).Measurement
+
+	if ws.Weight != typedef.WeightInvalid && ws.Weight != typedef.WeightCalculating {
+		m.Weight = ws.WeightScaled()
+	}
+
+	if ws.PercentFat != math.MaxUint16 {
+		m.BodyFat = ws.PercentFatScaled()
+	}
+
+	if ws.MuscleMass != math.MaxUint16 {
+		m.MuscleMass = ws.MuscleMassScaled()
+	}
+
+	if ws.BoneMass != math.MaxUint16 {
+		m.BoneMass = ws.BoneMassScaled()
+	}
+
+	if ws.PercentHydration != math.MaxUint16 {
+		m.BodyWater = ws.PercentHydrationScaled()
+	}
+}
+
+// addRestingHeartRate adds the resting heart rate of the day; the 7-day
+// average the device also reports is not a value of the day, and is skipped
+func (days fitMeasurementDays) addRestingHeartRate(hr *mesgdef.MonitoringHrData, date time.Time) {
+	if hr.Timestamp.IsZero() {
+		return
+	}
+
+	rhr := hr.CurrentDayRestingHeartRate
+	if rhr == 0 || rhr == math.MaxUint8 {
+		return
+	}
+
+	days.day(date).RestingHeartRate = float64(rhr)
+}
+
+// addMonitoring keeps the highest accumulated cycles of the day per activity
+// type; the activity type of compact messages is part of their intensity
+func (days fitMeasurementDays) addMonitoring(m *mesgdef.Monitoring, date time.Time) {
+	if m.Cycles == math.MaxUint32 {
+		return
+	}
+
+	activityType := m.ActivityType
+	if activityType == typedef.ActivityTypeInvalid && m.CurrentActivityTypeIntensity != math.MaxUint8 {
+		activityType = typedef.ActivityType(m.CurrentActivityTypeIntensity & 0x1f)
+	}
+
+	if activityType != typedef.ActivityTypeWalking && activityType != typedef.ActivityTypeRunning {
+		return
+	}
+
+	d := days.day(date)
+	d.cycles[activityType] = max(d.cycles[activityType], m.Cycles)
+}
+
+// measurements returns the measurements with any value, ordered by date
+func (days fitMeasurementDays) measurements() []*model.Measurement {
+	dates := make([]time.Time, 0, len(days))
+	for date := range days {
+		dates = append(dates, date)
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+
+	measurements := make([]*model.Measurement, 0, len(dates))
+
+	for _, date := range dates {
+		d := days[date]
+
+		for _, c := range d.cycles {
+			d.Steps += float64(c)
+		}
+
+		if d.Measurement == (model.Measurement{Date: d.Date}) {
+			continue
+		}
+
+		measurements = append(measurements, &d.Measurement)
+	}
+
+	return measurements
+}
+
+// fitLocalDate returns the date of a timestamp in the location, as midnight
+// UTC; the location comes from the monitoring info of the file, or is the
+// server's time zone otherwise
+func fitLocalDate(t time.Time, loc *time.Location) time.Time {
+	t = t.In(loc)
+
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
@@ -0,0 +1,146 @@
+package converters
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/encoder"
+	"github.com/muktihari/fit/kit/datetime"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeTestFIT(t *testing.T, fitData proto.FIT) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, encoder.New(buf).Encode(&fitData))
+
+	return buf.Bytes()
+}
+
+// testWeightFIT builds a weight-scale file with 2 weigh-ins on the same day
+// and one on the next day, while the scale is still calculating
+func testWeightFIT(t *testing.T) []byte {
+	t.Helper()
+
+	morning := time.Date(2024, 4, 1, 7, 0, 0, 0, time.Local)
+
+	f := filedef.NewWeight()
+	f.FileId.
+		SetType(typedef.FileWeight).
+		SetTimeCreated(morning).
+		SetManufacturer(typedef.ManufacturerDevelopment)
+
+	f.WeightScales = append(f.WeightScales,
+		mesgdef.NewWeightScale(nil).
+			SetTimestamp(morning).
+			SetWeightScaled(80.5).
+			SetPercentFatScaled(20.5),
+		mesgdef.NewWeightScale(nil).
+			SetTimestamp(morning.Add(time.Hour)).
+			SetWeightScaled(80.2).
+			SetPercentFatScaled(20.1).
+			SetPercentHydrationScaled(55.5).
+			SetMuscleMassScaled(60.3).
+			SetBoneMassScaled(3.2),
+		mesgdef.NewWeightScale(nil).
+			SetTimestamp(morning.AddDate(0, 0, 1)).
+			SetWeight(typedef.WeightCalculating),
+	)
+
+	return encodeTestFIT(t, f.ToFIT(nil))
+}
+
+// testMonitoringFIT builds a monitoring file of a device at UTC+2, with the
+// accumulated walking and running steps of a day, a compact message with only
+// the lower 16 bits of its timestamp, and the resting heart rate of the day
+// followed by only a 7-day average
+func testMonitoringFIT(t *testing.T) []byte {
+	t.Helper()
+
+	start := time.Date(2024, 4, 1, 22, 30, 0, 0, time.UTC)
+
+	messages := []proto.Message{
+		mesgdef.NewFileId(nil).
+			SetType(typedef.FileMonitoringB).
+			SetTimeCreated(start).
+			SetManufacturer(typedef.ManufacturerDevelopment).
+			ToMesg(nil),
+		mesgdef.NewMonitoringInfo(nil).
+			SetTimestamp(start).
+			SetLocalTimestamp(start.Add(2 * time.Hour)).
+			ToMesg(nil),
+		mesgdef.NewMonitoring(nil).
+			SetTimestamp(start).
+			SetActivityType(typedef.ActivityTypeWalking).
+			SetCycles(100).
+			ToMesg(nil),
+		mesgdef.NewMonitoring(nil).
+			SetTimestamp(start.Add(time.Minute)).
+			SetActivityType(typedef.ActivityTypeRunning).
+			SetCycles(50).
+			ToMesg(nil),
+		mesgdef.NewMonitoring(nil).
+			SetTimestamp16(uint16(datetime.ToUint32(start.Add(2 * time.Minute)))).
+			SetActivityType(typedef.ActivityTypeWalking).
+			SetCycles(250).
+			ToMesg(nil),
+		mesgdef.NewMonitoring(nil).
+			SetTimestamp(start.Add(3 * time.Minute)).
+			SetActivityType(typedef.ActivityTypeCycling).
+			SetCycles(1000).
+			ToMesg(nil),
+		mesgdef.NewMonitoringHrData(nil).
+			SetTimestamp(start).
+			SetRestingHeartRate(52).
+			SetCurrentDayRestingHeartRate(50).
+			ToMesg(nil),
+		mesgdef.NewMonitoringHrData(nil).
+			SetTimestamp(start.Add(2 * time.Minute)).
+			SetRestingHeartRate(60).
+			ToMesg(nil),
+	}
+
+	return encodeTestFIT(t, proto.FIT{Messages: messages})
+}
+
+func TestParseFitMeasurements_Weight(t *testing.T) {
+	ms, err := ParseMeasurements("weight.fit", testWeightFIT(t))
+	require.NoError(t, err)
+	require.Len(t, ms, 1)
+
+	m := ms[0]
+	assert.Equal(t, "2024-04-01", m.DateString())
+	assert.InDelta(t, 80.2, m.Weight, 0.001)
+	assert.InDelta(t, 20.1, m.BodyFat, 0.001)
+	assert.InDelta(t, 55.5, m.BodyWater, 0.001)
+	assert.InDelta(t, 60.3, m.MuscleMass, 0.001)
+	assert.InDelta(t, 3.2, m.BoneMass, 0.001)
+}
+
+func TestParseFitMeasurements_Monitoring(t *testing.T) {
+	ms, err := ParseMeasurements("monitoring.fit", testMonitoringFIT(t))
+	require.NoError(t, err)
+	require.Len(t, ms, 1)
+
+	// 22:30 UTC is the next day at the device
+	m := ms[0]
+	assert.Equal(t, "2024-04-02", m.DateString())
+	assert.InDelta(t, 300, m.Steps, 0.001)
+	assert.InDelta(t, 50, m.RestingHeartRate, 0.001)
+	assert.Zero(t, m.Weight)
+}
+
+func TestParseFitMeasurements_Activity(t *testing.T) {
+	_, err := ParseMeasurements("pool.fit", testPoolSwimFIT(t))
+	require.ErrorIs(t, err, ErrNotMeasurementFile)
+
+	_, err = ParseMeasurements("track.gpx", []byte("<gpx/>"))
+	require.ErrorIs(t, err, ErrNotMeasurementFile)
+}
@@ -0,0 +1,151 @@
+package converters
+
+import (
+	"math"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/profile/untyped/mesgnum"
+)
+
+// isFitMultisport returns whether the activity consists of multiple sports,
+// e.g. a triathlon: a session per sport, optionally with transition sessions
+// in between
+func isFitMultisport(act *filedef.Activity) bool {
+	legs := 0
+
+	for _, s := range act.Sessions {
+		if s.Sport != typedef.SportTransition {
+			legs++
+		}
+	}
+
+	return legs > 1
+}
+
+// parseFitMultisport converts every sport session of a multisport activity
+// to a separate workout, with only the records and laps of that session; the
+// workouts are linked through the multisport activity, which holds the
+// transitions
+func parseFitMultisport(act *filedef.Activity, content []byte, filename string) []*model.Workout {
+	multisport := &model.Multisport{
+		Name: formatFitWorkoutName("multisport", fitActivityStartTime(act)),
+	}
+
+	workouts := make([]*model.Workout, 0, len(act.Sessions))
+
+	for i, session := range act.Sessions {
+		start := session.StartTime
+		end := fitSessionEnd(act, i)
+
+		if session.Sport == typedef.SportTransition {
+			multisport.Transitions = append(multisport.Transitions, model.MultisportTransition{
+				Start:    start.Local(),
+				Duration: end.Sub(start),
+				Distance: fitSessionDistance(session),
+			})
+
+			continue
+		}
+
+		// The last session gets all remaining records
+		if i == len(act.Sessions)-1 {
+			end = time.Time{}
+		}
+
+		leg := fitActivityBetween(act, session, start, end)
+
+		gpxFile := buildGPXFromActivity(leg)
+		data := mapDataFromActivity(leg, gpxFile)
+
+		w := fitSessionWorkout(session, fitActivityStartTime(leg), data, parseLaps(leg), parseWorkoutStats(leg))
+		w.Multisport = multisport
+
+		if multisport.Date.IsZero() {
+			multisport.Date = w.Date
+			multisport.Name = formatFitWorkoutName("multisport", w.Date)
+		}
+
+		setContentAndName(w, filename, "fit", content)
+		w.SetPart(len(workouts))
+		w.UpdateAverages()
+		w.UpdateExtraMetrics()
+
+		workouts = append(workouts, w)
+	}
+
+	return workouts
+}
+
+// fitSessionEnd returns the end of the session: the start of the next
+// session, or the start plus the elapsed time for the last session
+func fitSessionEnd(act *filedef.Activity, i int) time.Time {
+	session := act.Sessions[i]
+
+	if i+1 < len(act.Sessions) && !act.Sessions[i+1].StartTime.IsZero() {
+		return act.Sessions[i+1].StartTime
+	}
+
+	if session.TotalElapsedTime != math.MaxUint32 {
+		return session.StartTime.Add(durationFromSeconds(session.TotalElapsedTimeScaled()))
+	}
+
+	return session.Timestamp
+}
+
+func fitSessionDistance(session *mesgdef.Session) float64 {
+	if session.TotalDistance == math.MaxUint32 {
+		return 0
+	}
+
+	return session.TotalDistanceScaled()
+}
+
+// fitActivityBetween returns the part of the activity recorded by a single
+// session; a zero end includes everything after the start
+func fitActivityBetween(act *filedef.Activity, session *mesgdef.Session, start, end time.Time) *filedef.Activity {
+	leg := *act
+	leg.Activity = nil
+	leg.Sessions = []*mesgdef.Session{session}
+	leg.Laps = nil
+	leg.Records = nil
+	leg.Lengths = nil
+	leg.HRVs = fitHRVBetween(act, start, end)
+
+	within := func(t time.Time) bool {
+		return !t.Before(start) && (end.IsZero() || t.Before(end))
+	}
+
+	for _, l := range act.Laps {
+		if within(l.StartTime) {
+			leg.Laps = append(leg.Laps, l)
+		}
+	}
+
+	for _, l := range act.Lengths {
+		if within(l.StartTime) {
+			leg.Lengths = append(leg.Lengths, l)
+		}
+	}
+
+	for _, r := range act.Records {
+		if within(r.Timestamp) {
+			leg.Records = append(leg.Records, r)
+		}
+	}
+
+	leg.UnrelatedMessages = nil
+
+	for _, m := range act.UnrelatedMessages {
+		if m.Num == mesgnum.Set && !within(mesgdef.NewSet(&m).StartTime) {
+			continue
+		}
+
+		leg.UnrelatedMessages = append(leg.UnrelatedMessages, m)
+	}
+
+	return &leg
+}
@@ -0,0 +1,149 @@
+package converters
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/encoder"
+	"github.com/muktihari/fit/kit/semicircles"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var triathlonStart = time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC)
+
+type testFitLeg struct {
+	sport    typedef.Sport
+	start    time.Duration // Offset from the start of the activity
+	duration time.Duration
+	distance float64
+	position bool
+}
+
+// testTriathlonFIT builds a FIT file with a swim without positions, a bike
+// and a run, separated by transitions
+func testTriathlonFIT(t *testing.T) []byte {
+	t.Helper()
+
+	legs := []testFitLeg{
+		{sport: typedef.SportSwimming, duration: 10 * time.Minute, distance: 500},
+		{sport: typedef.SportTransition, start: 10 * time.Minute, duration: 2 * time.Minute, distance: 100},
+		{sport: typedef.SportCycling, start: 12 * time.Minute, duration: 4 * time.Minute, distance: 2000, position: true},
+		{sport: typedef.SportTransition, start: 16 * time.Minute, duration: time.Minute},
+		{sport: typedef.SportRunning, start: 17 * time.Minute, duration: 4 * time.Minute, distance: 1000, position: true},
+	}
+
+	act := filedef.NewActivity()
+	act.FileId.
+		SetType(typedef.FileActivity).
+		SetTimeCreated(triathlonStart).
+		SetManufacturer(typedef.ManufacturerDevelopment)
+
+	total := 0.0
+	lat := 51.0
+
+	for _, leg := range legs {
+		start := triathlonStart.Add(leg.start)
+		end := start.Add(leg.duration)
+
+		act.Sessions = append(act.Sessions, mesgdef.NewSession(nil).
+			SetTimestamp(end).
+			SetStartTime(start).
+			SetSport(leg.sport).
+			SetTotalDistanceScaled(leg.distance).
+			SetTotalElapsedTimeScaled(leg.duration.Seconds()).
+			SetTotalTimerTimeScaled(leg.duration.Seconds()))
+
+		act.Laps = append(act.Laps, mesgdef.NewLap(nil).
+			SetTimestamp(end).
+			SetStartTime(start).
+			SetTotalElapsedTimeScaled(leg.duration.Seconds()).
+			SetTotalDistanceScaled(leg.distance))
+
+		steps := int(leg.duration / time.Minute)
+		for i := 0; i <= steps; i++ {
+			r := mesgdef.NewRecord(nil).
+				SetTimestamp(start.Add(time.Duration(i) * time.Minute)).
+				SetDistanceScaled(total + leg.distance*float64(i)/float64(steps)).
+				SetHeartRate(uint8(120 + i))
+
+			if leg.position {
+				r.SetPositionLat(semicircles.ToSemicircles(lat)).SetPositionLong(semicircles.ToSemicircles(4.0))
+				lat += 0.001
+			}
+
+			act.Records = append(act.Records, r)
+		}
+
+		total += leg.distance
+	}
+
+	end := triathlonStart.Add(21 * time.Minute)
+	act.Activity = mesgdef.NewActivity(nil).
+		SetType(typedef.ActivityAutoMultiSport).
+		SetTimestamp(end).
+		SetNumSessions(uint16(len(legs)))
+
+	fitData := act.ToFIT(nil)
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, encoder.New(buf).Encode(&fitData))
+
+	return buf.Bytes()
+}
+
+func TestParseFit_Multisport(t *testing.T) {
+	workouts, err := ParseCollection("triathlon.fit", testTriathlonFIT(t))
+	require.NoError(t, err)
+	require.Len(t, workouts, 3)
+
+	for i, sport := range []string{"swimming", "cycling", "running"} {
+		w := workouts[i]
+
+		assert.Equal(t, sport, w.Data.Type)
+		require.NotNil(t, w.Multisport)
+		assert.Same(t, workouts[0].Multisport, w.Multisport)
+
+		// Every leg is stored with the file, as a separate part
+		require.NotNil(t, w.GPX)
+		assert.Equal(t, i, w.GPX.Part)
+		assert.Equal(t, "triathlon.fit", w.GPX.Filename)
+	}
+
+	assert.NotEqual(t, workouts[0].GPX.Checksum, workouts[1].GPX.Checksum)
+
+	// The record at the end of a session belongs to the next one
+	swim := workouts[0]
+	assert.Equal(t, triathlonStart, swim.Date.UTC())
+	assert.Equal(t, 10*time.Minute, swim.Data.TotalDuration)
+	assert.InDelta(t, 500, swim.Data.TotalDistance, 0.01)
+	assert.Len(t, swim.Data.Details.Points, 10)
+	assert.Len(t, swim.Data.Laps, 1)
+
+	bike := workouts[1]
+	assert.Equal(t, triathlonStart.Add(12*time.Minute), bike.Date.UTC())
+	assert.InDelta(t, 2000, bike.Data.TotalDistance, 0.01)
+	require.Len(t, bike.Data.Details.Points, 4)
+	assert.InDelta(t, 51.0, bike.Data.Details.Points[0].Lat, 1e-6)
+	assert.Len(t, bike.Data.Laps, 1)
+
+	run := workouts[2]
+	require.Len(t, run.Data.Details.Points, 5)
+	assert.InDelta(t, 51.005, run.Data.Details.Points[0].Lat, 1e-6)
+
+	// Reparsing the stored file returns the same leg
+	reparsed, err := bike.ReparseFile()
+	require.NoError(t, err)
+	assert.Equal(t, "cycling", reparsed.Data.Type)
+	assert.Equal(t, bike.Date, reparsed.Date)
+
+	multisport := swim.Multisport
+	assert.Equal(t, triathlonStart, multisport.Date.UTC())
+	require.Len(t, multisport.Transitions, 2)
+	assert.Equal(t, 2*time.Minute, multisport.Transitions[0].Duration)
+	assert.InDelta(t, 100, multisport.Transitions[0].Distance, 0.01)
+	assert.Equal(t, time.Minute, multisport.Transitions[1].Duration)
+}
@@ -0,0 +1,183 @@
+package converters
+
+import (
+	"math"
+	"strings"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/muktihari/fit/profile/untyped/mesgnum"
+)
+
+// fitSets converts the set messages of a strength activity; the duration of
+// a rest set is added to the rest of the active set before it
+func fitSets(act *filedef.Activity) []model.WorkoutSet {
+	messages := fitSetMessages(act)
+	if len(messages) == 0 {
+		return nil
+	}
+
+	sets := make([]model.WorkoutSet, 0, len(messages))
+
+	for _, s := range messages {
+		duration := 0.0
+		if s.Duration != math.MaxUint32 {
+			duration = s.DurationScaled()
+		}
+
+		if s.SetType == typedef.SetTypeRest {
+			if len(sets) > 0 {
+				sets[len(sets)-1].Rest += durationFromSeconds(duration)
+			}
+
+			continue
+		}
+
+		set := model.WorkoutSet{
+			Start:    s.StartTime.Local(),
+			Duration: durationFromSeconds(duration),
+			Exercise: fitExercise(s),
+		}
+
+		if s.Repetitions != math.MaxUint16 {
+			set.Repetitions = int(s.Repetitions)
+		}
+
+		if s.Weight != math.MaxUint16 {
+			set.Weight = s.WeightScaled()
+		}
+
+		sets = append(sets, set)
+	}
+
+	return sets
+}
+
+// fitSetMessages returns the set messages of the activity; the activity
+// file definition does not group them, so they are part of the unrelated
+// messages
+func fitSetMessages(act *filedef.Activity) []*mesgdef.Set {
+	var sets []*mesgdef.Set
+
+	for i := range act.UnrelatedMessages {
+		if act.UnrelatedMessages[i].Num == mesgnum.Set {
+			sets = append(sets, mesgdef.NewSet(&act.UnrelatedMessages[i]))
+		}
+	}
+
+	return sets
+}
+
+// fitExercise returns the exercise of a set, by the name of its category
+// subtype, or the category if the subtype is unknown
+func fitExercise(s *mesgdef.Set) *model.Exercise {
+	if len(s.Category) == 0 || s.Category[0] == typedef.ExerciseCategoryInvalid ||
+		s.Category[0] == typedef.ExerciseCategoryUnknown {
+		return &model.Exercise{Name: model.ExerciseUnknown}
+	}
+
+	category := s.Category[0].String()
+	e := &model.Exercise{Name: category, Category: category}
+
+	if len(s.CategorySubtype) == 0 || s.CategorySubtype[0] == math.MaxUint16 {
+		return e
+	}
+
+	if name := fitExerciseName(s.Category[0], s.CategorySubtype[0]); name != "" {
+		e.Name = name
+	}
+
+	return e
+}
+
+// fitExerciseName returns the name of the exercise of a category subtype;
+// it returns an empty string if the subtype is not known
+//
+//nolint:gocyclo // one case per exercise category
+func fitExerciseName(category typedef.ExerciseCategory, subtype uint16) string {
+	var name string
+
+	switch category {
+	case typedef.ExerciseCategoryBenchPress:
+		name = typedef.BenchPressExerciseName(subtype).String()
+	case typedef.ExerciseCategoryCalfRaise:
+		name = typedef.CalfRaiseExerciseName(subtype).String()
+	case typedef.ExerciseCategoryCardio:
+		name = typedef.CardioExerciseName(subtype).String()
+	case typedef.ExerciseCategoryCarry:
+		name = typedef.CarryExerciseName(subtype).String()
+	case typedef.ExerciseCategoryChop:
+		name = typedef.ChopExerciseName(subtype).String()
+	case typedef.ExerciseCategoryCore:
+		name = typedef.CoreExerciseName(subtype).String()
+	case typedef.ExerciseCategoryCrunch:
+		name = typedef.CrunchExerciseName(subtype).String()
+	case typedef.ExerciseCategoryCurl:
+		name = typedef.CurlExerciseName(subtype).String()
+	case typedef.ExerciseCategoryDeadlift:
+		name = typedef.DeadliftExerciseName(subtype).String()
+	case typedef.ExerciseCategoryFlye:
+		name = typedef.FlyeExerciseName(subtype).String()
+	case typedef.ExerciseCategoryHipRaise:
+		name = typedef.HipRaiseExerciseName(subtype).String()
+	case typedef.ExerciseCategoryHipStability:
+		name = typedef.HipStabilityExerciseName(subtype).String()
+	case typedef.ExerciseCategoryHipSwing:
+		name = typedef.HipSwingExerciseName(subtype).String()
+	case typedef.ExerciseCategoryHyperextension:
+		name = typedef.HyperextensionExerciseName(subtype).String()
+	case typedef.ExerciseCategoryLateralRaise:
+		name = typedef.LateralRaiseExerciseName(subtype).String()
+	case typedef.ExerciseCategoryLegCurl:
+		name = typedef.LegCurlExerciseName(subtype).String()
+	case typedef.ExerciseCategoryLegRaise:
+		name = typedef.LegRaiseExerciseName(subtype).String()
+	case typedef.ExerciseCategoryLunge:
+		name = typedef.LungeExerciseName(subtype).String()
+	case typedef.ExerciseCategoryOlympicLift:
+		name = typedef.OlympicLiftExerciseName(subtype).String()
+	case typedef.ExerciseCategoryPlank:
+		name = typedef.PlankExerciseName(subtype).String()
+	case typedef.ExerciseCategoryPlyo:
+		name = typedef.PlyoExerciseName(subtype).String()
+	case typedef.ExerciseCategoryPullUp:
+		name = typedef.PullUpExerciseName(subtype).String()
+	case typedef.ExerciseCategoryPushUp:
+		name = typedef.PushUpExerciseName(subtype).String()
+	case typedef.ExerciseCategoryRow:
+		name = typedef.RowExerciseName(subtype).String()
+	case typedef.ExerciseCategoryShoulderPress:
+		name = typedef.ShoulderPressExerciseName(subtype).String()
+	case typedef.ExerciseCategoryShoulderStability:
+		name = typedef.ShoulderStabilityExerciseName(subtype).String()
+	case typedef.ExerciseCategoryShrug:
+		name = typedef.ShrugExerciseName(subtype).String()
+	case typedef.ExerciseCategorySitUp:
+		name = typedef.SitUpExerciseName(subtype).String()
+	case typedef.ExerciseCategorySquat:
+		name = typedef.SquatExerciseName(subtype).String()
+	case typedef.ExerciseCategoryTotalBody:
+		name = typedef.TotalBodyExerciseName(subtype).String()
+	case typedef.ExerciseCategoryTricepsExtension:
+		name = typedef.TricepsExtensionExerciseName(subtype).String()
+	case typedef.ExerciseCategoryWarmUp:
+		name = typedef.WarmUpExerciseName(subtype).String()
+	case typedef.ExerciseCategorySandbag:
+		name = typedef.SandbagExerciseName(subtype).String()
+	case typedef.ExerciseCategorySled:
+		name = typedef.SledExerciseName(subtype).String()
+	case typedef.ExerciseCategorySuspension:
+		name = typedef.SuspensionExerciseName(subtype).String()
+	case typedef.ExerciseCategoryTire:
+		name = typedef.TireExerciseName(subtype).String()
+	}
+
+	// Unknown values are formatted as e.g. SquatExerciseNameInvalid(123)
+	if strings.Contains(name, "(") {
+		return ""
+	}
+
+	return name
+}
@@ -0,0 +1,106 @@
+package converters
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/encoder"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testStrengthFIT builds a strength activity: 2 sets of barbell bench press
+// with a rest in between, and a set of an unknown exercise
+func testStrengthFIT(t *testing.T) []byte {
+	t.Helper()
+
+	start := time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC)
+
+	act := filedef.NewActivity()
+	act.FileId.
+		SetType(typedef.FileActivity).
+		SetTimeCreated(start).
+		SetManufacturer(typedef.ManufacturerDevelopment)
+
+	sets := []*mesgdef.Set{
+		mesgdef.NewSet(nil).
+			SetStartTime(start).
+			SetDurationScaled(40).
+			SetSetType(typedef.SetTypeActive).
+			SetRepetitions(8).
+			SetWeightScaled(60).
+			SetCategory([]typedef.ExerciseCategory{typedef.ExerciseCategoryBenchPress}).
+			SetCategorySubtype([]uint16{uint16(typedef.BenchPressExerciseNameBarbellBenchPress)}),
+		mesgdef.NewSet(nil).
+			SetStartTime(start.Add(40 * time.Second)).
+			SetDurationScaled(90).
+			SetSetType(typedef.SetTypeRest),
+		mesgdef.NewSet(nil).
+			SetStartTime(start.Add(130 * time.Second)).
+			SetDurationScaled(35).
+			SetSetType(typedef.SetTypeActive).
+			SetRepetitions(6).
+			SetWeightScaled(65).
+			SetCategory([]typedef.ExerciseCategory{typedef.ExerciseCategoryBenchPress}).
+			SetCategorySubtype([]uint16{uint16(typedef.BenchPressExerciseNameBarbellBenchPress)}),
+		mesgdef.NewSet(nil).
+			SetStartTime(start.Add(165 * time.Second)).
+			SetDurationScaled(30).
+			SetSetType(typedef.SetTypeActive).
+			SetRepetitions(12),
+	}
+
+	for _, s := range sets {
+		act.UnrelatedMessages = append(act.UnrelatedMessages, s.ToMesg(nil))
+	}
+
+	end := start.Add(195 * time.Second)
+
+	for at := start; !at.After(end); at = at.Add(15 * time.Second) {
+		act.Records = append(act.Records, mesgdef.NewRecord(nil).
+			SetTimestamp(at).
+			SetHeartRate(110))
+	}
+
+	act.Sessions = append(act.Sessions, mesgdef.NewSession(nil).
+		SetTimestamp(end).
+		SetStartTime(start).
+		SetSport(typedef.SportTraining).
+		SetSubSport(typedef.SubSportStrengthTraining).
+		SetTotalElapsedTimeScaled(end.Sub(start).Seconds()).
+		SetTotalTimerTimeScaled(end.Sub(start).Seconds()))
+
+	fitData := act.ToFIT(nil)
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, encoder.New(buf).Encode(&fitData))
+
+	return buf.Bytes()
+}
+
+func TestParseFit_StrengthSets(t *testing.T) {
+	workouts, err := ParseCollection("strength.fit", testStrengthFIT(t))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Equal(t, "strength_training", w.Data.Type)
+	require.Len(t, w.Data.Sets, 3)
+
+	s := w.Data.Sets[0]
+	assert.Equal(t, "barbell_bench_press", s.ExerciseName())
+	assert.Equal(t, "bench_press", s.Exercise.Category)
+	assert.Equal(t, 8, s.Repetitions)
+	assert.InDelta(t, 60, s.Weight, 0.001)
+	assert.Equal(t, 40*time.Second, s.Duration)
+	assert.Equal(t, 90*time.Second, s.Rest)
+
+	assert.Equal(t, "unknown", w.Data.Sets[2].ExerciseName())
+	assert.Zero(t, w.Data.Sets[2].Weight)
+
+	assert.Equal(t, 26, w.Data.TotalRepetitions)
+	assert.InDelta(t, 8*60+6*65, w.Data.TotalWeight, 0.001)
+}
@@ -0,0 +1,66 @@
+package converters
+
+import (
+	"math"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/typedef"
+)
+
+// fitPoolLength returns the length of the pool of a pool swim, in meters
+func fitPoolLength(act *filedef.Activity) float64 {
+	for _, s := range act.Sessions {
+		if s.PoolLength != math.MaxUint16 && s.PoolLength != 0 {
+			return s.PoolLengthScaled()
+		}
+	}
+
+	return 0
+}
+
+// fitLengths converts the length messages of a pool swim; idle lengths are
+// the rests between intervals
+func fitLengths(act *filedef.Activity) []model.WorkoutLength {
+	if len(act.Lengths) == 0 {
+		return nil
+	}
+
+	poolLength := fitPoolLength(act)
+	lengths := make([]model.WorkoutLength, 0, len(act.Lengths))
+
+	for _, l := range act.Lengths {
+		elapsed := l.TotalElapsedTime
+		if elapsed == math.MaxUint32 {
+			elapsed = l.TotalTimerTime
+		}
+
+		if elapsed == math.MaxUint32 {
+			continue
+		}
+
+		length := model.WorkoutLength{
+			Start:    l.StartTime.Local(),
+			Duration: durationFromSeconds(float64(elapsed) / 1000),
+		}
+
+		if l.LengthType != typedef.LengthTypeIdle {
+			length.Distance = poolLength
+			if length.Distance == 0 && l.AvgSpeed != math.MaxUint16 {
+				length.Distance = l.AvgSpeedScaled() * length.Duration.Seconds()
+			}
+
+			if l.TotalStrokes != math.MaxUint16 {
+				length.Strokes = int(l.TotalStrokes)
+			}
+
+			if l.SwimStroke != typedef.SwimStrokeInvalid {
+				length.Stroke = l.SwimStroke.String()
+			}
+		}
+
+		lengths = append(lengths, length)
+	}
+
+	return lengths
+}
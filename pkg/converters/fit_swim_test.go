@@ -0,0 +1,111 @@
+package converters
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/muktihari/fit/encoder"
+	"github.com/muktihari/fit/profile/filedef"
+	"github.com/muktihari/fit/profile/mesgdef"
+	"github.com/muktihari/fit/profile/typedef"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testPoolSwimFIT builds a pool swim in a 25m pool: 2 lengths of freestyle,
+// a rest and a length of breaststroke
+func testPoolSwimFIT(t *testing.T) []byte {
+	t.Helper()
+
+	start := time.Date(2024, 2, 1, 7, 0, 0, 0, time.UTC)
+
+	act := filedef.NewActivity()
+	act.FileId.
+		SetType(typedef.FileActivity).
+		SetTimeCreated(start).
+		SetManufacturer(typedef.ManufacturerDevelopment)
+
+	lengths := []struct {
+		duration time.Duration
+		stroke   typedef.SwimStroke
+		strokes  uint16
+		idle     bool
+	}{
+		{duration: 30 * time.Second, stroke: typedef.SwimStrokeFreestyle, strokes: 15},
+		{duration: 32 * time.Second, stroke: typedef.SwimStrokeFreestyle, strokes: 17},
+		{duration: 20 * time.Second, idle: true},
+		{duration: 40 * time.Second, stroke: typedef.SwimStrokeBreaststroke, strokes: 10},
+	}
+
+	at := start
+	distance := 0.0
+
+	for _, l := range lengths {
+		length := mesgdef.NewLength(nil).
+			SetStartTime(at).
+			SetTimestamp(at.Add(l.duration)).
+			SetTotalElapsedTimeScaled(l.duration.Seconds()).
+			SetTotalTimerTimeScaled(l.duration.Seconds()).
+			SetLengthType(typedef.LengthTypeActive).
+			SetSwimStroke(l.stroke).
+			SetTotalStrokes(l.strokes)
+
+		if l.idle {
+			length.SetLengthType(typedef.LengthTypeIdle)
+		} else {
+			distance += 25
+		}
+
+		act.Lengths = append(act.Lengths, length)
+
+		at = at.Add(l.duration)
+
+		act.Records = append(act.Records, mesgdef.NewRecord(nil).
+			SetTimestamp(at).
+			SetDistanceScaled(distance).
+			SetHeartRate(130))
+	}
+
+	act.Sessions = append(act.Sessions, mesgdef.NewSession(nil).
+		SetTimestamp(at).
+		SetStartTime(start).
+		SetSport(typedef.SportSwimming).
+		SetSubSport(typedef.SubSportLapSwimming).
+		SetPoolLengthScaled(25).
+		SetPoolLengthUnit(typedef.DisplayMeasureMetric).
+		SetTotalDistanceScaled(distance).
+		SetTotalElapsedTimeScaled(at.Sub(start).Seconds()).
+		SetTotalTimerTimeScaled(at.Sub(start).Seconds()))
+
+	fitData := act.ToFIT(nil)
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, encoder.New(buf).Encode(&fitData))
+
+	return buf.Bytes()
+}
+
+func TestParseFit_PoolSwim(t *testing.T) {
+	workouts, err := ParseCollection("pool.fit", testPoolSwimFIT(t))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Equal(t, "swimming", w.Data.Type)
+	assert.InDelta(t, 25, w.Data.PoolLength, 0.001)
+	require.Len(t, w.Data.Lengths, 4)
+
+	l := w.Data.Lengths[0]
+	assert.InDelta(t, 25, l.Distance, 0.001)
+	assert.Equal(t, 30*time.Second, l.Duration)
+	assert.Equal(t, "freestyle", l.Stroke)
+	assert.Equal(t, 15, l.Strokes)
+	assert.InDelta(t, 45, l.SWOLF(), 0.001)
+
+	assert.True(t, w.Data.Lengths[2].IsRest())
+	assert.Equal(t, "breaststroke", w.Data.Lengths[3].Stroke)
+
+	intervals := w.SwimIntervals()
+	require.Len(t, intervals, 2)
+	assert.Equal(t, 20*time.Second, intervals[0].Rest)
+}
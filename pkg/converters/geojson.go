@@ -0,0 +1,215 @@
+package converters
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+type (
+	geoJSONFeatureCollection struct {
+		Type     string           `json:"type"`
+		Features []geoJSONFeature `json:"features"`
+	}
+
+	geoJSONFeature struct {
+		Type       string          `json:"type"`
+		Geometry   geoJSONGeometry `json:"geometry"`
+		Properties map[string]any  `json:"properties"`
+	}
+
+	geoJSONGeometry struct {
+		Type        string          `json:"type"`
+		Coordinates json.RawMessage `json:"coordinates"`
+	}
+
+	// geoJSONObject is any GeoJSON object: a FeatureCollection, a Feature
+	// or a bare geometry
+	geoJSONObject struct {
+		Type        string           `json:"type"`
+		Features    []geoJSONFeature `json:"features"`
+		Geometry    geoJSONGeometry  `json:"geometry"`
+		Properties  map[string]any   `json:"properties"`
+		Coordinates json.RawMessage  `json:"coordinates"`
+	}
+)
+
+// ParseGeoJSON reads every LineString and MultiLineString in the document;
+// every Feature becomes a separate GPX track. Timestamps and metrics are
+// read from the "coordinateProperties" property when present.
+func ParseGeoJSON(content []byte) (*gpx.GPX, error) {
+	var obj geoJSONObject
+	if err := json.Unmarshal(content, &obj); err != nil {
+		return nil, err
+	}
+
+	var features []geoJSONFeature
+
+	switch obj.Type {
+	case "FeatureCollection":
+		features = obj.Features
+	case "Feature":
+		features = []geoJSONFeature{{Type: obj.Type, Geometry: obj.Geometry, Properties: obj.Properties}}
+	default:
+		features = []geoJSONFeature{{
+			Type:     "Feature",
+			Geometry: geoJSONGeometry{Type: obj.Type, Coordinates: obj.Coordinates},
+		}}
+	}
+
+	g := &gpx.GPX{Creator: "GeoJSON importer"}
+
+	for _, f := range features {
+		track, err := f.gpxTrack()
+		if err != nil {
+			return nil, err
+		}
+
+		if len(track.Segments) == 0 {
+			continue
+		}
+
+		if g.Name == "" {
+			g.Name = track.Name
+			g.Description = track.Description
+		}
+
+		if g.Time == nil {
+			if t, ok := parseKMLTime(cast.ToString(f.Properties["time"])); ok {
+				g.Time = &t
+			}
+		}
+
+		if c := cast.ToString(f.Properties["creator"]); c != "" {
+			g.Creator = c
+		}
+
+		g.Tracks = append(g.Tracks, track)
+	}
+
+	if len(g.Tracks) == 0 {
+		return nil, ErrNoTracksFound
+	}
+
+	return g, nil
+}
+
+func (f *geoJSONFeature) gpxTrack() (gpx.GPXTrack, error) {
+	track := gpx.GPXTrack{
+		Name:        cast.ToString(f.Properties["name"]),
+		Description: cast.ToString(f.Properties["notes"]),
+		Type:        cast.ToString(f.Properties["type"]),
+	}
+
+	var lines [][][]float64
+
+	switch f.Geometry.Type {
+	case "LineString":
+		var line [][]float64
+		if err := json.Unmarshal(f.Geometry.Coordinates, &line); err != nil {
+			return track, err
+		}
+
+		lines = append(lines, line)
+	case "MultiLineString":
+		if err := json.Unmarshal(f.Geometry.Coordinates, &lines); err != nil {
+			return track, err
+		}
+	default:
+		return track, nil
+	}
+
+	coordinateProperties := cast.ToStringMap(f.Properties["coordinateProperties"])
+	offset := 0
+
+	for _, line := range lines {
+		segment := gpx.GPXTrackSegment{}
+
+		for i, c := range line {
+			if len(c) < 2 {
+				continue
+			}
+
+			p := gpx.GPXPoint{
+				Point: gpx.Point{
+					Latitude:  c[1],
+					Longitude: c[0],
+				},
+			}
+
+			if len(c) > 2 {
+				p.Elevation = *gpx.NewNullableFloat64(c[2])
+			}
+
+			geoJSONPointProperties(&p, coordinateProperties, offset+i)
+
+			segment.Points = append(segment.Points, p)
+		}
+
+		offset += len(line)
+
+		if len(segment.Points) > 0 {
+			track.Segments = append(track.Segments, segment)
+		}
+	}
+
+	return track, nil
+}
+
+// geoJSONPointProperties copies the timestamp and metrics for the point at
+// the given index from the coordinateProperties arrays; for MultiLineStrings
+// the arrays are expected to span all lines
+func geoJSONPointProperties(p *gpx.GPXPoint, props map[string]any, idx int) {
+	for name, values := range props {
+		list, ok := values.([]any)
+		if !ok || idx >= len(list) || list[idx] == nil {
+			continue
+		}
+
+		if name == "times" {
+			p.Timestamp = geoJSONTime(list[idx])
+			continue
+		}
+
+		v, err := cast.ToFloat64E(list[idx])
+		if err != nil {
+			continue
+		}
+
+		p.Extensions.Nodes = append(p.Extensions.Nodes, gpx.ExtensionNode{
+			XMLName: xml.Name{Local: geoJSONMetricName(name)}, Data: cast.ToString(v),
+		})
+	}
+}
+
+// geoJSONTime parses a timestamp as either a string or milliseconds since
+// the epoch
+func geoJSONTime(v any) time.Time {
+	if s, ok := v.(string); ok {
+		t, _ := parseKMLTime(s)
+		return t
+	}
+
+	if ms, err := cast.ToInt64E(v); err == nil {
+		return time.UnixMilli(ms)
+	}
+
+	return time.Time{}
+}
+
+// geoJSONMetricName maps the names used by togeojson to our metric names
+func geoJSONMetricName(name string) string {
+	switch name {
+	case "heart", "heartRates":
+		return "heart-rate"
+	case "cadences":
+		return "cadence"
+	case "powers":
+		return "power"
+	default:
+		return name
+	}
+}
@@ -0,0 +1,276 @@
+package converters
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"io"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/spf13/cast"
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+var ErrNoTracksFound = errors.New("no tracks found")
+
+type (
+	kmlPlacemark struct {
+		Name          string          `xml:"name"`
+		Description   string          `xml:"description"`
+		TimeStamp     string          `xml:"TimeStamp>when"`
+		TimeSpanBegin string          `xml:"TimeSpan>begin"`
+		LineStrings   []kmlLineString `xml:"LineString"`
+		Tracks        []kmlTrack      `xml:"Track"`
+		MultiTracks   []kmlMultiTrack `xml:"MultiTrack"`
+		MultiGeometry []kmlMultiTrack `xml:"MultiGeometry"`
+	}
+
+	// kmlMultiTrack is used for both gx:MultiTrack and MultiGeometry
+	kmlMultiTrack struct {
+		LineStrings []kmlLineString `xml:"LineString"`
+		Tracks      []kmlTrack      `xml:"Track"`
+	}
+
+	kmlLineString struct {
+		Coordinates string `xml:"coordinates"`
+	}
+
+	// kmlTrack is a gx:Track, which holds timestamped coordinates and
+	// optional per-point sensor data
+	kmlTrack struct {
+		When         []string         `xml:"when"`
+		Coords       []string         `xml:"coord"`
+		ExtendedData *kmlExtendedData `xml:"ExtendedData"`
+	}
+
+	kmlExtendedData struct {
+		Arrays []kmlSimpleArrayData `xml:"SchemaData>SimpleArrayData"`
+	}
+
+	kmlSimpleArrayData struct {
+		Name   string   `xml:"name,attr"`
+		Values []string `xml:"value"`
+	}
+)
+
+// ParseKML reads all Placemarks with a LineString or gx:Track geometry,
+// wherever they are nested in the document; every Placemark becomes a
+// separate GPX track.
+func ParseKML(content []byte) (*gpx.GPX, error) {
+	placemarks, err := kmlPlacemarks(content)
+	if err != nil {
+		return nil, err
+	}
+
+	g := &gpx.GPX{Creator: "KML importer"}
+
+	for _, pm := range placemarks {
+		track := pm.gpxTrack()
+		if len(track.Segments) == 0 {
+			continue
+		}
+
+		if g.Name == "" {
+			g.Name = pm.Name
+			g.Description = pm.Description
+		}
+
+		if g.Time == nil {
+			if t, ok := parseKMLTime(pm.TimeStamp, pm.TimeSpanBegin); ok {
+				g.Time = &t
+			}
+		}
+
+		g.Tracks = append(g.Tracks, track)
+	}
+
+	if len(g.Tracks) == 0 {
+		return nil, ErrNoTracksFound
+	}
+
+	return g, nil
+}
+
+// ParseKMZ reads the first KML document from a KMZ archive
+func ParseKMZ(content []byte) (*gpx.GPX, error) {
+	zipReader, err := zip.NewReader(bytes.NewReader(content), int64(len(content)))
+	if err != nil {
+		return nil, err
+	}
+
+	for _, zipFile := range zipReader.File {
+		if !strings.EqualFold(path.Ext(zipFile.Name), ".kml") {
+			continue
+		}
+
+		c, err := readFileFromZip(zipFile)
+		if err != nil {
+			return nil, err
+		}
+
+		return ParseKML(c)
+	}
+
+	return nil, ErrNoTracksFound
+}
+
+func kmlPlacemarks(content []byte) ([]kmlPlacemark, error) {
+	var placemarks []kmlPlacemark
+
+	dec := xml.NewDecoder(bytes.NewReader(content))
+
+	for {
+		tok, err := dec.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			return nil, err
+		}
+
+		se, ok := tok.(xml.StartElement)
+		if !ok || se.Name.Local != "Placemark" {
+			continue
+		}
+
+		var pm kmlPlacemark
+		if err := dec.DecodeElement(&pm, &se); err != nil {
+			return nil, err
+		}
+
+		placemarks = append(placemarks, pm)
+	}
+
+	return placemarks, nil
+}
+
+func (pm *kmlPlacemark) gpxTrack() gpx.GPXTrack {
+	track := gpx.GPXTrack{
+		Name:        pm.Name,
+		Description: pm.Description,
+	}
+
+	lineStrings := pm.LineStrings
+	tracks := pm.Tracks
+
+	for _, mt := range slices.Concat(pm.MultiTracks, pm.MultiGeometry) {
+		lineStrings = append(lineStrings, mt.LineStrings...)
+		tracks = append(tracks, mt.Tracks...)
+	}
+
+	for _, ls := range lineStrings {
+		if s := ls.gpxSegment(); len(s.Points) > 0 {
+			track.Segments = append(track.Segments, s)
+		}
+	}
+
+	for _, t := range tracks {
+		if s := t.gpxSegment(); len(s.Points) > 0 {
+			track.Segments = append(track.Segments, s)
+		}
+	}
+
+	return track
+}
+
+func (ls *kmlLineString) gpxSegment() gpx.GPXTrackSegment {
+	segment := gpx.GPXTrackSegment{}
+
+	for _, c := range strings.Fields(ls.Coordinates) {
+		if p, ok := kmlPoint(strings.Split(c, ",")); ok {
+			segment.Points = append(segment.Points, p)
+		}
+	}
+
+	return segment
+}
+
+func (t *kmlTrack) gpxSegment() gpx.GPXTrackSegment {
+	segment := gpx.GPXTrackSegment{}
+
+	for i, c := range t.Coords {
+		p, ok := kmlPoint(strings.Fields(c))
+		if !ok {
+			continue
+		}
+
+		if i < len(t.When) {
+			if ts, ok := parseKMLTime(t.When[i]); ok {
+				p.Timestamp = ts
+			}
+		}
+
+		if t.ExtendedData != nil {
+			for _, a := range t.ExtendedData.Arrays {
+				if i >= len(a.Values) || a.Name == "" {
+					continue
+				}
+
+				p.Extensions.Nodes = append(p.Extensions.Nodes, gpx.ExtensionNode{
+					XMLName: xml.Name{Local: a.Name}, Data: strings.TrimSpace(a.Values[i]),
+				})
+			}
+		}
+
+		segment.Points = append(segment.Points, p)
+	}
+
+	return segment
+}
+
+// kmlPoint converts "longitude, latitude[, altitude]" fields to a point
+func kmlPoint(fields []string) (gpx.GPXPoint, bool) {
+	if len(fields) < 2 {
+		return gpx.GPXPoint{}, false
+	}
+
+	lng, err := cast.ToFloat64E(strings.TrimSpace(fields[0]))
+	if err != nil {
+		return gpx.GPXPoint{}, false
+	}
+
+	lat, err := cast.ToFloat64E(strings.TrimSpace(fields[1]))
+	if err != nil {
+		return gpx.GPXPoint{}, false
+	}
+
+	p := gpx.GPXPoint{
+		Point: gpx.Point{
+			Latitude:  lat,
+			Longitude: lng,
+		},
+	}
+
+	if len(fields) > 2 {
+		if ele, err := cast.ToFloat64E(strings.TrimSpace(fields[2])); err == nil {
+			p.Elevation = *gpx.NewNullableFloat64(ele)
+		}
+	}
+
+	return p, true
+}
+
+// parseKMLTime returns the first candidate that is a valid KML dateTime
+func parseKMLTime(candidates ...string) (time.Time, bool) {
+	layouts := []string{time.RFC3339Nano, "2006-01-02T15:04:05", time.DateOnly}
+
+	for _, c := range candidates {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+
+		for _, l := range layouts {
+			if t, err := time.Parse(l, c); err == nil {
+				return t, true
+			}
+		}
+	}
+
+	return time.Time{}, false
+}
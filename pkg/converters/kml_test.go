@@ -0,0 +1,117 @@
+package converters
+
+import (
+	"archive/zip"
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const kmlGxTrackSample = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2" xmlns:gx="http://www.google.com/kml/ext/2.2">
+  <Document>
+    <name>Tracks</name>
+    <Folder>
+      <Placemark>
+        <name>Evening ride</name>
+        <gx:Track>
+          <when>2023-09-10T18:00:00Z</when>
+          <when>2023-09-10T18:00:10Z</when>
+          <when>2023-09-10T18:00:20Z</when>
+          <gx:coord>4.40000 51.20000 12</gx:coord>
+          <gx:coord>4.40100 51.20000 13</gx:coord>
+          <gx:coord>4.40200 51.20000 14</gx:coord>
+          <ExtendedData>
+            <SchemaData schemaUrl="#schema">
+              <gx:SimpleArrayData name="heartrate">
+                <gx:value>110</gx:value>
+                <gx:value>120</gx:value>
+                <gx:value>130</gx:value>
+              </gx:SimpleArrayData>
+            </SchemaData>
+          </ExtendedData>
+        </gx:Track>
+      </Placemark>
+    </Folder>
+  </Document>
+</kml>`
+
+const kmlLineStringSample = `<?xml version="1.0" encoding="UTF-8"?>
+<kml xmlns="http://www.opengis.net/kml/2.2">
+  <Placemark>
+    <name>Planned route</name>
+    <TimeStamp><when>2023-09-11</when></TimeStamp>
+    <LineString>
+      <coordinates>
+        4.4,51.2,10 4.401,51.2,10
+        4.402,51.2,10
+      </coordinates>
+    </LineString>
+  </Placemark>
+</kml>`
+
+const geoJSONLineStringSample = `{
+  "type": "Feature",
+  "properties": {"name": "Lunch walk"},
+  "geometry": {"type": "LineString", "coordinates": [[4.4, 51.2], [4.401, 51.2], [4.402, 51.2]]}
+}`
+
+func TestParseKML_GxTrack(t *testing.T) {
+	workouts, err := ParseCollection("tracks.kml", []byte(kmlGxTrackSample))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Equal(t, "Evening ride", w.Name)
+	assert.Equal(t, time.Date(2023, 9, 10, 18, 0, 0, 0, time.UTC), w.Date.UTC())
+	require.Len(t, w.Data.Details.Points, 3)
+	assert.InDelta(t, 120, w.Data.Details.Points[1].ExtraMetrics["heart-rate"], 0.01)
+	assert.InDelta(t, 139, w.Data.TotalDistance, 2)
+	assert.Equal(t, 20*time.Second, w.Data.TotalDuration)
+}
+
+func TestParseKML_LineString(t *testing.T) {
+	workouts, err := ParseCollection("route.kml", []byte(kmlLineStringSample))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Equal(t, "Planned route", w.Name)
+	assert.Equal(t, time.Date(2023, 9, 11, 0, 0, 0, 0, time.UTC), w.Date.UTC())
+	assert.Len(t, w.Data.Details.Points, 3)
+}
+
+func TestParseKMZ(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+
+	f, err := zw.Create("doc.kml")
+	require.NoError(t, err)
+
+	_, err = f.Write([]byte(kmlGxTrackSample))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+
+	workouts, err := ParseCollection("tracks.kmz", buf.Bytes())
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+	assert.Len(t, workouts[0].Data.Details.Points, 3)
+	assert.Equal(t, "tracks.kmz", workouts[0].GPX.Filename)
+}
+
+func TestParseGeoJSON_LineString(t *testing.T) {
+	workouts, err := ParseCollection("walk.geojson", []byte(geoJSONLineStringSample))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	assert.Equal(t, "Lunch walk", workouts[0].Name)
+	assert.Len(t, workouts[0].Data.Details.Points, 3)
+}
+
+func TestParseKML_NoTracks(t *testing.T) {
+	_, err := ParseCollection("empty.kml", []byte(`<kml><Placemark><Point><coordinates>4,51</coordinates></Point></Placemark></kml>`))
+	require.ErrorIs(t, err, ErrNoTracksFound)
+}
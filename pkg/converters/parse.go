@@ -12,7 +12,7 @@ import (
 
 var (
 	ErrUnsupportedFile = errors.New("unsupported file")
-	SupportedFileTypes = []string{".fit", ".ftb", ".gpx", ".tcx", ".zip"}
+	SupportedFileTypes = []string{".fit", ".ftb", ".geojson", ".gpx", ".json", ".kml", ".kmz", ".sml", ".tcx", ".zip"}
 )
 
 type (
@@ -57,6 +57,10 @@ func ParseCollection(filename string, content []byte) ([]*model.Workout, error)
 }
 
 func parseContent(filename string, content []byte) ([]*model.Workout, error) {
+	if compression := compressionType(filename, content); compression != "" {
+		return parseCompressed(compression, filename, content)
+	}
+
 	suffix := strings.ToLower(path.Ext(filename))
 
 	switch suffix {
@@ -66,6 +70,16 @@ func parseContent(filename string, content []byte) ([]*model.Workout, error) {
 		return ParseFit(content, filename)
 	case ".tcx":
 		return parseSingle(ParseTCX, "tcx", filename, content)
+	case ".kml":
+		return parseSingle(ParseKML, "kml", filename, content)
+	case ".kmz":
+		return parseSingle(ParseKMZ, "kmz", filename, content)
+	case ".geojson":
+		return parseSingle(ParseGeoJSON, "geojson", filename, content)
+	case ".json":
+		return parseJSON(filename, content)
+	case ".sml":
+		return ParseSuuntoSML(content, filename)
 	case ".zip":
 		return ParseZip(content)
 	case ".ftb":
@@ -75,6 +89,18 @@ func parseContent(filename string, content []byte) ([]*model.Workout, error) {
 	}
 }
 
+// parseJSON detects which of the supported JSON formats the content has
+func parseJSON(filename string, content []byte) ([]*model.Workout, error) {
+	switch {
+	case isPolarJSON(content):
+		return ParsePolarJSON(content, filename)
+	case isSuuntoJSON(content):
+		return ParseSuuntoJSON(content, filename)
+	default:
+		return parseSingle(ParseGeoJSON, "geojson", filename, content)
+	}
+}
+
 func parseSingle(f parserFunc, fileType string, filename string, content []byte) ([]*model.Workout, error) {
 	g, err := f(content)
 	if err != nil {
@@ -0,0 +1,266 @@
+package converters
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+const polarCreator = "Polar"
+
+var ErrNoPolarExercises = errors.New("no exercises found in Polar training session")
+
+type (
+	// polarSession is a training session from a Polar Flow data export
+	polarSession struct {
+		Name      string          `json:"name"`
+		Device    string          `json:"device"`
+		Exercises []polarExercise `json:"exercises"`
+	}
+
+	polarExercise struct {
+		StartTime      string       `json:"startTime"` // Local time, without timezone
+		StopTime       string       `json:"stopTime"`
+		TimezoneOffset int          `json:"timezoneOffset"` // In minutes
+		Duration       string       `json:"duration"`       // ISO 8601 duration
+		Distance       float64      `json:"distance"`
+		Sport          string       `json:"sport"`
+		Ascent         float64      `json:"ascent"`
+		Descent        float64      `json:"descent"`
+		HeartRate      polarStat    `json:"heartRate"`
+		Speed          polarStat    `json:"speed"` // In km/h
+		Cadence        polarStat    `json:"cadence"`
+		Power          polarStat    `json:"power"`
+		Laps           []polarLap   `json:"laps"`
+		AutoLaps       []polarLap   `json:"autoLaps"`
+		Samples        polarSamples `json:"samples"`
+	}
+
+	polarStat struct {
+		Min float64 `json:"min"`
+		Avg float64 `json:"avg"`
+		Max float64 `json:"max"`
+	}
+
+	polarLap struct {
+		SplitTime string    `json:"splitTime"` // The time since the start at the end of the lap
+		Duration  string    `json:"duration"`
+		Distance  float64   `json:"distance"`
+		HeartRate polarStat `json:"heartRate"`
+		Speed     polarStat `json:"speed"`
+		Cadence   polarStat `json:"cadence"`
+		Power     polarStat `json:"power"`
+	}
+
+	polarSamples struct {
+		HeartRate     []polarSample     `json:"heartRate"`
+		Speed         []polarSample     `json:"speed"` // In km/h
+		Cadence       []polarSample     `json:"cadence"`
+		Power         []polarSample     `json:"power"`
+		Temperature   []polarSample     `json:"temperature"`
+		Altitude      []polarSample     `json:"altitude"`
+		Distance      []polarSample     `json:"distance"`
+		RecordedRoute []polarRoutePoint `json:"recordedRoute"`
+	}
+
+	polarSample struct {
+		DateTime string   `json:"dateTime"`
+		Value    *float64 `json:"value"`
+	}
+
+	polarRoutePoint struct {
+		DateTime  string   `json:"dateTime"`
+		Latitude  float64  `json:"latitude"`
+		Longitude float64  `json:"longitude"`
+		Altitude  *float64 `json:"altitude"`
+	}
+)
+
+func isPolarJSON(content []byte) bool {
+	return bytes.Contains(content, []byte(`"exercises"`))
+}
+
+// ParsePolarJSON parses a training session from a Polar Flow data export;
+// every exercise (e.g. of a multisport session) becomes a separate workout
+func ParsePolarJSON(content []byte, filename string) ([]*model.Workout, error) {
+	var session polarSession
+	if err := json.Unmarshal(content, &session); err != nil {
+		return nil, err
+	}
+
+	if len(session.Exercises) == 0 {
+		return nil, ErrNoPolarExercises
+	}
+
+	workouts := make([]*model.Workout, 0, len(session.Exercises))
+
+	for i := range session.Exercises {
+		a := session.Exercises[i].activity()
+		if session.Name != "" && len(session.Exercises) == 1 {
+			a.Name = session.Name
+		}
+
+		workouts = append(workouts, a.workout(filename, "json", content))
+	}
+
+	return workouts, nil
+}
+
+func (e *polarExercise) location() *time.Location {
+	return time.FixedZone("", e.TimezoneOffset*60)
+}
+
+func (e *polarExercise) parseTime(s string) time.Time {
+	t, err := time.ParseInLocation("2006-01-02T15:04:05", s, e.location())
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
+
+func (e *polarExercise) activity() *sampledActivity {
+	start := e.parseTime(e.StartTime)
+
+	a := &sampledActivity{
+		Name:     formatFitWorkoutName(e.Sport, start),
+		Type:     sampledActivityType(e.Sport),
+		Creator:  polarCreator,
+		Start:    start,
+		Stop:     e.parseTime(e.StopTime),
+		Distance: e.Distance,
+		Duration: parseISODuration(e.Duration),
+		Stats: model.WorkoutStats{
+			TotalUp:          e.Ascent,
+			TotalDown:        e.Descent,
+			AverageHeartRate: e.HeartRate.Avg,
+			MaxHeartRate:     e.HeartRate.Max,
+			AverageSpeed:     e.Speed.Avg / 3.6,
+			MaxSpeed:         e.Speed.Max / 3.6,
+			AverageCadence:   e.Cadence.Avg,
+			MaxCadence:       e.Cadence.Max,
+			AveragePower:     e.Power.Avg,
+			MaxPower:         e.Power.Max,
+		},
+		Samples: e.samples(),
+	}
+
+	laps := e.Laps
+	if len(laps) == 0 {
+		laps = e.AutoLaps
+	}
+
+	for _, l := range laps {
+		if lap, ok := l.lap(start); ok {
+			a.Laps = append(a.Laps, lap)
+		}
+	}
+
+	return a
+}
+
+// samples merges the separate sample series by their timestamp
+func (e *polarExercise) samples() []activitySample {
+	byTime := map[string]*activitySample{}
+
+	var order []string
+
+	get := func(dateTime string) *activitySample {
+		if s, ok := byTime[dateTime]; ok {
+			return s
+		}
+
+		t := e.parseTime(dateTime)
+		if t.IsZero() {
+			return nil
+		}
+
+		s := &activitySample{Time: t}
+		byTime[dateTime] = s
+		order = append(order, dateTime)
+
+		return s
+	}
+
+	for _, p := range e.Samples.RecordedRoute {
+		if s := get(p.DateTime); s != nil {
+			s.Lat, s.Lng, s.HasPosition = p.Latitude, p.Longitude, true
+
+			if p.Altitude != nil {
+				s.Elevation, s.HasElevation = *p.Altitude, true
+			}
+		}
+	}
+
+	for _, p := range e.Samples.Altitude {
+		if s := get(p.DateTime); s != nil && p.Value != nil && !s.HasElevation {
+			s.Elevation, s.HasElevation = *p.Value, true
+		}
+	}
+
+	for _, p := range e.Samples.Distance {
+		if s := get(p.DateTime); s != nil && p.Value != nil {
+			s.Distance = *p.Value
+		}
+	}
+
+	for metric, series := range map[string][]polarSample{
+		"heart-rate":  e.Samples.HeartRate,
+		"cadence":     e.Samples.Cadence,
+		"power":       e.Samples.Power,
+		"temperature": e.Samples.Temperature,
+		"speed":       e.Samples.Speed,
+	} {
+		for _, p := range series {
+			s := get(p.DateTime)
+			if s == nil || p.Value == nil {
+				continue
+			}
+
+			v := *p.Value
+			if metric == "speed" {
+				v /= 3.6
+			}
+
+			s.setMetric(metric, v)
+		}
+	}
+
+	samples := make([]activitySample, 0, len(order))
+	for _, dt := range order {
+		samples = append(samples, *byTime[dt])
+	}
+
+	return samples
+}
+
+func (l *polarLap) lap(start time.Time) (model.WorkoutLap, bool) {
+	split := parseISODuration(l.SplitTime)
+	duration := parseISODuration(l.Duration)
+
+	if start.IsZero() || split <= 0 || duration <= 0 {
+		return model.WorkoutLap{}, false
+	}
+
+	stop := start.Add(split)
+
+	return model.WorkoutLap{
+		Start:         stop.Add(-duration),
+		Stop:          stop,
+		TotalDistance: l.Distance,
+		TotalDuration: duration,
+		WorkoutStats: model.WorkoutStats{
+			AverageHeartRate: l.HeartRate.Avg,
+			MaxHeartRate:     l.HeartRate.Max,
+			AverageSpeed:     l.Speed.Avg / 3.6,
+			MaxSpeed:         l.Speed.Max / 3.6,
+			AverageCadence:   l.Cadence.Avg,
+			MaxCadence:       l.Cadence.Max,
+			AveragePower:     l.Power.Avg,
+			MaxPower:         l.Power.Max,
+		},
+	}, true
+}
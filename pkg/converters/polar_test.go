@@ -0,0 +1,90 @@
+package converters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const polarSessionSample = `{
+  "exportVersion": "1.6",
+  "name": "Morning run",
+  "device": "Polar Vantage V",
+  "exercises": [{
+    "startTime": "2023-09-10T08:00:00.000",
+    "stopTime": "2023-09-10T08:00:20.000",
+    "timezoneOffset": 120,
+    "duration": "PT20S",
+    "distance": 140,
+    "sport": "RUNNING",
+    "heartRate": {"min": 110, "avg": 125, "max": 140},
+    "speed": {"avg": 25.2, "max": 28.8},
+    "laps": [
+      {"lapNumber": 1, "splitTime": "PT10S", "duration": "PT10S", "distance": 70, "heartRate": {"avg": 120, "max": 130}},
+      {"lapNumber": 2, "splitTime": "PT20S", "duration": "PT10S", "distance": 70, "heartRate": {"avg": 135, "max": 140}}
+    ],
+    "samples": {
+      "heartRate": [
+        {"dateTime": "2023-09-10T08:00:00.000", "value": 110},
+        {"dateTime": "2023-09-10T08:00:10.000", "value": 125},
+        {"dateTime": "2023-09-10T08:00:20.000", "value": 140}
+      ],
+      "cadence": [
+        {"dateTime": "2023-09-10T08:00:00.000", "value": 80},
+        {"dateTime": "2023-09-10T08:00:10.000", "value": 85},
+        {"dateTime": "2023-09-10T08:00:20.000"}
+      ],
+      "temperature": [
+        {"dateTime": "2023-09-10T08:00:00.000", "value": 18.5}
+      ],
+      "recordedRoute": [
+        {"dateTime": "2023-09-10T08:00:00.000", "latitude": 51.2, "longitude": 4.4, "altitude": 12},
+        {"dateTime": "2023-09-10T08:00:10.000", "latitude": 51.201, "longitude": 4.401, "altitude": 13},
+        {"dateTime": "2023-09-10T08:00:20.000", "latitude": 51.202, "longitude": 4.402, "altitude": 14}
+      ]
+    }
+  }]
+}`
+
+func TestParsePolarJSON(t *testing.T) {
+	workouts, err := ParseCollection("training-session-2023-09-10-123.json", []byte(polarSessionSample))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Equal(t, "Morning run", w.Name)
+	assert.Equal(t, "running", w.Data.Type)
+	assert.Equal(t, "Polar", w.Data.Creator)
+	assert.Equal(t, time.Date(2023, 9, 10, 6, 0, 0, 0, time.UTC), w.Date.UTC())
+	assert.Equal(t, 20*time.Second, w.Data.TotalDuration)
+
+	points := w.Data.Details.Points
+	require.Len(t, points, 3)
+	assert.InDelta(t, 51.201, points[1].Lat, 1e-6)
+	assert.InDelta(t, 13, points[1].Elevation, 0.001)
+	assert.InDelta(t, 125, points[1].ExtraMetrics.Get("heart-rate"), 0.001)
+	assert.InDelta(t, 85, points[1].ExtraMetrics.Get("cadence"), 0.001)
+	assert.InDelta(t, 18.5, points[0].ExtraMetrics.Get("temperature"), 0.001)
+	assert.NotContains(t, points[2].ExtraMetrics, "cadence")
+
+	require.Len(t, w.Data.Laps, 2)
+	assert.Equal(t, w.Date.Add(10*time.Second), w.Data.Laps[1].Start)
+	assert.InDelta(t, 135, w.Data.Laps[1].AverageHeartRate, 0.001)
+}
+
+func TestParseJSON_GeoJSON(t *testing.T) {
+	content := []byte(`{"type": "LineString", "coordinates": [[4.4, 51.2], [4.401, 51.201]]}`)
+
+	workouts, err := ParseCollection("route.json", content)
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+	assert.Len(t, workouts[0].Data.Details.Points, 2)
+}
+
+func TestParseISODuration(t *testing.T) {
+	assert.Equal(t, 20*time.Second, parseISODuration("PT20S"))
+	assert.Equal(t, time.Hour+2*time.Minute+3500*time.Millisecond, parseISODuration("PT1H2M3.5S"))
+	assert.Zero(t, parseISODuration("20"))
+}
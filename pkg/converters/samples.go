@@ -0,0 +1,338 @@
+package converters
+
+import (
+	"encoding/xml"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/spf13/cast"
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+// sampleMaxAge is how long a metric or elevation is carried forward to
+// samples with a position; devices often record them as separate samples
+const sampleMaxAge = 5 * time.Second
+
+type (
+	// sampledActivity is an activity recorded as a series of samples, as
+	// exported by Suunto and Polar; both are converted to a workout the same
+	// way
+	sampledActivity struct {
+		Name     string
+		Type     string
+		Creator  string
+		Start    time.Time
+		Stop     time.Time
+		Distance float64       // The total distance, in meters
+		Duration time.Duration // The total duration
+		Stats    model.WorkoutStats
+		Laps     []model.WorkoutLap
+		Samples  []activitySample
+	}
+
+	activitySample struct {
+		Time         time.Time
+		Lat          float64
+		Lng          float64
+		HasPosition  bool
+		Elevation    float64
+		HasElevation bool
+		Distance     float64            // The total distance up to this sample, in meters; 0 when unknown
+		Metrics      map[string]float64 // heart-rate, cadence, power, temperature, speed
+	}
+)
+
+func (s *activitySample) setMetric(name string, value float64) {
+	if math.IsNaN(value) || math.IsInf(value, 0) {
+		return
+	}
+
+	if s.Metrics == nil {
+		s.Metrics = map[string]float64{}
+	}
+
+	s.Metrics[name] = value
+}
+
+// workout converts the activity to a workout; activities without any
+// position keep their samples without coordinates, so charts and breakdowns
+// remain available without a map
+func (a *sampledActivity) workout(filename string, fileType string, content []byte) *model.Workout {
+	slices.SortStableFunc(a.Samples, func(x, y activitySample) int { return x.Time.Compare(y.Time) })
+
+	var data *model.MapData
+
+	if slices.ContainsFunc(a.Samples, func(s activitySample) bool { return s.HasPosition }) {
+		a.carryForward()
+		data = model.MapDataFromGPX(a.gpx())
+	} else {
+		data = a.mapDataWithoutPositions()
+	}
+
+	if data == nil {
+		data = &model.MapData{}
+	}
+
+	data.Creator = a.Creator
+	data.WorkoutData.Name = a.Name
+	data.WorkoutData.Type = a.Type
+	data.WorkoutData.MergeNonZero(model.WorkoutData{
+		Start:         a.Start,
+		Stop:          a.Stop,
+		TotalDistance: a.Distance,
+		TotalDuration: a.Duration,
+		WorkoutStats:  a.Stats,
+		Laps:          a.Laps,
+	})
+
+	data.UpdateExtraMetrics()
+	sanitizeMapData(data)
+
+	w := &model.Workout{
+		Data: data,
+		Name: a.Name,
+		Date: firstNonZeroTime(a.Start, data.Start),
+	}
+
+	setContentAndName(w, filename, fileType, content)
+	w.UpdateAverages()
+	w.UpdateExtraMetrics()
+
+	return w
+}
+
+// carryForward copies the most recent metrics and elevation to the samples
+// with a position that lack them, since only those samples end up in the
+// track
+func (a *sampledActivity) carryForward() {
+	type recent struct {
+		time  time.Time
+		value float64
+	}
+
+	var elevation *recent
+
+	metrics := map[string]recent{}
+
+	for i := range a.Samples {
+		s := &a.Samples[i]
+
+		for name, v := range s.Metrics {
+			metrics[name] = recent{s.Time, v}
+		}
+
+		if s.HasElevation {
+			elevation = &recent{s.Time, s.Elevation}
+		}
+
+		if !s.HasPosition {
+			continue
+		}
+
+		for name, r := range metrics {
+			if _, ok := s.Metrics[name]; !ok && s.Time.Sub(r.time) <= sampleMaxAge {
+				s.setMetric(name, r.value)
+			}
+		}
+
+		if !s.HasElevation && elevation != nil && s.Time.Sub(elevation.time) <= sampleMaxAge {
+			s.Elevation, s.HasElevation = elevation.value, true
+		}
+	}
+}
+
+func (a *sampledActivity) gpx() *gpx.GPX {
+	g := &gpx.GPX{
+		Name:    a.Name,
+		Creator: a.Creator,
+	}
+
+	if !a.Start.IsZero() {
+		g.Time = &a.Start
+	}
+
+	g.AppendTrack(&gpx.GPXTrack{Name: a.Name, Type: a.Type})
+
+	for _, s := range a.Samples {
+		if !s.HasPosition {
+			continue
+		}
+
+		p := &gpx.GPXPoint{
+			Timestamp: s.Time,
+			Point: gpx.Point{
+				Latitude:  s.Lat,
+				Longitude: s.Lng,
+			},
+		}
+
+		if s.HasElevation {
+			p.Elevation = *gpx.NewNullableFloat64(s.Elevation)
+		}
+
+		for _, name := range sortedMetricNames(s.Metrics) {
+			p.Extensions.Nodes = append(p.Extensions.Nodes, gpx.ExtensionNode{
+				XMLName: xml.Name{Local: name}, Data: strconv.FormatFloat(s.Metrics[name], 'f', -1, 64),
+			})
+		}
+
+		g.AppendPoint(p)
+	}
+
+	return g
+}
+
+func (a *sampledActivity) mapDataWithoutPositions() *model.MapData {
+	if len(a.Samples) == 0 {
+		return nil
+	}
+
+	points := make([]model.MapPoint, 0, len(a.Samples))
+
+	var (
+		prevTime      time.Time
+		prevDistance  float64
+		totalDuration time.Duration
+	)
+
+	for _, s := range a.Samples {
+		if s.Time.IsZero() {
+			continue
+		}
+
+		dt := time.Duration(0)
+		if !prevTime.IsZero() {
+			dt = max(s.Time.Sub(prevTime), 0)
+		}
+
+		prevTime = s.Time
+		totalDuration += dt
+
+		deltaDist := 0.0
+		if s.Distance > prevDistance {
+			deltaDist = s.Distance - prevDistance
+			prevDistance = s.Distance
+		}
+
+		extra := model.ExtraMetrics{}
+		for name, v := range s.Metrics {
+			extra.Set(name, v)
+		}
+
+		if s.HasElevation {
+			extra.Set("elevation", s.Elevation)
+		}
+
+		points = append(points, model.MapPoint{
+			Time:          s.Time,
+			Elevation:     s.Elevation,
+			Distance:      deltaDist,
+			TotalDistance: prevDistance,
+			Duration:      dt,
+			TotalDuration: totalDuration,
+			ExtraMetrics:  extra,
+		})
+	}
+
+	if len(points) == 0 {
+		return nil
+	}
+
+	return &model.MapData{
+		Details: &model.MapDataDetails{Points: points},
+		WorkoutData: model.WorkoutData{
+			Start:         points[0].Time,
+			Stop:          points[len(points)-1].Time,
+			TotalDistance: prevDistance,
+			TotalDuration: totalDuration,
+		},
+	}
+}
+
+func sortedMetricNames(metrics map[string]float64) []string {
+	names := make([]string, 0, len(metrics))
+	for name := range metrics {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// parseISODuration parses the subset of ISO 8601 durations used by sport
+// exports, e.g. "PT1H2M3.5S"
+func parseISODuration(s string) time.Duration {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	if !strings.HasPrefix(s, "PT") {
+		return 0
+	}
+
+	var (
+		d   time.Duration
+		num strings.Builder
+	)
+
+	for _, r := range s[2:] {
+		var unit time.Duration
+
+		switch r {
+		case 'H':
+			unit = time.Hour
+		case 'M':
+			unit = time.Minute
+		case 'S':
+			unit = time.Second
+		default:
+			num.WriteRune(r)
+			continue
+		}
+
+		d += time.Duration(cast.ToFloat64(num.String()) * float64(unit))
+		num.Reset()
+	}
+
+	return d
+}
+
+// sampledActivityType maps the sport names used by Suunto and Polar (e.g.
+// "Trail running", "MOUNTAIN_BIKING") to the types known by the workout type
+// auto-detection
+func sampledActivityType(sport string) string {
+	s := strings.ToLower(sport)
+
+	switch {
+	case s == "":
+		return ""
+	case strings.Contains(s, "horse") || s == "riding":
+		return "horse-riding"
+	case strings.Contains(s, "run") || strings.Contains(s, "jog"):
+		return "running"
+	case strings.Contains(s, "bik") || strings.Contains(s, "cycl") || strings.Contains(s, "ride"):
+		return "cycling"
+	case strings.Contains(s, "walk"):
+		return "walking"
+	case strings.Contains(s, "hik") || strings.Contains(s, "trek"):
+		return "hiking"
+	case strings.Contains(s, "swim"):
+		return "swimming"
+	case strings.Contains(s, "snowboard"):
+		return "snowboarding"
+	case strings.Contains(s, "ski"):
+		return "skiing"
+	case strings.Contains(s, "skat"):
+		return "inline-skating"
+	case strings.Contains(s, "kayak") || strings.Contains(s, "canoe") || strings.Contains(s, "paddl"):
+		return "kayaking"
+	case strings.Contains(s, "row"):
+		return "rowing"
+	case strings.Contains(s, "golf"):
+		return "golfing"
+	default:
+		return s
+	}
+}
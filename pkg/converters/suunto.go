@@ -0,0 +1,322 @@
+package converters
+
+import (
+	"bytes"
+	"encoding/json"
+	"encoding/xml"
+	"errors"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/spf13/cast"
+)
+
+const (
+	suuntoCreator = "Suunto"
+
+	// Suunto stores temperatures in Kelvin
+	suuntoKelvinOffset = 273.15
+)
+
+var ErrNoSuuntoSamples = errors.New("no samples found in Suunto log")
+
+// xmlNode is a generic XML element, used to read the free-form SML samples
+type xmlNode struct {
+	XMLName xml.Name
+	Content string    `xml:",chardata"`
+	Nodes   []xmlNode `xml:",any"`
+}
+
+// value converts the element to the same shape encoding/json produces:
+// leaves become strings, repeated elements become lists
+func (n *xmlNode) value() any {
+	if len(n.Nodes) == 0 {
+		return strings.TrimSpace(n.Content)
+	}
+
+	m := map[string]any{}
+
+	for i := range n.Nodes {
+		child := &n.Nodes[i]
+		name := child.XMLName.Local
+
+		existing, ok := m[name]
+		if !ok {
+			m[name] = child.value()
+			continue
+		}
+
+		list, isList := existing.([]any)
+		if !isList {
+			list = []any{existing}
+		}
+
+		m[name] = append(list, child.value())
+	}
+
+	return m
+}
+
+// ParseSuuntoSML parses a Suunto SML file, the XML format used by Suunto
+// (Moveslink) and the Suunto app
+func ParseSuuntoSML(content []byte, filename string) ([]*model.Workout, error) {
+	var root xmlNode
+	if err := xml.Unmarshal(content, &root); err != nil {
+		return nil, err
+	}
+
+	m, _ := root.value().(map[string]any)
+
+	return parseSuuntoLog(m, filename, "sml", content)
+}
+
+// ParseSuuntoJSON parses a Suunto JSON log, as exported by the Suunto app
+func ParseSuuntoJSON(content []byte, filename string) ([]*model.Workout, error) {
+	var m map[string]any
+	if err := json.Unmarshal(content, &m); err != nil {
+		return nil, err
+	}
+
+	return parseSuuntoLog(m, filename, "json", content)
+}
+
+func isSuuntoJSON(content []byte) bool {
+	return bytes.Contains(content, []byte(`"DeviceLog"`)) ||
+		(bytes.Contains(content, []byte(`"Samples"`)) && bytes.Contains(content, []byte(`"TimeISO8601"`)))
+}
+
+func parseSuuntoLog(m map[string]any, filename string, fileType string, content []byte) ([]*model.Workout, error) {
+	log := m
+	if dl, ok := m["DeviceLog"].(map[string]any); ok {
+		log = dl
+	}
+
+	header := cast.ToStringMap(log["Header"])
+	start := parseSuuntoTime(header["DateTime"])
+	activity := cast.ToString(header["Activity"])
+
+	a := &sampledActivity{
+		Type:     sampledActivityType(activity),
+		Creator:  suuntoCreator,
+		Distance: cast.ToFloat64(header["Distance"]),
+		Duration: durationFromSeconds(cast.ToFloat64(header["Duration"])),
+		Stats: model.WorkoutStats{
+			TotalUp:          cast.ToFloat64(header["Ascent"]),
+			TotalDown:        cast.ToFloat64(header["Descent"]),
+			AverageHeartRate: suuntoStat(header["HR"], "Avg") * 60,
+			MaxHeartRate:     suuntoStat(header["HR"], "Max") * 60,
+		},
+	}
+
+	for _, s := range suuntoList(log["Samples"], "Sample") {
+		if sample, ok := parseSuuntoSample(s, start); ok {
+			a.Samples = append(a.Samples, sample)
+		}
+	}
+
+	if len(a.Samples) == 0 {
+		return nil, ErrNoSuuntoSamples
+	}
+
+	// The header has the local time without a timezone, the samples have
+	// the actual time
+	a.Start = a.Samples[0].Time
+	for _, s := range a.Samples {
+		if s.Time.Before(a.Start) {
+			a.Start = s.Time
+		}
+	}
+
+	a.Name = formatFitWorkoutName(activity, a.Start)
+
+	if a.Duration > 0 {
+		a.Stop = a.Start.Add(a.Duration)
+	}
+
+	for _, w := range suuntoList(log["Windows"], "Window") {
+		if lap, ok := parseSuuntoLap(w); ok {
+			a.Laps = append(a.Laps, lap)
+		}
+	}
+
+	return []*model.Workout{a.workout(filename, fileType, content)}, nil
+}
+
+func parseSuuntoSample(s map[string]any, start time.Time) (activitySample, bool) {
+	// Samples from the Suunto app nest the actual values
+	if attrs, ok := s["Attributes"].(map[string]any); ok {
+		if sml, ok := attrs["suunto/sml"].(map[string]any); ok {
+			for k, v := range cast.ToStringMap(sml["Sample"]) {
+				s[k] = v
+			}
+		}
+	}
+
+	sample := activitySample{
+		Time: parseSuuntoTime(firstNonNil(s["TimeISO8601"], s["UTC"])),
+	}
+
+	if sample.Time.IsZero() {
+		offset, err := cast.ToFloat64E(s["Time"])
+		if err != nil || s["Time"] == nil || start.IsZero() {
+			return sample, false
+		}
+
+		sample.Time = start.Add(durationFromSeconds(offset))
+	}
+
+	lat, latErr := cast.ToFloat64E(s["Latitude"])
+	lng, lngErr := cast.ToFloat64E(s["Longitude"])
+
+	if latErr == nil && lngErr == nil && s["Latitude"] != nil && s["Longitude"] != nil {
+		// Positions are stored in radians
+		sample.Lat = lat * 180 / math.Pi
+		sample.Lng = lng * 180 / math.Pi
+		sample.HasPosition = true
+	}
+
+	for _, key := range []string{"GPSAltitude", "Altitude"} {
+		if v, err := cast.ToFloat64E(s[key]); err == nil && s[key] != nil {
+			sample.Elevation, sample.HasElevation = v, true
+			break
+		}
+	}
+
+	sample.Distance = cast.ToFloat64(s["Distance"])
+
+	for key, metric := range map[string]string{
+		"HR":          "heart-rate",
+		"Cadence":     "cadence",
+		"Power":       "power",
+		"Temperature": "temperature",
+		"Speed":       "speed",
+	} {
+		if s[key] == nil {
+			continue
+		}
+
+		v, err := cast.ToFloat64E(s[key])
+		if err != nil {
+			continue
+		}
+
+		switch key {
+		case "HR", "Cadence":
+			// Heart rate and cadence are stored in Hz
+			v *= 60
+		case "Temperature":
+			v -= suuntoKelvinOffset
+		}
+
+		sample.setMetric(metric, v)
+	}
+
+	if len(sample.Metrics) == 0 && !sample.HasPosition && !sample.HasElevation && sample.Distance == 0 {
+		// E.g. event samples, without any measurement
+		return sample, false
+	}
+
+	return sample, true
+}
+
+func parseSuuntoLap(w map[string]any) (model.WorkoutLap, bool) {
+	switch strings.ToLower(cast.ToString(w["Type"])) {
+	case "lap", "autolap", "manual", "interval":
+	default:
+		return model.WorkoutLap{}, false
+	}
+
+	stop := parseSuuntoTime(firstNonNil(w["TimeISO8601"], w["UTC"]))
+	duration := durationFromSeconds(cast.ToFloat64(w["Duration"]))
+
+	if stop.IsZero() || duration <= 0 {
+		return model.WorkoutLap{}, false
+	}
+
+	lap := model.WorkoutLap{
+		Start:         stop.Add(-duration),
+		Stop:          stop,
+		TotalDistance: cast.ToFloat64(w["Distance"]),
+		TotalDuration: duration,
+		WorkoutStats: model.WorkoutStats{
+			AverageHeartRate: suuntoStat(w["HR"], "Avg") * 60,
+			MaxHeartRate:     suuntoStat(w["HR"], "Max") * 60,
+			AverageSpeed:     suuntoStat(w["Speed"], "Avg"),
+			MaxSpeed:         suuntoStat(w["Speed"], "Max"),
+			AverageCadence:   suuntoStat(w["Cadence"], "Avg") * 60,
+			MaxCadence:       suuntoStat(w["Cadence"], "Max") * 60,
+			AveragePower:     suuntoStat(w["Power"], "Avg"),
+			MaxPower:         suuntoStat(w["Power"], "Max"),
+		},
+	}
+
+	return lap, true
+}
+
+// suuntoList returns the elements of a list, which is either a JSON array
+// (with optionally every element wrapped in an object with the element
+// name), or an XML element containing one or more child elements
+func suuntoList(v any, element string) []map[string]any {
+	if m, ok := v.(map[string]any); ok {
+		v = m[element]
+	}
+
+	var items []any
+
+	switch l := v.(type) {
+	case []any:
+		items = l
+	case map[string]any:
+		items = []any{l}
+	}
+
+	result := make([]map[string]any, 0, len(items))
+
+	for _, item := range items {
+		m, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+
+		if inner, ok := m[element].(map[string]any); ok {
+			m = inner
+		}
+
+		result = append(result, m)
+	}
+
+	return result
+}
+
+// suuntoStat returns a summary value (e.g. "Avg") of a statistic, which is
+// stored as an object or a list with a single object
+func suuntoStat(v any, key string) float64 {
+	if l, ok := v.([]any); ok && len(l) > 0 {
+		v = l[0]
+	}
+
+	return cast.ToFloat64(cast.ToStringMap(v)[key])
+}
+
+func parseSuuntoTime(v any) time.Time {
+	s := cast.ToString(v)
+	if s == "" {
+		return time.Time{}
+	}
+
+	t, _ := parseKMLTime(s)
+
+	return t
+}
+
+func firstNonNil(values ...any) any {
+	for _, v := range values {
+		if v != nil {
+			return v
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package converters
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const suuntoSMLSample = `<?xml version="1.0" encoding="utf-8"?>
+<sml xmlns="http://www.suunto.com/schemas/sml">
+  <DeviceLog>
+    <Header>
+      <Activity>Running</Activity>
+      <DateTime>2023-09-10T20:00:00.000</DateTime>
+      <Duration>20</Duration>
+      <Distance>140</Distance>
+      <Ascent>2</Ascent>
+    </Header>
+    <Windows>
+      <Window>
+        <Type>Lap</Type>
+        <UTC>2023-09-10T18:00:10.000Z</UTC>
+        <Duration>10</Duration>
+        <Distance>70</Distance>
+      </Window>
+      <Window>
+        <Type>Move</Type>
+        <UTC>2023-09-10T18:00:20.000Z</UTC>
+        <Duration>20</Duration>
+      </Window>
+    </Windows>
+    <Samples>
+      <Sample>
+        <UTC>2023-09-10T18:00:00.000Z</UTC>
+        <HR>2</HR>
+        <Cadence>1.4</Cadence>
+        <Temperature>293.15</Temperature>
+        <Altitude>12</Altitude>
+      </Sample>
+      <Sample>
+        <UTC>2023-09-10T18:00:00.000Z</UTC>
+        <Latitude>0.8936085770210968</Latitude>
+        <Longitude>0.07679448708775051</Longitude>
+      </Sample>
+      <Sample>
+        <UTC>2023-09-10T18:00:10.000Z</UTC>
+        <Latitude>0.8936260303136166</Latitude>
+        <Longitude>0.07681194038027044</Longitude>
+        <HR>2.5</HR>
+      </Sample>
+      <Sample>
+        <UTC>2023-09-10T18:00:20.000Z</UTC>
+        <Latitude>0.8936434836061365</Latitude>
+        <Longitude>0.07682939367279039</Longitude>
+        <HR>3</HR>
+        <Power>250</Power>
+      </Sample>
+      <Sample>
+        <UTC>2023-09-10T18:00:20.000Z</UTC>
+        <Events><Lap><Type>Manual</Type></Lap></Events>
+      </Sample>
+    </Samples>
+  </DeviceLog>
+</sml>`
+
+const suuntoJSONSample = `{"DeviceLog": {
+  "Header": {"Activity": "Cycling", "DateTime": "2023-09-10T20:00:00.000", "Duration": 20, "Distance": 300},
+  "Samples": [
+    {"TimeISO8601": "2023-09-10T20:00:00.000+02:00", "Attributes": {"suunto/sml": {"Sample": {"HR": 2, "Distance": 0, "Power": 180}}}},
+    {"TimeISO8601": "2023-09-10T20:00:10.000+02:00", "Attributes": {"suunto/sml": {"Sample": {"HR": 2.2, "Distance": 150, "Power": 200}}}},
+    {"TimeISO8601": "2023-09-10T20:00:20.000+02:00", "Attributes": {"suunto/sml": {"Sample": {"HR": 2.4, "Distance": 300, "Power": 220}}}}
+  ],
+  "Windows": [
+    {"Window": {"Type": "Autolap", "TimeISO8601": "2023-09-10T20:00:20.000+02:00", "Duration": 20, "Distance": 300, "HR": [{"Avg": 2.2, "Max": 2.4}]}}
+  ]
+}}`
+
+func TestParseSuuntoSML(t *testing.T) {
+	workouts, err := ParseCollection("run.sml", []byte(suuntoSMLSample))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Equal(t, "running", w.Data.Type)
+	assert.Equal(t, "Suunto", w.Data.Creator)
+	assert.Equal(t, time.Date(2023, 9, 10, 18, 0, 0, 0, time.UTC), w.Date.UTC())
+	assert.Equal(t, 20*time.Second, w.Data.TotalDuration)
+
+	points := w.Data.Details.Points
+	require.Len(t, points, 3)
+	assert.InDelta(t, 51.2, points[0].Lat, 1e-6)
+	assert.InDelta(t, 4.4, points[0].Lng, 1e-6)
+
+	// Metrics recorded in a separate sample are carried to the position
+	assert.InDelta(t, 120, points[0].ExtraMetrics.Get("heart-rate"), 0.001)
+	assert.InDelta(t, 84, points[0].ExtraMetrics.Get("cadence"), 0.001)
+	assert.InDelta(t, 20, points[0].ExtraMetrics.Get("temperature"), 0.001)
+	assert.InDelta(t, 12, points[0].Elevation, 0.001)
+	assert.InDelta(t, 150, points[1].ExtraMetrics.Get("heart-rate"), 0.001)
+	assert.InDelta(t, 250, points[2].ExtraMetrics.Get("power"), 0.001)
+
+	require.Len(t, w.Data.Laps, 1)
+	assert.InDelta(t, 70, w.Data.Laps[0].TotalDistance, 0.001)
+	assert.Equal(t, 10*time.Second, w.Data.Laps[0].TotalDuration)
+}
+
+func TestParseSuuntoJSON(t *testing.T) {
+	workouts, err := ParseCollection("ride.json", []byte(suuntoJSONSample))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	w := workouts[0]
+	assert.Equal(t, "cycling", w.Data.Type)
+	assert.InDelta(t, 300, w.Data.TotalDistance, 0.001)
+
+	// Without positions, the samples are kept without coordinates
+	points := w.Data.Details.Points
+	require.Len(t, points, 3)
+	assert.Zero(t, points[1].Lat)
+	assert.InDelta(t, 150, points[1].TotalDistance, 0.001)
+	assert.InDelta(t, 132, points[1].ExtraMetrics.Get("heart-rate"), 0.001)
+	assert.InDelta(t, 200, points[1].ExtraMetrics.Get("power"), 0.001)
+	assert.Contains(t, w.Data.ExtraMetrics, "power")
+
+	require.Len(t, w.Data.Laps, 1)
+	assert.InDelta(t, 132, w.Data.Laps[0].AverageHeartRate, 0.001)
+}
@@ -78,5 +78,11 @@ func tcxPtToGPXPt(t *tcx.Trackpoint) *gpx.GPXPoint {
 		})
 	}
 
+	if t.Power != 0 {
+		p.Extensions.Nodes = append(p.Extensions.Nodes, gpx.ExtensionNode{
+			XMLName: xml.Name{Local: "power"}, Data: cast.ToString(t.Power),
+		})
+	}
+
 	return p
 }
@@ -0,0 +1,248 @@
+// Package dem samples elevations from digital elevation model (DEM) files in
+// a local directory: SRTM HGT tiles (e.g. N50E004.hgt) and GeoTIFF files in
+// geographic coordinates (e.g. Copernicus DEM tiles).
+package dem
+
+import (
+	"errors"
+	"io/fs"
+	"math"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// maxCachedFiles is the number of DEM files kept in memory; a tile of 1 arc
+// second takes about 50MB
+const maxCachedFiles = 4
+
+var (
+	ErrNotConfigured   = errors.New("dem: no directory configured")
+	ErrUnsupportedFile = errors.New("dem: unsupported file")
+
+	current *Directory
+	m       sync.RWMutex
+)
+
+// SetDirectory configures the directory with the DEM files; an empty path
+// disables the elevation lookups
+func SetDirectory(path string) {
+	m.Lock()
+	defer m.Unlock()
+
+	if path == "" {
+		current = nil
+		return
+	}
+
+	current = NewDirectory(path)
+}
+
+// Enabled returns whether a directory with DEM files is configured
+func Enabled() bool {
+	m.RLock()
+	defer m.RUnlock()
+
+	return current != nil
+}
+
+// Elevation returns the elevation at the coordinates, in meters, from the
+// configured directory; it returns false when no DEM file covers the
+// coordinates
+func Elevation(lat, lng float64) (float64, bool) {
+	m.RLock()
+	d := current
+	m.RUnlock()
+
+	if d == nil {
+		return 0, false
+	}
+
+	return d.Elevation(lat, lng)
+}
+
+// Directory is a directory with DEM files; the files are indexed on the
+// first lookup, and loaded when they are needed
+type Directory struct {
+	path string
+
+	m        sync.Mutex
+	indexed  bool
+	hgt      map[string]string // The paths of the HGT files, by tile name
+	geoTIFFs []*geoTIFF        // The GeoTIFF files, with their bounds
+	grids    map[string]*grid  // The loaded files, by path; nil if loading failed
+	loaded   []string          // The paths of the loaded files, oldest first
+}
+
+// NewDirectory returns the DEM files in the directory and its subdirectories
+func NewDirectory(path string) *Directory {
+	return &Directory{path: path}
+}
+
+// Elevation returns the elevation at the coordinates, in meters; it returns
+// false when no file covers the coordinates
+func (d *Directory) Elevation(lat, lng float64) (float64, bool) {
+	if math.IsNaN(lat) || math.IsNaN(lng) {
+		return 0, false
+	}
+
+	d.m.Lock()
+	defer d.m.Unlock()
+
+	d.index()
+
+	if p, ok := d.hgt[hgtName(lat, lng)]; ok {
+		if g := d.load(p, func() (*grid, error) { return readHGT(p) }); g != nil {
+			if v, ok := g.elevation(lat, lng); ok {
+				return v, true
+			}
+		}
+	}
+
+	for _, t := range d.geoTIFFs {
+		if !t.covers(lat, lng) {
+			continue
+		}
+
+		if g := d.load(t.path, t.read); g != nil {
+			if v, ok := g.elevation(lat, lng); ok {
+				return v, true
+			}
+		}
+	}
+
+	return 0, false
+}
+
+// index finds the DEM files in the directory; files that can not be read are
+// skipped
+func (d *Directory) index() {
+	if d.indexed {
+		return
+	}
+
+	d.indexed = true
+	d.hgt = map[string]string{}
+	d.grids = map[string]*grid{}
+
+	_ = filepath.WalkDir(d.path, func(path string, e fs.DirEntry, err error) error {
+		if err != nil || e.IsDir() {
+			return nil //nolint:nilerr // Unreadable parts of the directory are skipped
+		}
+
+		name := upperBase(path)
+
+		switch filepath.Ext(name) {
+		case ".HGT":
+			if _, _, ok := parseHGTName(name); ok {
+				d.hgt[strings.TrimSuffix(name, ".HGT")] = path
+			}
+		case ".TIF", ".TIFF":
+			if t, err := openGeoTIFF(path); err == nil {
+				d.geoTIFFs = append(d.geoTIFFs, t)
+			}
+		}
+
+		return nil
+	})
+
+	sort.Slice(d.geoTIFFs, func(i, j int) bool {
+		return d.geoTIFFs[i].path < d.geoTIFFs[j].path
+	})
+}
+
+// load returns the grid of the file, reading it when it is not loaded yet;
+// the oldest files are dropped when too many files are loaded
+func (d *Directory) load(path string, read func() (*grid, error)) *grid {
+	if g, ok := d.grids[path]; ok {
+		return g
+	}
+
+	g, err := read()
+	if err != nil {
+		g = nil
+	}
+
+	if len(d.loaded) >= maxCachedFiles {
+		delete(d.grids, d.loaded[0])
+		d.loaded = d.loaded[1:]
+	}
+
+	d.grids[path] = g
+	d.loaded = append(d.loaded, path)
+
+	return g
+}
+
+// grid is a raster of elevation samples, in rows from north to south
+type grid struct {
+	west, north float64   // The coordinates of the first sample
+	dLng, dLat  float64   // The distance between two samples, in degrees
+	width       int       // The number of samples in a row
+	height      int       // The number of rows
+	values      []float32 // The samples, row by row
+	noData      float32   // The value of missing samples
+	hasNoData   bool
+}
+
+// position returns the position of the coordinates in the grid, in samples
+func (g *grid) position(lat, lng float64) (float64, float64) {
+	return (lng - g.west) / g.dLng, (g.north - lat) / g.dLat
+}
+
+// contains returns whether the coordinates are within the grid; coordinates
+// within half a sample of the edge use the edge samples
+func (g *grid) contains(lat, lng float64) bool {
+	x, y := g.position(lat, lng)
+
+	return x >= -0.5 && y >= -0.5 && x <= float64(g.width)-0.5 && y <= float64(g.height)-0.5
+}
+
+// elevation interpolates the samples around the coordinates; missing
+// samples are ignored
+func (g *grid) elevation(lat, lng float64) (float64, bool) {
+	if !g.contains(lat, lng) {
+		return 0, false
+	}
+
+	x, y := g.position(lat, lng)
+
+	x = min(max(x, 0), float64(g.width-1))
+	y = min(max(y, 0), float64(g.height-1))
+
+	x0, y0 := int(x), int(y)
+	x1, y1 := min(x0+1, g.width-1), min(y0+1, g.height-1)
+	fx, fy := x-float64(x0), y-float64(y0)
+
+	var sum, weights float64
+
+	for _, s := range [...]struct {
+		x, y int
+		w    float64
+	}{
+		{x0, y0, (1 - fx) * (1 - fy)},
+		{x1, y0, fx * (1 - fy)},
+		{x0, y1, (1 - fx) * fy},
+		{x1, y1, fx * fy},
+	} {
+		v := g.values[s.y*g.width+s.x]
+		if (g.hasNoData && v == g.noData) || math.IsNaN(float64(v)) {
+			continue
+		}
+
+		sum += s.w * float64(v)
+		weights += s.w
+	}
+
+	if weights == 0 {
+		return 0, false
+	}
+
+	return sum / weights, true
+}
+
+// upperBase returns the upper case file name of the path
+func upperBase(path string) string {
+	return strings.ToUpper(filepath.Base(path))
+}
@@ -0,0 +1,271 @@
+package dem
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHGTName(t *testing.T) {
+	assert.Equal(t, "N50E004", hgtName(50.5, 4.5))
+	assert.Equal(t, "S01W001", hgtName(-0.5, -0.5))
+	assert.Equal(t, "N00W180", hgtName(0.1, -179.9))
+
+	lat, lng, ok := parseHGTName("S12W077.HGT")
+	require.True(t, ok)
+	assert.Equal(t, -12, lat)
+	assert.Equal(t, -77, lng)
+
+	_, _, ok = parseHGTName("X12W077.HGT")
+	assert.False(t, ok)
+}
+
+func TestDirectory_ElevationHGT(t *testing.T) {
+	dir := t.TempDir()
+
+	// A tile of 3 by 3 samples, every half degree, from north to south
+	samples := []int16{
+		100, 200, 300,
+		0, 100, hgtVoid,
+		0, 0, 0,
+	}
+
+	data := make([]byte, 2*len(samples))
+	for i, s := range samples {
+		binary.BigEndian.PutUint16(data[2*i:], uint16(s))
+	}
+
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "n50e004.hgt"), data, 0o600))
+
+	d := NewDirectory(dir)
+
+	v, ok := d.Elevation(50.5, 4.5)
+	require.True(t, ok)
+	assert.InDelta(t, 100, v, 0.001)
+
+	v, ok = d.Elevation(50.75, 4)
+	require.True(t, ok)
+	assert.InDelta(t, 50, v, 0.001)
+
+	v, ok = d.Elevation(50.75, 4.25)
+	require.True(t, ok)
+	assert.InDelta(t, 100, v, 0.001)
+
+	// The void sample is ignored
+	v, ok = d.Elevation(50.25, 4.75)
+	require.True(t, ok)
+	assert.InDelta(t, 100.0/3, v, 0.001)
+
+	_, ok = d.Elevation(50.5, 5.5)
+	assert.False(t, ok)
+}
+
+func TestDirectory_ElevationGeoTIFF(t *testing.T) {
+	tests := []struct {
+		name   string
+		order  tiffByteOrder
+		format string
+	}{
+		{name: "float32 deflate", order: binary.LittleEndian, format: "float32"},
+		{name: "int16 big endian", order: binary.BigEndian, format: "int16"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			require.NoError(t, os.WriteFile(filepath.Join(dir, "tile.tif"), buildTestGeoTIFF(tt.order, tt.format), 0o600))
+
+			d := NewDirectory(dir)
+
+			for _, c := range [][2]float64{{50.875, 4.125}, {50.5, 4.5}, {50.2, 4.8}, {50.9, 4.1}} {
+				// The samples are a plane, so the interpolation is exact
+				x := (c[1] - 4.125) / 0.25
+				y := (50.875 - c[0]) / 0.25
+				expected := 100 + 10*min(max(x, 0), 3) - 5*min(max(y, 0), 3)
+
+				v, ok := d.Elevation(c[0], c[1])
+				require.True(t, ok)
+				assert.InDelta(t, expected, v, 0.01, "at %v", c)
+			}
+
+			_, ok := d.Elevation(49.9, 4.5)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestElevation(t *testing.T) {
+	SetDirectory("")
+	assert.False(t, Enabled())
+
+	_, ok := Elevation(50.5, 4.5)
+	assert.False(t, ok)
+
+	SetDirectory(t.TempDir())
+	defer SetDirectory("")
+
+	assert.True(t, Enabled())
+
+	_, ok = Elevation(50.5, 4.5)
+	assert.False(t, ok)
+}
+
+type tiffByteOrder interface {
+	binary.ByteOrder
+	binary.AppendByteOrder
+}
+
+// buildTestGeoTIFF returns a GeoTIFF file of 4 by 4 pixels of a quarter
+// degree, from 51N 4E, with a single strip; the float32 variant is
+// compressed with the floating point predictor, the int16 variant uses the
+// horizontal predictor
+func buildTestGeoTIFF(order tiffByteOrder, format string) []byte {
+	const size = 4
+
+	var strip []byte
+
+	for y := range size {
+		row := make([]float64, size)
+		for x := range row {
+			row[x] = float64(100 + 10*x - 5*y)
+		}
+
+		if format == "float32" {
+			strip = append(strip, floatPredictorRow(row)...)
+		} else {
+			strip = append(strip, horizontalPredictorRow(order, row)...)
+		}
+	}
+
+	compression, bits, sampleFormat, predictor := uint16(compressionNone), uint16(16), uint16(sampleFormatInt), uint16(predictorHorizontal)
+
+	if format == "float32" {
+		var b bytes.Buffer
+
+		zw := zlib.NewWriter(&b)
+		zw.Write(strip)
+		zw.Close()
+
+		strip = b.Bytes()
+		compression, bits, sampleFormat, predictor = compressionDeflate, 32, sampleFormatFloat, predictorFloatingPoint
+	}
+
+	short := func(v ...uint16) []byte {
+		b := make([]byte, 2*len(v))
+		for i := range v {
+			order.PutUint16(b[2*i:], v[i])
+		}
+
+		return b
+	}
+	long := func(v uint32) []byte { return order.AppendUint32(nil, v) }
+	double := func(v ...float64) []byte {
+		b := make([]byte, 8*len(v))
+		for i := range v {
+			order.PutUint64(b[8*i:], math.Float64bits(v[i]))
+		}
+
+		return b
+	}
+
+	entries := []struct {
+		tag, typ uint16
+		data     []byte
+	}{
+		{tagImageWidth, 3, short(size)},
+		{tagImageLength, 3, short(size)},
+		{tagBitsPerSample, 3, short(bits)},
+		{tagCompression, 3, short(compression)},
+		{tagStripOffsets, 4, long(tiffHeaderSize)},
+		{tagSamplesPerPixel, 3, short(1)},
+		{tagRowsPerStrip, 3, short(size)},
+		{tagStripByteCounts, 4, long(uint32(len(strip)))},
+		{tagPredictor, 3, short(predictor)},
+		{tagSampleFormat, 3, short(sampleFormat)},
+		{tagModelPixelScale, 12, double(0.25, 0.25, 0)},
+		{tagModelTiepoint, 12, double(0, 0, 0, 4, 51, 0)},
+		{tagGeoKeyDirectory, 3, short(1, 1, 0, 2, geoKeyModelType, 0, 1, modelTypeGeographic, geoKeyRasterType, 0, 1, 1)},
+		{tagGDALNoData, 2, []byte("-9999\x00")},
+	}
+
+	sizes := map[uint16]int{2: 1, 3: 2, 4: 4, 12: 8}
+
+	// The strip follows the header, and the values that do not fit in the
+	// entries follow the directory
+	ifd := tiffHeaderSize + len(strip) + len(strip)%2
+	extra := ifd + 2 + len(entries)*tiffEntrySize + 4
+
+	var dir, data []byte
+
+	dir = order.AppendUint16(dir, uint16(len(entries)))
+
+	for _, e := range entries {
+		dir = order.AppendUint16(dir, e.tag)
+		dir = order.AppendUint16(dir, e.typ)
+		dir = order.AppendUint32(dir, uint32(len(e.data)/sizes[e.typ]))
+
+		if len(e.data) <= tiffMaxInlineDataSize {
+			dir = append(dir, append(e.data, make([]byte, tiffMaxInlineDataSize-len(e.data))...)...)
+			continue
+		}
+
+		dir = order.AppendUint32(dir, uint32(extra+len(data)))
+		data = append(data, e.data...)
+	}
+
+	dir = order.AppendUint32(dir, 0)
+
+	file := []byte("II*\x00")
+	if order == binary.BigEndian {
+		file = []byte("MM\x00*")
+	}
+
+	file = order.AppendUint32(file, uint32(ifd))
+	file = append(file, strip...)
+	file = append(file, make([]byte, ifd-len(file))...)
+	file = append(file, dir...)
+
+	return append(file, data...)
+}
+
+// floatPredictorRow encodes a row of float32 samples with the floating point
+// predictor: the bytes are grouped per byte of the samples, most significant
+// bytes first, and differenced
+func floatPredictorRow(row []float64) []byte {
+	out := make([]byte, 4*len(row))
+
+	for i, v := range row {
+		be := binary.BigEndian.AppendUint32(nil, math.Float32bits(float32(v)))
+		for b := range be {
+			out[b*len(row)+i] = be[b]
+		}
+	}
+
+	for i := len(out) - 1; i > 0; i-- {
+		out[i] -= out[i-1]
+	}
+
+	return out
+}
+
+// horizontalPredictorRow encodes a row of int16 samples with the horizontal
+// predictor: every sample is the difference with the previous sample
+func horizontalPredictorRow(order tiffByteOrder, row []float64) []byte {
+	var out []byte
+
+	prev := int16(0)
+
+	for _, v := range row {
+		out = order.AppendUint16(out, uint16(int16(v)-prev))
+		prev = int16(v)
+	}
+
+	return out
+}
@@ -0,0 +1,506 @@
+package dem
+
+import (
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// The TIFF tags that are used
+const (
+	tagImageWidth      = 256
+	tagImageLength     = 257
+	tagBitsPerSample   = 258
+	tagCompression     = 259
+	tagStripOffsets    = 273
+	tagSamplesPerPixel = 277
+	tagRowsPerStrip    = 278
+	tagStripByteCounts = 279
+	tagPredictor       = 317
+	tagTileWidth       = 322
+	tagTileLength      = 323
+	tagTileOffsets     = 324
+	tagTileByteCounts  = 325
+	tagSampleFormat    = 339
+	tagModelPixelScale = 33550
+	tagModelTiepoint   = 33922
+	tagGeoKeyDirectory = 34735
+	tagGDALNoData      = 42113
+)
+
+// The values of the TIFF and GeoTIFF tags that are supported
+const (
+	compressionNone        = 1
+	compressionDeflate     = 8
+	compressionDeflateOld  = 32946
+	predictorNone          = 1
+	predictorHorizontal    = 2
+	predictorFloatingPoint = 3
+	sampleFormatUint       = 1
+	sampleFormatInt        = 2
+	sampleFormatFloat      = 3
+
+	geoKeyModelType       = 1024
+	geoKeyRasterType      = 1025
+	modelTypeGeographic   = 2
+	rasterPixelIsPoint    = 2
+	tiffHeaderSize        = 8
+	tiffEntrySize         = 12
+	tiffMaxInlineDataSize = 4
+	tiffMaxCount          = 1 << 24
+)
+
+var errUnsupportedGeoTIFF = errors.New("dem: unsupported GeoTIFF")
+
+// geoTIFF is a GeoTIFF file with a single band of elevations, in geographic
+// coordinates; only its structure is read when it is opened
+type geoTIFF struct {
+	path  string
+	order binary.ByteOrder
+	grid  grid // The position of the samples, without values
+
+	bitsPerSample int
+	sampleFormat  int
+	compression   int
+	predictor     int
+
+	chunkWidth  int      // The width of a strip or tile
+	chunkHeight int      // The number of rows of a strip or tile
+	tiled       bool     // Whether the samples are stored in tiles instead of strips
+	offsets     []uint64 // The positions of the strips or tiles in the file
+	byteCounts  []uint64 // The sizes of the strips or tiles
+}
+
+// tiffField is the value of a TIFF tag
+type tiffField struct {
+	numbers []float64
+	text    string
+}
+
+func (f tiffField) first(def int) int {
+	if len(f.numbers) == 0 {
+		return def
+	}
+
+	return int(f.numbers[0])
+}
+
+func (f tiffField) uints() []uint64 {
+	r := make([]uint64, len(f.numbers))
+	for i, n := range f.numbers {
+		r[i] = uint64(n)
+	}
+
+	return r
+}
+
+// openGeoTIFF reads the structure and position of a GeoTIFF file
+func openGeoTIFF(path string) (*geoTIFF, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	order, fields, err := readTIFFFields(f)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errUnsupportedGeoTIFF, path, err)
+	}
+
+	t := &geoTIFF{
+		path:          path,
+		order:         order,
+		bitsPerSample: fields[tagBitsPerSample].first(1),
+		sampleFormat:  fields[tagSampleFormat].first(sampleFormatUint),
+		compression:   fields[tagCompression].first(compressionNone),
+		predictor:     fields[tagPredictor].first(predictorNone),
+	}
+
+	if err := t.setLayout(fields); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errUnsupportedGeoTIFF, path, err)
+	}
+
+	if err := t.setPosition(fields); err != nil {
+		return nil, fmt.Errorf("%w: %s: %w", errUnsupportedGeoTIFF, path, err)
+	}
+
+	return t, nil
+}
+
+// setLayout checks the format of the samples and finds the strips or tiles
+func (t *geoTIFF) setLayout(fields map[uint16]tiffField) error {
+	t.grid.width = fields[tagImageWidth].first(0)
+	t.grid.height = fields[tagImageLength].first(0)
+
+	if t.grid.width < 1 || t.grid.height < 1 {
+		return errors.New("no image size")
+	}
+
+	if fields[tagSamplesPerPixel].first(1) != 1 {
+		return errors.New("more than one band")
+	}
+
+	switch {
+	case t.sampleFormat == sampleFormatFloat && (t.bitsPerSample == 32 || t.bitsPerSample == 64):
+	case t.sampleFormat != sampleFormatFloat && (t.bitsPerSample == 16 || t.bitsPerSample == 32):
+	default:
+		return fmt.Errorf("sample format %d with %d bits", t.sampleFormat, t.bitsPerSample)
+	}
+
+	switch t.compression {
+	case compressionNone, compressionDeflate, compressionDeflateOld:
+	default:
+		return fmt.Errorf("compression %d", t.compression)
+	}
+
+	switch {
+	case t.predictor == predictorNone:
+	case t.predictor == predictorHorizontal && t.sampleFormat != sampleFormatFloat:
+	case t.predictor == predictorFloatingPoint && t.sampleFormat == sampleFormatFloat:
+	default:
+		return fmt.Errorf("predictor %d", t.predictor)
+	}
+
+	if _, ok := fields[tagTileOffsets]; ok {
+		t.tiled = true
+		t.chunkWidth = fields[tagTileWidth].first(0)
+		t.chunkHeight = fields[tagTileLength].first(0)
+		t.offsets = fields[tagTileOffsets].uints()
+		t.byteCounts = fields[tagTileByteCounts].uints()
+	} else {
+		t.chunkWidth = t.grid.width
+		t.chunkHeight = min(fields[tagRowsPerStrip].first(t.grid.height), t.grid.height)
+		t.offsets = fields[tagStripOffsets].uints()
+		t.byteCounts = fields[tagStripByteCounts].uints()
+	}
+
+	if t.chunkWidth < 1 || t.chunkHeight < 1 {
+		return errors.New("no strip or tile size")
+	}
+
+	chunks := t.chunksAcross() * ((t.grid.height + t.chunkHeight - 1) / t.chunkHeight)
+	if len(t.offsets) != chunks || len(t.byteCounts) != chunks {
+		return errors.New("missing strips or tiles")
+	}
+
+	return nil
+}
+
+// setPosition sets the coordinates of the samples from the GeoTIFF tags
+func (t *geoTIFF) setPosition(fields map[uint16]tiffField) error {
+	scale := fields[tagModelPixelScale].numbers
+	tiepoint := fields[tagModelTiepoint].numbers
+
+	if len(scale) < 2 || len(tiepoint) < 5 || scale[0] <= 0 || scale[1] <= 0 {
+		return errors.New("no pixel scale or tiepoint")
+	}
+
+	modelType, rasterType := 0, 0
+
+	keys := fields[tagGeoKeyDirectory].numbers
+	for i := 4; i+3 < len(keys); i += 4 {
+		// Only keys with their value in the directory itself are needed
+		if keys[i+1] != 0 {
+			continue
+		}
+
+		switch int(keys[i]) {
+		case geoKeyModelType:
+			modelType = int(keys[i+3])
+		case geoKeyRasterType:
+			rasterType = int(keys[i+3])
+		}
+	}
+
+	if modelType != 0 && modelType != modelTypeGeographic {
+		return errors.New("not in geographic coordinates")
+	}
+
+	g := &t.grid
+	g.dLng, g.dLat = scale[0], scale[1]
+	g.west = tiepoint[3] - tiepoint[0]*g.dLng
+	g.north = tiepoint[4] + tiepoint[1]*g.dLat
+
+	if rasterType != rasterPixelIsPoint {
+		// The tiepoint is the corner of the first pixel, not its center
+		g.west += g.dLng / 2
+		g.north -= g.dLat / 2
+	}
+
+	if g.west < -181 || g.north > 91 || g.west+float64(g.width)*g.dLng > 181 || g.north-float64(g.height)*g.dLat < -91 {
+		return errors.New("not in geographic coordinates")
+	}
+
+	if nd, ok := fields[tagGDALNoData]; ok {
+		v, err := strconv.ParseFloat(strings.TrimSpace(strings.Trim(nd.text, "\x00")), 64)
+		if err == nil {
+			g.noData, g.hasNoData = float32(v), true
+		}
+	}
+
+	return nil
+}
+
+// covers returns whether the coordinates are within the file
+func (t *geoTIFF) covers(lat, lng float64) bool {
+	return t.grid.contains(lat, lng)
+}
+
+func (t *geoTIFF) chunksAcross() int {
+	if !t.tiled {
+		return 1
+	}
+
+	return (t.grid.width + t.chunkWidth - 1) / t.chunkWidth
+}
+
+// read reads all samples of the file
+func (t *geoTIFF) read() (*grid, error) {
+	f, err := os.Open(t.path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	g := t.grid
+	g.values = make([]float32, g.width*g.height)
+
+	across := t.chunksAcross()
+
+	for i := range t.offsets {
+		x0 := (i % across) * t.chunkWidth
+		y0 := (i / across) * t.chunkHeight
+
+		rows := t.chunkHeight
+		if !t.tiled {
+			// The last strip only has the remaining rows
+			rows = min(rows, g.height-y0)
+		}
+
+		samples, err := t.readChunk(f, i, rows)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s: %w", errUnsupportedGeoTIFF, t.path, err)
+		}
+
+		for y := 0; y < rows && y0+y < g.height; y++ {
+			n := min(t.chunkWidth, g.width-x0)
+			copy(g.values[(y0+y)*g.width+x0:], samples[y*t.chunkWidth:y*t.chunkWidth+n])
+		}
+	}
+
+	return &g, nil
+}
+
+// readChunk reads and decodes the samples of a strip or tile
+func (t *geoTIFF) readChunk(r io.ReaderAt, i, rows int) ([]float32, error) {
+	raw := make([]byte, t.byteCounts[i])
+	if _, err := r.ReadAt(raw, int64(t.offsets[i])); err != nil {
+		return nil, err
+	}
+
+	if t.compression != compressionNone {
+		zr, err := zlib.NewReader(bytes.NewReader(raw))
+		if err != nil {
+			return nil, err
+		}
+
+		raw, err = io.ReadAll(zr)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	size := t.bitsPerSample / 8
+	rowSize := t.chunkWidth * size
+
+	if len(raw) < rows*rowSize {
+		return nil, errors.New("strip or tile too short")
+	}
+
+	samples := make([]float32, rows*t.chunkWidth)
+
+	for y := range rows {
+		row := raw[y*rowSize : (y+1)*rowSize]
+		t.decodeRow(row, samples[y*t.chunkWidth:(y+1)*t.chunkWidth])
+	}
+
+	return samples, nil
+}
+
+// decodeRow converts a row of samples, undoing the predictor
+func (t *geoTIFF) decodeRow(row []byte, samples []float32) {
+	size := t.bitsPerSample / 8
+	order := t.order
+
+	if t.predictor == predictorFloatingPoint {
+		// The bytes are differenced, and grouped per byte of the samples,
+		// most significant bytes first
+		for i := 1; i < len(row); i++ {
+			row[i] += row[i-1]
+		}
+
+		shuffled := make([]byte, len(row))
+		for i := range samples {
+			for b := range size {
+				shuffled[i*size+b] = row[b*len(samples)+i]
+			}
+		}
+
+		row, order = shuffled, binary.BigEndian
+	}
+
+	var prev uint64
+
+	mask := uint64(math.MaxUint64)
+	if size < 8 {
+		mask = 1<<(8*size) - 1
+	}
+
+	for i := range samples {
+		var bits uint64
+
+		switch size {
+		case 2:
+			bits = uint64(order.Uint16(row[i*2:]))
+		case 4:
+			bits = uint64(order.Uint32(row[i*4:]))
+		default:
+			bits = order.Uint64(row[i*8:])
+		}
+
+		if t.predictor == predictorHorizontal {
+			bits = (bits + prev) & mask
+			prev = bits
+		}
+
+		samples[i] = t.sampleValue(bits)
+	}
+}
+
+// sampleValue interprets the bits of a sample
+func (t *geoTIFF) sampleValue(bits uint64) float32 {
+	switch {
+	case t.sampleFormat == sampleFormatFloat && t.bitsPerSample == 64:
+		return float32(math.Float64frombits(bits))
+	case t.sampleFormat == sampleFormatFloat:
+		return math.Float32frombits(uint32(bits))
+	case t.sampleFormat == sampleFormatInt && t.bitsPerSample == 16:
+		return float32(int16(uint16(bits)))
+	case t.sampleFormat == sampleFormatInt:
+		return float32(int32(uint32(bits)))
+	case t.bitsPerSample == 16:
+		return float32(uint16(bits))
+	default:
+		return float32(uint32(bits))
+	}
+}
+
+// readTIFFFields reads the byte order and the tags of the first image of a
+// TIFF file
+func readTIFFFields(r io.ReaderAt) (binary.ByteOrder, map[uint16]tiffField, error) {
+	header := make([]byte, tiffHeaderSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, nil, err
+	}
+
+	var order binary.ByteOrder
+
+	switch string(header[:4]) {
+	case "II*\x00":
+		order = binary.LittleEndian
+	case "MM\x00*":
+		order = binary.BigEndian
+	default:
+		return nil, nil, errors.New("not a TIFF file")
+	}
+
+	ifd := int64(order.Uint32(header[4:]))
+
+	countBytes := make([]byte, 2)
+	if _, err := r.ReadAt(countBytes, ifd); err != nil {
+		return nil, nil, err
+	}
+
+	entries := make([]byte, int(order.Uint16(countBytes))*tiffEntrySize)
+	if _, err := r.ReadAt(entries, ifd+2); err != nil {
+		return nil, nil, err
+	}
+
+	fields := map[uint16]tiffField{}
+
+	for e := 0; e < len(entries); e += tiffEntrySize {
+		entry := entries[e : e+tiffEntrySize]
+
+		field, err := readTIFFField(r, order, order.Uint16(entry[2:]), order.Uint32(entry[4:]), entry[8:])
+		if err != nil {
+			return nil, nil, err
+		}
+
+		fields[order.Uint16(entry)] = field
+	}
+
+	return order, fields, nil
+}
+
+// readTIFFField reads the value of a tag; the value is stored in the entry
+// itself when it fits, and at the offset in the entry otherwise
+func readTIFFField(r io.ReaderAt, order binary.ByteOrder, typ uint16, count uint32, value []byte) (tiffField, error) {
+	size := map[uint16]int{1: 1, 2: 1, 3: 2, 4: 4, 6: 1, 7: 1, 8: 2, 9: 4, 11: 4, 12: 8, 16: 8}[typ]
+	if size == 0 {
+		// Rationals and unknown types are not needed
+		return tiffField{}, nil
+	}
+
+	if count > tiffMaxCount {
+		return tiffField{}, errors.New("tag value too large")
+	}
+
+	data := value[:min(len(value), int(count)*size)]
+
+	if int(count)*size > tiffMaxInlineDataSize {
+		data = make([]byte, int(count)*size)
+		if _, err := r.ReadAt(data, int64(order.Uint32(value))); err != nil {
+			return tiffField{}, err
+		}
+	}
+
+	if typ == 2 {
+		return tiffField{text: string(data)}, nil
+	}
+
+	numbers := make([]float64, count)
+
+	for i := range numbers {
+		d := data[i*size:]
+
+		switch typ {
+		case 1, 7:
+			numbers[i] = float64(d[0])
+		case 6:
+			numbers[i] = float64(int8(d[0]))
+		case 3:
+			numbers[i] = float64(order.Uint16(d))
+		case 8:
+			numbers[i] = float64(int16(order.Uint16(d)))
+		case 4:
+			numbers[i] = float64(order.Uint32(d))
+		case 9:
+			numbers[i] = float64(int32(order.Uint32(d)))
+		case 11:
+			numbers[i] = float64(math.Float32frombits(order.Uint32(d)))
+		case 12:
+			numbers[i] = math.Float64frombits(order.Uint64(d))
+		case 16:
+			numbers[i] = float64(order.Uint64(d))
+		}
+	}
+
+	return tiffField{numbers: numbers}, nil
+}
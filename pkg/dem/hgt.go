@@ -0,0 +1,102 @@
+package dem
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+)
+
+// hgtVoid is the value of missing samples in HGT files
+const hgtVoid = -32768
+
+// hgtName returns the name of the HGT tile with the coordinates, e.g.
+// N50E004 for 50.5, 4.5
+func hgtName(lat, lng float64) string {
+	latDeg, lngDeg := int(math.Floor(lat)), int(math.Floor(lng))
+
+	ns, ew := 'N', 'E'
+
+	if latDeg < 0 {
+		ns, latDeg = 'S', -latDeg
+	}
+
+	if lngDeg < 0 {
+		ew, lngDeg = 'W', -lngDeg
+	}
+
+	return fmt.Sprintf("%c%02d%c%03d", ns, latDeg, ew, lngDeg)
+}
+
+// parseHGTName returns the latitude and longitude of the south west corner
+// of the HGT tile with the (upper case) file name
+func parseHGTName(name string) (int, int, bool) {
+	if len(name) < 7 {
+		return 0, 0, false
+	}
+
+	lat, err := strconv.Atoi(name[1:3])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	lng, err := strconv.Atoi(name[4:7])
+	if err != nil {
+		return 0, 0, false
+	}
+
+	switch name[0] {
+	case 'N':
+	case 'S':
+		lat = -lat
+	default:
+		return 0, 0, false
+	}
+
+	switch name[3] {
+	case 'E':
+	case 'W':
+		lng = -lng
+	default:
+		return 0, 0, false
+	}
+
+	return lat, lng, true
+}
+
+// readHGT reads an HGT file: a square of big endian 16-bit samples, in rows
+// from north to south, with samples on both edges of the tile
+func readHGT(path string) (*grid, error) {
+	lat, lng, ok := parseHGTName(upperBase(path))
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnsupportedFile, path)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	size := int(math.Sqrt(float64(len(data) / 2)))
+	if size < 2 || size*size*2 != len(data) {
+		return nil, fmt.Errorf("%w: %s is not a square tile", ErrUnsupportedFile, path)
+	}
+
+	values := make([]float32, size*size)
+	for i := range values {
+		values[i] = float32(int16(binary.BigEndian.Uint16(data[2*i:])))
+	}
+
+	return &grid{
+		west:      float64(lng),
+		north:     float64(lat + 1),
+		dLng:      1 / float64(size-1),
+		dLat:      1 / float64(size-1),
+		width:     size,
+		height:    size,
+		values:    values,
+		noData:    hgtVoid,
+		hasNoData: true,
+	}, nil
+}
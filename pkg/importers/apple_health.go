@@ -0,0 +1,648 @@
+package importers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/converters"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/spf13/cast"
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+const (
+	appleHealthExport     = "export.xml"
+	appleHealthTimeLayout = "2006-01-02 15:04:05 -0700"
+
+	// appleHeartRateMaxGap is how far a heart rate sample can be from a
+	// point, to be attached to that point
+	appleHeartRateMaxGap = 30 * time.Second
+)
+
+const (
+	appleRecordBodyMass         = "HKQuantityTypeIdentifierBodyMass"
+	appleRecordHeight           = "HKQuantityTypeIdentifierHeight"
+	appleRecordStepCount        = "HKQuantityTypeIdentifierStepCount"
+	appleRecordRestingHeartRate = "HKQuantityTypeIdentifierRestingHeartRate"
+	appleRecordHeartRate        = "HKQuantityTypeIdentifierHeartRate"
+)
+
+type (
+	appleWorkout struct {
+		ActivityType      string                   `xml:"workoutActivityType,attr"`
+		Duration          float64                  `xml:"duration,attr"`
+		DurationUnit      string                   `xml:"durationUnit,attr"`
+		TotalDistance     float64                  `xml:"totalDistance,attr"`
+		TotalDistanceUnit string                   `xml:"totalDistanceUnit,attr"`
+		SourceName        string                   `xml:"sourceName,attr"`
+		StartDate         string                   `xml:"startDate,attr"`
+		EndDate           string                   `xml:"endDate,attr"`
+		Statistics        []appleWorkoutStatistics `xml:"WorkoutStatistics"`
+		Routes            []appleWorkoutRoute      `xml:"WorkoutRoute"`
+
+		start      time.Time
+		end        time.Time
+		heartRates []appleSample
+	}
+
+	appleWorkoutStatistics struct {
+		Type    string  `xml:"type,attr"`
+		Sum     float64 `xml:"sum,attr"`
+		Average float64 `xml:"average,attr"`
+		Minimum float64 `xml:"minimum,attr"`
+		Maximum float64 `xml:"maximum,attr"`
+		Unit    string  `xml:"unit,attr"`
+	}
+
+	appleWorkoutRoute struct {
+		FileReference struct {
+			Path string `xml:"path,attr"`
+		} `xml:"FileReference"`
+	}
+
+	appleSample struct {
+		time  time.Time
+		value float64
+	}
+
+	// appleDay holds the measurements of a single day; steps are counted per
+	// source, since the iPhone and the watch both count the same steps
+	appleDay struct {
+		weight, height, restingHeartRate appleSample
+		steps                            map[string]float64
+	}
+)
+
+// importAppleHealthArchive imports an Apple Health export: export.xml holds
+// all records and workouts, the routes of the workouts are stored as GPX
+// files in the workout-routes/ folder. The export is often several
+// gigabytes, so export.xml is streamed twice instead of kept in memory: once
+// for the measurements and workouts, and once for the heart rate samples
+// recorded during those workouts.
+func importAppleHealthArchive(ctx context.Context, archivePath string, sink ArchiveSink) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer zr.Close()
+
+	var export *zip.File
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[path.Clean(f.Name)] = f
+
+		if path.Base(f.Name) == appleHealthExport && (export == nil || len(f.Name) < len(export.Name)) {
+			export = f
+		}
+	}
+
+	if export == nil {
+		return fmt.Errorf("%w: %s not found", ErrInvalidArchive, appleHealthExport)
+	}
+
+	days := map[time.Time]*appleDay{}
+
+	var workouts []*appleWorkout
+
+	err = walkAppleHealthExport(ctx, export, func(d *xml.Decoder, se *xml.StartElement) error {
+		switch se.Name.Local {
+		case "Record":
+			addAppleRecord(days, se)
+		case "Workout":
+			w := &appleWorkout{}
+			if err := d.DecodeElement(w, se); err != nil {
+				return err
+			}
+
+			if w.parseDates() {
+				workouts = append(workouts, w)
+			}
+
+			return nil
+		}
+
+		return d.Skip()
+	})
+	if err != nil {
+		return err
+	}
+
+	addAppleMeasurements(export.Name, days, sink)
+
+	if len(workouts) == 0 {
+		return nil
+	}
+
+	sort.Slice(workouts, func(i, j int) bool { return workouts[i].start.Before(workouts[j].start) })
+
+	err = walkAppleHealthExport(ctx, export, func(d *xml.Decoder, se *xml.StartElement) error {
+		if se.Name.Local == "Record" && appleAttr(se, "type") == appleRecordHeartRate {
+			addAppleHeartRate(workouts, se)
+		}
+
+		return d.Skip()
+	})
+	if err != nil {
+		return err
+	}
+
+	root := path.Dir(export.Name)
+
+	for _, w := range workouts {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		w.sortHeartRates()
+
+		entry := fmt.Sprintf("%s: %s %s", export.Name, w.ActivityType, w.StartDate)
+
+		aw, err := w.archiveWorkout(root, files)
+		if err != nil {
+			sink.Fail(entry, err)
+			continue
+		}
+
+		if aw.Entry == "" {
+			aw.Entry = entry
+		}
+
+		sink.AddWorkout(aw)
+	}
+
+	return nil
+}
+
+// walkAppleHealthExport streams export.xml, and calls fn for every child of
+// the root element; fn should consume the whole element
+func walkAppleHealthExport(ctx context.Context, export *zip.File, fn func(d *xml.Decoder, se *xml.StartElement) error) error {
+	rc, err := export.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	d := xml.NewDecoder(rc)
+	d.Strict = false
+	depth := 0
+
+	for {
+		t, err := d.Token()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+
+		if err != nil {
+			return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+		}
+
+		switch se := t.(type) {
+		case xml.StartElement:
+			if depth == 0 {
+				depth++
+				continue
+			}
+
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+
+			if err := fn(d, &se); err != nil {
+				return err
+			}
+		case xml.EndElement:
+			depth--
+		}
+	}
+}
+
+func appleAttr(se *xml.StartElement, name string) string {
+	for _, a := range se.Attr {
+		if a.Name.Local == name {
+			return a.Value
+		}
+	}
+
+	return ""
+}
+
+func parseAppleTime(s string) (time.Time, bool) {
+	t, err := time.Parse(appleHealthTimeLayout, s)
+	if err != nil {
+		return time.Time{}, false
+	}
+
+	return t, true
+}
+
+// appleDate returns the calendar day of the timestamp, in the timezone it
+// was recorded in
+func appleDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+func addAppleRecord(days map[time.Time]*appleDay, se *xml.StartElement) {
+	recordType := appleAttr(se, "type")
+
+	switch recordType {
+	case appleRecordBodyMass, appleRecordHeight, appleRecordStepCount, appleRecordRestingHeartRate:
+	default:
+		return
+	}
+
+	t, ok := parseAppleTime(appleAttr(se, "startDate"))
+	if !ok {
+		return
+	}
+
+	v, err := cast.ToFloat64E(appleAttr(se, "value"))
+	if err != nil || v <= 0 {
+		return
+	}
+
+	date := appleDate(t)
+
+	day, ok := days[date]
+	if !ok {
+		day = &appleDay{steps: map[string]float64{}}
+		days[date] = day
+	}
+
+	sample := appleSample{time: t}
+	unit := appleAttr(se, "unit")
+
+	switch recordType {
+	case appleRecordBodyMass:
+		sample.value = appleWeight(v, unit)
+		day.weight = latestAppleSample(day.weight, sample)
+	case appleRecordHeight:
+		sample.value = appleHeight(v, unit)
+		day.height = latestAppleSample(day.height, sample)
+	case appleRecordRestingHeartRate:
+		sample.value = v
+		day.restingHeartRate = latestAppleSample(day.restingHeartRate, sample)
+	case appleRecordStepCount:
+		day.steps[appleAttr(se, "sourceName")] += v
+	}
+}
+
+func latestAppleSample(current, sample appleSample) appleSample {
+	if sample.value <= 0 || sample.time.Before(current.time) {
+		return current
+	}
+
+	return sample
+}
+
+func addAppleMeasurements(entry string, days map[time.Time]*appleDay, sink ArchiveSink) {
+	dates := make([]time.Time, 0, len(days))
+	for d := range days {
+		dates = append(dates, d)
+	}
+
+	slices.SortFunc(dates, func(a, b time.Time) int { return a.Compare(b) })
+
+	for _, date := range dates {
+		day := days[date]
+
+		var steps float64
+		for _, s := range day.steps {
+			steps = max(steps, s)
+		}
+
+		sink.AddMeasurement(entry, date, func(m *model.Measurement) {
+			if day.weight.value > 0 {
+				m.Weight = day.weight.value
+			}
+
+			if day.height.value > 0 {
+				m.Height = day.height.value
+			}
+
+			if day.restingHeartRate.value > 0 {
+				m.RestingHeartRate = day.restingHeartRate.value
+			}
+
+			if steps > 0 {
+				m.Steps = steps
+			}
+		})
+	}
+}
+
+// addAppleHeartRate adds the heart rate sample to the workout it was recorded
+// during, if any; the workouts are sorted by their start
+func addAppleHeartRate(workouts []*appleWorkout, se *xml.StartElement) {
+	t, ok := parseAppleTime(appleAttr(se, "startDate"))
+	if !ok {
+		return
+	}
+
+	i := sort.Search(len(workouts), func(i int) bool { return workouts[i].start.After(t) }) - 1
+	if i < 0 || t.After(workouts[i].end) {
+		return
+	}
+
+	v, err := cast.ToFloat64E(appleAttr(se, "value"))
+	if err != nil || v <= 0 {
+		return
+	}
+
+	workouts[i].heartRates = append(workouts[i].heartRates, appleSample{time: t, value: v})
+}
+
+func (w *appleWorkout) parseDates() bool {
+	var ok bool
+
+	if w.start, ok = parseAppleTime(w.StartDate); !ok {
+		return false
+	}
+
+	if w.end, ok = parseAppleTime(w.EndDate); !ok {
+		w.end = w.start.Add(w.duration())
+	}
+
+	return true
+}
+
+func (w *appleWorkout) duration() time.Duration {
+	switch w.DurationUnit {
+	case "s", "sec":
+		return time.Duration(w.Duration * float64(time.Second))
+	case "hr", "h":
+		return time.Duration(w.Duration * float64(time.Hour))
+	default:
+		return time.Duration(w.Duration * float64(time.Minute))
+	}
+}
+
+// distance returns the total distance in meters; recent exports only list
+// it in the workout statistics
+func (w *appleWorkout) distance() float64 {
+	if w.TotalDistance > 0 {
+		return appleDistance(w.TotalDistance, w.TotalDistanceUnit)
+	}
+
+	for _, s := range w.Statistics {
+		if strings.HasPrefix(s.Type, "HKQuantityTypeIdentifierDistance") && s.Sum > 0 {
+			return appleDistance(s.Sum, s.Unit)
+		}
+	}
+
+	return 0
+}
+
+func (w *appleWorkout) archiveWorkout(root string, files map[string]*zip.File) (*ArchiveWorkout, error) {
+	aw := &ArchiveWorkout{
+		Type: appleWorkoutType(w.ActivityType),
+	}
+
+	for _, r := range w.Routes {
+		f, ok := files[path.Join(root, r.FileReference.Path)]
+		if !ok {
+			continue
+		}
+
+		workouts, err := w.parseRoute(f)
+		if err != nil {
+			return nil, err
+		}
+
+		aw.Entry = f.Name
+		aw.Workouts = workouts
+
+		return aw, nil
+	}
+
+	// Indoor workouts have no route
+	aw.Workouts = []*model.Workout{w.workoutWithoutRoute()}
+
+	return aw, nil
+}
+
+// parseRoute adds the heart rate samples to the route, so they are part of
+// the stored file and survive reparsing the workout
+func (w *appleWorkout) parseRoute(f *zip.File) ([]*model.Workout, error) {
+	content, err := readZipFile(f)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(w.heartRates) > 0 {
+		g, err := converters.ParseGPX(content)
+		if err != nil {
+			return nil, err
+		}
+
+		w.addHeartRates(g)
+
+		if content, err = g.ToXml(gpx.ToXmlParams{Version: "1.1", Indent: true}); err != nil {
+			return nil, err
+		}
+	}
+
+	return converters.ParseCollection(path.Base(f.Name), content)
+}
+
+func (w *appleWorkout) addHeartRates(g *gpx.GPX) {
+	for ti := range g.Tracks {
+		for si := range g.Tracks[ti].Segments {
+			points := g.Tracks[ti].Segments[si].Points
+
+			for pi := range points {
+				hr, ok := w.heartRateAt(points[pi].Timestamp)
+				if !ok {
+					continue
+				}
+
+				points[pi].Extensions.Nodes = append(points[pi].Extensions.Nodes, gpx.ExtensionNode{
+					XMLName: xml.Name{Local: "heart-rate"}, Data: cast.ToString(hr),
+				})
+			}
+		}
+	}
+}
+
+// sortHeartRates sorts the heart rate samples by time; the export is not
+// chronological across sources (e.g. watch and phone)
+func (w *appleWorkout) sortHeartRates() {
+	sort.SliceStable(w.heartRates, func(i, j int) bool {
+		return w.heartRates[i].time.Before(w.heartRates[j].time)
+	})
+}
+
+// heartRateAt returns the heart rate sample closest to the timestamp; the
+// samples must be sorted by time
+func (w *appleWorkout) heartRateAt(t time.Time) (float64, bool) {
+	if t.IsZero() {
+		return 0, false
+	}
+
+	samples := w.heartRates
+	i := sort.Search(len(samples), func(i int) bool { return !samples[i].time.Before(t) })
+
+	var (
+		best  appleSample
+		found bool
+	)
+
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= len(samples) {
+			continue
+		}
+
+		if !found || absDuration(samples[j].time.Sub(t)) < absDuration(best.time.Sub(t)) {
+			best, found = samples[j], true
+		}
+	}
+
+	if !found || absDuration(best.time.Sub(t)) > appleHeartRateMaxGap {
+		return 0, false
+	}
+
+	return best.value, true
+}
+
+func (w *appleWorkout) workoutWithoutRoute() *model.Workout {
+	data := &model.MapData{
+		Creator: w.SourceName,
+		WorkoutData: model.WorkoutData{
+			Name:          formatAppleWorkoutName(w.ActivityType, w.start),
+			Type:          w.ActivityType,
+			Start:         w.start,
+			Stop:          w.end,
+			TotalDistance: w.distance(),
+			TotalDuration: w.end.Sub(w.start),
+		},
+	}
+
+	if d := w.duration(); d > 0 {
+		data.TotalDuration = d
+	}
+
+	if len(w.heartRates) > 0 {
+		var sum float64
+
+		data.MinHeartRate = w.heartRates[0].value
+
+		for _, s := range w.heartRates {
+			sum += s.value
+			data.MinHeartRate = min(data.MinHeartRate, s.value)
+			data.MaxHeartRate = max(data.MaxHeartRate, s.value)
+		}
+
+		data.AverageHeartRate = sum / float64(len(w.heartRates))
+	}
+
+	return &model.Workout{
+		Name: data.Name,
+		Date: w.start,
+		Data: data,
+	}
+}
+
+func formatAppleWorkoutName(activityType string, at time.Time) string {
+	name := strings.TrimPrefix(activityType, "HKWorkoutActivityType")
+	if name == "" {
+		name = "Workout"
+	}
+
+	return name + " - " + at.Format(time.DateTime)
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+
+	return d
+}
+
+// appleWeight converts the weight to kilograms
+func appleWeight(v float64, unit string) float64 {
+	switch unit {
+	case "lb":
+		return v * 0.45359237
+	case "g":
+		return v / 1000
+	case "st":
+		return v * 6.35029318
+	default:
+		return v
+	}
+}
+
+// appleHeight converts the height to centimeter
+func appleHeight(v float64, unit string) float64 {
+	switch unit {
+	case "m":
+		return v * 100
+	case "in":
+		return v * 2.54
+	case "ft":
+		return v * 30.48
+	default:
+		return v
+	}
+}
+
+// appleDistance converts the distance to meters
+func appleDistance(v float64, unit string) float64 {
+	switch unit {
+	case "km":
+		return v * 1000
+	case "mi":
+		return v * 1609.344
+	case "yd":
+		return v * 0.9144
+	case "ft":
+		return v * 0.3048
+	default:
+		return v
+	}
+}
+
+// appleWorkoutType maps HealthKit's workout activity types (e.g.
+// "HKWorkoutActivityTypeRunning") to a workout type
+func appleWorkoutType(t string) model.WorkoutType {
+	switch strings.TrimPrefix(t, "HKWorkoutActivityType") {
+	case "Running":
+		return model.WorkoutTypeRunning
+	case "Cycling", "HandCycling":
+		return model.WorkoutTypeCycling
+	case "Walking":
+		return model.WorkoutTypeWalking
+	case "Hiking":
+		return model.WorkoutTypeHiking
+	case "Swimming":
+		return model.WorkoutTypeSwimming
+	case "DownhillSkiing", "CrossCountrySkiing":
+		return model.WorkoutTypeSkiing
+	case "Snowboarding":
+		return model.WorkoutTypeSnowboarding
+	case "SkatingSports":
+		return model.WorkoutTypeInlineSkating
+	case "PaddleSports":
+		return model.WorkoutTypeKayaking
+	case "Rowing":
+		return model.WorkoutTypeRowing
+	case "Golf":
+		return model.WorkoutTypeGolfing
+	case "TraditionalStrengthTraining", "FunctionalStrengthTraining":
+		return model.WorkoutTypeWeightLifting
+	case "EquestrianSports":
+		return model.WorkoutTypeHorseRiding
+	default:
+		return model.WorkoutTypeAutoDetect
+	}
+}
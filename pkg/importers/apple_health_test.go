@@ -0,0 +1,90 @@
+package importers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testAppleHealthExport = `<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE HealthData [
+<!ELEMENT HealthData (ExportDate,Me,(Record|Workout)*)>
+]>
+<HealthData locale="en_US">
+ <ExportDate value="2024-05-02 10:00:00 +0200"/>
+ <Me HKCharacteristicTypeIdentifierBiologicalSex="HKBiologicalSexNotSet"/>
+ <Record type="HKQuantityTypeIdentifierBodyMass" sourceName="Health" unit="lb" startDate="2024-05-01 07:00:00 +0200" endDate="2024-05-01 07:00:00 +0200" value="160"/>
+ <Record type="HKQuantityTypeIdentifierHeight" sourceName="Health" unit="m" startDate="2024-05-01 07:00:00 +0200" endDate="2024-05-01 07:00:00 +0200" value="1.8"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" unit="count" startDate="2024-05-01 09:00:00 +0200" endDate="2024-05-01 09:10:00 +0200" value="1000"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="iPhone" unit="count" startDate="2024-05-01 12:00:00 +0200" endDate="2024-05-01 12:10:00 +0200" value="500"/>
+ <Record type="HKQuantityTypeIdentifierStepCount" sourceName="Apple Watch" unit="count" startDate="2024-05-01 09:00:00 +0200" endDate="2024-05-01 09:10:00 +0200" value="1200"/>
+ <Record type="HKQuantityTypeIdentifierRestingHeartRate" sourceName="Apple Watch" unit="count/min" startDate="2024-05-01 23:00:00 +0200" endDate="2024-05-01 23:00:00 +0200" value="51"/>
+ <Record type="HKQuantityTypeIdentifierHeartRate" sourceName="Apple Watch" unit="count/min" startDate="2024-05-01 10:01:00 +0200" endDate="2024-05-01 10:01:00 +0200" value="140"/>
+ <Record type="HKQuantityTypeIdentifierHeartRate" sourceName="Apple Watch" unit="count/min" startDate="2024-05-01 10:00:05 +0200" endDate="2024-05-01 10:00:05 +0200" value="120">
+  <MetadataEntry key="HKMetadataKeyHeartRateMotionContext" value="0"/>
+ </Record>
+ <Record type="HKQuantityTypeIdentifierHeartRate" sourceName="Apple Watch" unit="count/min" startDate="2024-05-01 18:10:00 +0200" endDate="2024-05-01 18:10:00 +0200" value="100"/>
+ <Record type="HKQuantityTypeIdentifierHeartRate" sourceName="Apple Watch" unit="count/min" startDate="2024-05-01 20:00:00 +0200" endDate="2024-05-01 20:00:00 +0200" value="60"/>
+ <Workout workoutActivityType="HKWorkoutActivityTypeCycling" duration="2" durationUnit="min" sourceName="Apple Watch" startDate="2024-05-01 10:00:00 +0200" endDate="2024-05-01 10:02:00 +0200">
+  <MetadataEntry key="HKIndoorWorkout" value="0"/>
+  <WorkoutStatistics type="HKQuantityTypeIdentifierDistanceCycling" startDate="2024-05-01 10:00:00 +0200" endDate="2024-05-01 10:02:00 +0200" sum="0.26" unit="km"/>
+  <WorkoutRoute sourceName="Apple Watch">
+   <FileReference path="/workout-routes/route_2024-05-01_10.00am.gpx"/>
+  </WorkoutRoute>
+ </Workout>
+ <Workout workoutActivityType="HKWorkoutActivityTypeTraditionalStrengthTraining" duration="30" durationUnit="min" sourceName="Apple Watch" startDate="2024-05-01 18:00:00 +0200" endDate="2024-05-01 18:30:00 +0200"/>
+</HealthData>
+`
+
+func TestImportArchive_AppleHealth(t *testing.T) {
+	archivePath := writeTestArchive(t, map[string][]byte{
+		"apple_health_export/export.xml":                                  []byte(testAppleHealthExport),
+		"apple_health_export/export_cda.xml":                              []byte(`<ClinicalDocument/>`),
+		"apple_health_export/workout-routes/route_2024-05-01_10.00am.gpx": []byte(testGPX),
+	})
+
+	sink := &testSink{}
+	require.NoError(t, ImportArchive(context.Background(), ArchiveProgramApple, archivePath, sink))
+	assert.Empty(t, sink.failed)
+
+	require.Len(t, sink.workouts, 2)
+
+	ride := sink.workouts[0]
+	assert.Equal(t, model.WorkoutTypeCycling, ride.Type)
+	assert.Equal(t, "apple_health_export/workout-routes/route_2024-05-01_10.00am.gpx", ride.Entry)
+	require.Len(t, ride.Workouts, 1)
+
+	points := ride.Workouts[0].Data.Details.Points
+	require.Len(t, points, 3)
+	assert.InDelta(t, 120, points[0].ExtraMetrics.Get("heart-rate"), 0.001)
+	assert.InDelta(t, 140, points[1].ExtraMetrics.Get("heart-rate"), 0.001)
+	assert.NotContains(t, points[2].ExtraMetrics, "heart-rate")
+	assert.Contains(t, string(ride.Workouts[0].GPX.Content), "heart-rate")
+
+	strength := sink.workouts[1]
+	assert.Equal(t, model.WorkoutTypeWeightLifting, strength.Type)
+	require.Len(t, strength.Workouts, 1)
+	assert.Equal(t, "TraditionalStrengthTraining - 2024-05-01 18:00:00", strength.Workouts[0].Name)
+	assert.Equal(t, "30m0s", strength.Workouts[0].Data.TotalDuration.String())
+	assert.InDelta(t, 100, strength.Workouts[0].Data.AverageHeartRate, 0.001)
+
+	require.Len(t, sink.measurements, 1)
+	m := sink.measurements["2024-05-01"]
+	require.NotNil(t, m)
+	assert.InDelta(t, 72.57, m.Weight, 0.01)
+	assert.InDelta(t, 180, m.Height, 0.001)
+	assert.InDelta(t, 1500, m.Steps, 0.001)
+	assert.InDelta(t, 51, m.RestingHeartRate, 0.001)
+}
+
+func TestImportArchive_AppleHealthWithoutExport(t *testing.T) {
+	archivePath := writeTestArchive(t, map[string][]byte{
+		"apple_health_export/workout-routes/route.gpx": []byte(testGPX),
+	})
+
+	err := ImportArchive(context.Background(), ArchiveProgramApple, archivePath, &testSink{})
+	require.ErrorIs(t, err, ErrInvalidArchive)
+}
@@ -0,0 +1,84 @@
+package importers
+
+import (
+	"archive/zip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+var (
+	ErrUnsupportedArchive = errors.New("unsupported archive program")
+	ErrInvalidArchive     = errors.New("invalid archive")
+)
+
+type ArchiveProgram string
+
+const (
+	ArchiveProgramStrava ArchiveProgram = "strava"
+	ArchiveProgramGarmin ArchiveProgram = "garmin"
+	ArchiveProgramApple  ArchiveProgram = "apple-health"
+)
+
+// ArchivePrograms lists all programs an export archive can be imported from
+var ArchivePrograms = []ArchiveProgram{
+	ArchiveProgramStrava,
+	ArchiveProgramGarmin,
+	ArchiveProgramApple,
+}
+
+func (p ArchiveProgram) IsValid() bool {
+	return slices.Contains(ArchivePrograms, p)
+}
+
+// ArchiveWorkout holds the workouts parsed from a single archive entry,
+// together with the metadata the archive provides for them
+type ArchiveWorkout struct {
+	Entry      string                   // The name of the entry in the archive
+	Workouts   []*model.Workout         // The workouts parsed from the entry
+	Name       string                   // The name of the workout, if known
+	Notes      string                   // The notes of the workout, if known
+	Type       model.WorkoutType        // The type of the workout; auto-detected when empty
+	Visibility *model.WorkoutVisibility // The visibility of the workout; the user's default when nil
+	Equipment  []string                 // The names of the equipment used
+	Commute    bool                     // Whether the workout was a commute
+}
+
+// ArchiveSink receives the items read from an archive. Importers report
+// entries they can not process, and continue with the next entry.
+type ArchiveSink interface {
+	AddWorkout(w *ArchiveWorkout)
+	AddMeasurement(entry string, date time.Time, update func(m *model.Measurement))
+	Skip(entry string)
+	Fail(entry string, err error)
+}
+
+// ImportArchive reads the export archive stored at path, and passes every
+// item it contains to the sink
+func ImportArchive(ctx context.Context, program ArchiveProgram, path string, sink ArchiveSink) error {
+	switch program {
+	case ArchiveProgramStrava:
+		return importStravaArchive(ctx, path, sink)
+	case ArchiveProgramGarmin:
+		return importGarminArchive(ctx, path, sink)
+	case ArchiveProgramApple:
+		return importAppleHealthArchive(ctx, path, sink)
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedArchive, program)
+	}
+}
+
+func readZipFile(zf *zip.File) ([]byte, error) {
+	f, err := zf.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	return io.ReadAll(f)
+}
@@ -0,0 +1,95 @@
+package importers
+
+import (
+	"archive/zip"
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/geocoder"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/stretchr/testify/require"
+)
+
+func init() {
+	geocoder.ForceOffline()
+}
+
+const testGPX = `<?xml version="1.0" encoding="UTF-8"?>
+<gpx version="1.1" creator="test" xmlns="http://www.topografix.com/GPX/1/1">
+  <trk><trkseg>
+    <trkpt lat="51.0000" lon="4.0000"><ele>10</ele><time>2024-05-01T08:00:00Z</time></trkpt>
+    <trkpt lat="51.0010" lon="4.0010"><ele>11</ele><time>2024-05-01T08:01:00Z</time></trkpt>
+    <trkpt lat="51.0020" lon="4.0020"><ele>12</ele><time>2024-05-01T08:02:00Z</time></trkpt>
+  </trkseg></trk>
+</gpx>`
+
+// testSink records everything an importer passes to it
+type testSink struct {
+	workouts     []*ArchiveWorkout
+	measurements map[string]*model.Measurement
+	skipped      []string
+	failed       map[string]error
+}
+
+func (s *testSink) AddWorkout(w *ArchiveWorkout) {
+	s.workouts = append(s.workouts, w)
+}
+
+func (s *testSink) AddMeasurement(_ string, date time.Time, update func(m *model.Measurement)) {
+	if s.measurements == nil {
+		s.measurements = map[string]*model.Measurement{}
+	}
+
+	key := date.Format(time.DateOnly)
+	if _, ok := s.measurements[key]; !ok {
+		s.measurements[key] = &model.Measurement{}
+	}
+
+	update(s.measurements[key])
+}
+
+func (s *testSink) Skip(entry string) {
+	s.skipped = append(s.skipped, entry)
+}
+
+func (s *testSink) Fail(entry string, err error) {
+	if s.failed == nil {
+		s.failed = map[string]error{}
+	}
+
+	s.failed[entry] = err
+}
+
+// testZip returns a zip archive with the given files
+func testZip(t *testing.T, files map[string][]byte) []byte {
+	t.Helper()
+
+	buf := bytes.NewBuffer(nil)
+	zw := zip.NewWriter(buf)
+
+	for name, content := range files {
+		w, err := zw.Create(name)
+		require.NoError(t, err)
+
+		_, err = w.Write(content)
+		require.NoError(t, err)
+	}
+
+	require.NoError(t, zw.Close())
+
+	return buf.Bytes()
+}
+
+// writeTestArchive writes a zip archive with the given files to disk, and
+// returns its path
+func writeTestArchive(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+
+	archivePath := filepath.Join(t.TempDir(), "export.zip")
+	require.NoError(t, os.WriteFile(archivePath, testZip(t, files), 0o600))
+
+	return archivePath
+}
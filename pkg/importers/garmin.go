@@ -0,0 +1,423 @@
+package importers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/converters"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+const (
+	garminUploadedFilesDir       = "DI-Connect-Uploaded-Files"
+	garminSummarizedActivities   = "summarizedActivities.json"
+	garminBioMetrics             = "userBioMetrics.json"
+	garminDailySummaryPrefix     = "UDSFile"
+	garminGramsPerKilogram       = 1000
+	garminActivityMatchTolerance = 2 // seconds
+)
+
+type (
+	garminSummaryFile struct {
+		Activities []garminActivity `json:"summarizedActivitiesExport"`
+	}
+
+	garminActivity struct {
+		ID           uint64  `json:"activityId"`
+		Name         string  `json:"name"`
+		ActivityType string  `json:"activityType"`
+		StartTimeGMT float64 `json:"startTimeGmt"` // Milliseconds since the epoch
+	}
+
+	garminBioMetric struct {
+		MetaData struct {
+			CalendarDate string `json:"calendarDate"`
+		} `json:"metaData"`
+		Weight *struct {
+			Weight float64 `json:"weight"` // In grams
+		} `json:"weight"`
+	}
+
+	garminDailySummary struct {
+		CalendarDate     json.RawMessage `json:"calendarDate"`
+		RestingHeartRate float64         `json:"restingHeartRate"`
+	}
+)
+
+// garminActivities holds the activity summaries by their start time, in
+// seconds since the epoch
+type garminActivities map[int64]*garminActivity
+
+// importGarminArchive imports a Garmin Connect data export: the uploaded
+// activity files are stored in nested zips in the DI-Connect-Uploaded-Files
+// folder, activity summaries and health data in JSON files
+func importGarminArchive(ctx context.Context, archivePath string, sink ArchiveSink) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer zr.Close()
+
+	activities := garminActivities{}
+
+	var uploads []*zip.File
+
+	for _, f := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		name := path.Base(f.Name)
+
+		switch {
+		case strings.HasSuffix(name, garminSummarizedActivities):
+			if err := activities.read(f); err != nil {
+				sink.Fail(f.Name, err)
+			}
+		case strings.HasSuffix(name, garminBioMetrics):
+			if err := importGarminBioMetrics(f, sink); err != nil {
+				sink.Fail(f.Name, err)
+			}
+		case strings.HasPrefix(name, garminDailySummaryPrefix) && strings.HasSuffix(name, ".json"):
+			if err := importGarminDailySummaries(f, sink); err != nil {
+				sink.Fail(f.Name, err)
+			}
+		case strings.Contains(f.Name, garminUploadedFilesDir+"/") && strings.EqualFold(path.Ext(name), ".zip"):
+			uploads = append(uploads, f)
+		}
+	}
+
+	if len(uploads) == 0 {
+		return fmt.Errorf("%w: %s not found", ErrInvalidArchive, garminUploadedFilesDir)
+	}
+
+	// The summaries are read first, so they can be matched to the files
+	for _, f := range uploads {
+		if err := importGarminUploads(ctx, f, filepath.Dir(archivePath), activities, sink); err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				return err
+			}
+
+			sink.Fail(f.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// importGarminUploads imports every activity file in a nested zip; the zip is
+// extracted to a temporary file in dir (the import directory the archive is
+// stored in), since it can be several gigabytes
+func importGarminUploads(ctx context.Context, f *zip.File, dir string, activities garminActivities, sink ArchiveSink) error {
+	tmp, err := os.CreateTemp(dir, "garmin-*.zip")
+	if err != nil {
+		return err
+	}
+
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(tmp, rc)
+	rc.Close()
+
+	if err != nil {
+		return err
+	}
+
+	size, err := tmp.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+
+	zr, err := zip.NewReader(tmp, size)
+	if err != nil {
+		return err
+	}
+
+	for _, zf := range zr.File {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		if zf.FileInfo().IsDir() {
+			continue
+		}
+
+		entry := f.Name + "/" + zf.Name
+
+		if !converters.IsSupportedFile(path.Base(zf.Name)) {
+			sink.Skip(entry)
+			continue
+		}
+
+		if err := importGarminActivity(zf, entry, activities, sink); err != nil {
+			sink.Fail(entry, err)
+		}
+	}
+
+	return nil
+}
+
+func importGarminActivity(zf *zip.File, entry string, activities garminActivities, sink ArchiveSink) error {
+	content, err := readZipFile(zf)
+	if err != nil {
+		return err
+	}
+
+	var workouts []*model.Workout
+
+	if strings.EqualFold(path.Ext(zf.Name), ".fit") {
+		workouts, err = converters.ParseFit(content, path.Base(zf.Name))
+	} else {
+		workouts, err = converters.ParseCollection(zf.Name, content)
+	}
+
+	if errors.Is(err, converters.ErrNoSessionsFound) {
+		// Not an activity, e.g. a weight-scale, monitoring or settings file
+		return importGarminMeasurements(content, entry, sink)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(workouts) == 0 {
+		return converters.ErrNoTracksFound
+	}
+
+	aw := &ArchiveWorkout{
+		Entry:    entry,
+		Workouts: workouts,
+	}
+
+	if a := activities.find(workouts[0].Date); a != nil {
+		aw.Name = a.Name
+		aw.Type = garminWorkoutType(a.ActivityType)
+	}
+
+	sink.AddWorkout(aw)
+
+	return nil
+}
+
+func (activities garminActivities) read(f *zip.File) error {
+	content, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+
+	var files []garminSummaryFile
+	if err := json.Unmarshal(content, &files); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		for i := range file.Activities {
+			a := &file.Activities[i]
+			if a.StartTimeGMT == 0 {
+				continue
+			}
+
+			activities[time.UnixMilli(int64(a.StartTimeGMT)).Unix()] = a
+		}
+	}
+
+	return nil
+}
+
+// find returns the summary of the activity starting at the given time; the
+// start time of the file and the summary may differ slightly
+func (activities garminActivities) find(start time.Time) *garminActivity {
+	if start.IsZero() {
+		return nil
+	}
+
+	s := start.Unix()
+
+	for d := int64(0); d <= garminActivityMatchTolerance; d++ {
+		if a, ok := activities[s-d]; ok {
+			return a
+		}
+
+		if a, ok := activities[s+d]; ok {
+			return a
+		}
+	}
+
+	return nil
+}
+
+func importGarminBioMetrics(f *zip.File, sink ArchiveSink) error {
+	content, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+
+	var metrics []garminBioMetric
+	if err := json.Unmarshal(content, &metrics); err != nil {
+		return err
+	}
+
+	for _, m := range metrics {
+		if m.Weight == nil || m.Weight.Weight <= 0 {
+			continue
+		}
+
+		d, ok := parseGarminDate(m.MetaData.CalendarDate)
+		if !ok {
+			continue
+		}
+
+		weight := m.Weight.Weight / garminGramsPerKilogram
+
+		sink.AddMeasurement(f.Name, d, func(m *model.Measurement) {
+			m.Weight = weight
+		})
+	}
+
+	return nil
+}
+
+// importGarminMeasurements adds the measurements of a weight-scale or
+// monitoring FIT file; other files are skipped
+func importGarminMeasurements(content []byte, entry string, sink ArchiveSink) error {
+	ms, err := converters.ParseFitMeasurements(content)
+	if errors.Is(err, converters.ErrNotMeasurementFile) {
+		sink.Skip(entry)
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if len(ms) == 0 {
+		sink.Skip(entry)
+		return nil
+	}
+
+	for _, m := range ms {
+		sink.AddMeasurement(entry, time.Time(m.Date), func(existing *model.Measurement) {
+			existing.MergeNonZero(*m)
+		})
+	}
+
+	return nil
+}
+
+func importGarminDailySummaries(f *zip.File, sink ArchiveSink) error {
+	content, err := readZipFile(f)
+	if err != nil {
+		return err
+	}
+
+	var summaries []garminDailySummary
+	if err := json.Unmarshal(content, &summaries); err != nil {
+		return err
+	}
+
+	for _, s := range summaries {
+		if s.RestingHeartRate <= 0 {
+			continue
+		}
+
+		d, ok := parseGarminDate(garminCalendarDate(s.CalendarDate))
+		if !ok {
+			continue
+		}
+
+		rhr := s.RestingHeartRate
+
+		sink.AddMeasurement(f.Name, d, func(m *model.Measurement) {
+			m.RestingHeartRate = rhr
+		})
+	}
+
+	return nil
+}
+
+// garminCalendarDate returns the calendar date, which is either a string or
+// an object with a "date" field, depending on the age of the export
+func garminCalendarDate(raw json.RawMessage) string {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s
+	}
+
+	var obj struct {
+		Date string `json:"date"`
+	}
+
+	if err := json.Unmarshal(raw, &obj); err == nil {
+		return obj.Date
+	}
+
+	return ""
+}
+
+func parseGarminDate(s string) (time.Time, bool) {
+	s = strings.TrimSpace(s)
+
+	if len(s) >= len(time.DateOnly) {
+		if d, err := time.Parse(time.DateOnly, s[:len(time.DateOnly)]); err == nil {
+			return d, true
+		}
+	}
+
+	if d, err := time.Parse("Jan 2, 2006", s); err == nil {
+		return d, true
+	}
+
+	return time.Time{}, false
+}
+
+// garminWorkoutType maps Garmin's activity type keys (e.g. "road_biking",
+// "trail_running") to a workout type
+func garminWorkoutType(t string) model.WorkoutType {
+	switch strings.ToLower(t) {
+	case "running", "trail_running", "treadmill_running", "track_running", "street_running", "virtual_run", "indoor_running":
+		return model.WorkoutTypeRunning
+	case "cycling", "road_biking", "mountain_biking", "gravel_cycling", "indoor_cycling", "virtual_ride", "cyclocross", "track_cycling", "recumbent_cycling":
+		return model.WorkoutTypeCycling
+	case "e_bike_fitness", "e_bike_mountain", "e_enduro_mtb":
+		return model.WorkoutTypeECycling
+	case "walking", "casual_walking", "speed_walking":
+		return model.WorkoutTypeWalking
+	case "hiking":
+		return model.WorkoutTypeHiking
+	case "swimming", "lap_swimming", "open_water_swimming":
+		return model.WorkoutTypeSwimming
+	case "resort_skiing_snowboarding_ws", "resort_skiing", "backcountry_skiing", "cross_country_skiing_ws", "skate_skiing_ws":
+		return model.WorkoutTypeSkiing
+	case "resort_snowboarding", "backcountry_snowboarding":
+		return model.WorkoutTypeSnowboarding
+	case "inline_skating":
+		return model.WorkoutTypeInlineSkating
+	case "kayaking_v2", "kayaking", "whitewater_rafting_kayaking", "paddling":
+		return model.WorkoutTypeKayaking
+	case "rowing", "rowing_v2", "indoor_rowing":
+		return model.WorkoutTypeRowing
+	case "golf":
+		return model.WorkoutTypeGolfing
+	case "strength_training":
+		return model.WorkoutTypeWeightLifting
+	case "horseback_riding":
+		return model.WorkoutTypeHorseRiding
+	default:
+		return model.WorkoutTypeAutoDetect
+	}
+}
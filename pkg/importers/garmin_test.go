@@ -0,0 +1,75 @@
+package importers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/converters"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testFIT(t *testing.T) []byte {
+	t.Helper()
+
+	workouts, err := converters.ParseCollection("workout.gpx", []byte(testGPX))
+	require.NoError(t, err)
+	require.Len(t, workouts, 1)
+
+	content, err := converters.Export(workouts[0], converters.ExportFormatFIT)
+	require.NoError(t, err)
+
+	return content
+}
+
+func TestImportArchive_Garmin(t *testing.T) {
+	uploads := testZip(t, map[string][]byte{
+		"user@example.com_1001.fit": testFIT(t),
+		"user@example.com_1002.txt": []byte("not an activity"),
+	})
+
+	archivePath := writeTestArchive(t, map[string][]byte{
+		"DI_CONNECT/DI-Connect-Uploaded-Files/UploadedFiles_0-_Part1.zip": uploads,
+		"DI_CONNECT/DI-Connect-Fitness/user_0_summarizedActivities.json": []byte(`[{"summarizedActivitiesExport": [
+			{"activityId": 1001, "name": "Ghent Cycling", "activityType": "road_biking", "startTimeGmt": 1714550400000.0}
+		]}]`),
+		"DI_CONNECT/DI-Connect-Wellness/123_userBioMetrics.json": []byte(`[
+			{"metaData": {"calendarDate": "2024-05-01T00:00:00.0"}, "weight": {"weight": 72500.0}},
+			{"metaData": {"calendarDate": "2024-05-02T00:00:00.0"}}
+		]`),
+		"DI_CONNECT/DI-Connect-Aggregator/UDSFile_2024-05-01_2024-08-09.json": []byte(`[
+			{"calendarDate": "2024-05-01", "restingHeartRate": 52},
+			{"calendarDate": "2024-05-03", "restingHeartRate": 0}
+		]`),
+	})
+
+	sink := &testSink{}
+	require.NoError(t, ImportArchive(context.Background(), ArchiveProgramGarmin, archivePath, sink))
+
+	require.Len(t, sink.workouts, 1)
+
+	w := sink.workouts[0]
+	assert.Equal(t, "Ghent Cycling", w.Name)
+	assert.Equal(t, model.WorkoutTypeCycling, w.Type)
+	require.Len(t, w.Workouts, 1)
+	assert.Len(t, w.Workouts[0].Data.Details.Points, 3)
+
+	assert.Len(t, sink.skipped, 1)
+	assert.Empty(t, sink.failed)
+
+	require.Len(t, sink.measurements, 1)
+	m := sink.measurements["2024-05-01"]
+	require.NotNil(t, m)
+	assert.InDelta(t, 72.5, m.Weight, 0.001)
+	assert.InDelta(t, 52, m.RestingHeartRate, 0.001)
+}
+
+func TestImportArchive_GarminWithoutUploads(t *testing.T) {
+	archivePath := writeTestArchive(t, map[string][]byte{
+		"DI_CONNECT/DI-Connect-Fitness/user_0_summarizedActivities.json": []byte(`[]`),
+	})
+
+	err := ImportArchive(context.Background(), ArchiveProgramGarmin, archivePath, &testSink{})
+	require.ErrorIs(t, err, ErrInvalidArchive)
+}
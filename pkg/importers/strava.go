@@ -0,0 +1,250 @@
+package importers
+
+import (
+	"archive/zip"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/converters"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/spf13/cast"
+)
+
+const stravaActivitiesCSV = "activities.csv"
+
+// stravaColumns maps the (lower case) column names of activities.csv to
+// their index; some names occur more than once, the first one wins
+type stravaColumns map[string]int
+
+type stravaActivity struct {
+	ID          string
+	Name        string
+	Description string
+	PrivateNote string
+	Type        string
+	Gear        string
+	Filename    string
+	Visibility  string
+	Private     string
+	Commute     string
+}
+
+// importStravaArchive imports a Strava account export: activities.csv holds
+// the metadata for every activity, the activity files themselves are stored
+// in the activities/ folder (optionally compressed)
+func importStravaArchive(ctx context.Context, archivePath string, sink ArchiveSink) error {
+	zr, err := zip.OpenReader(archivePath)
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+	defer zr.Close()
+
+	var csvFile *zip.File
+
+	files := make(map[string]*zip.File, len(zr.File))
+	for _, f := range zr.File {
+		files[path.Clean(f.Name)] = f
+
+		if path.Base(f.Name) == stravaActivitiesCSV && (csvFile == nil || len(f.Name) < len(csvFile.Name)) {
+			csvFile = f
+		}
+	}
+
+	if csvFile == nil {
+		return fmt.Errorf("%w: %s not found", ErrInvalidArchive, stravaActivitiesCSV)
+	}
+
+	rc, err := csvFile.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+
+	r := csv.NewReader(rc)
+	r.FieldsPerRecord = -1
+	r.LazyQuotes = true
+
+	header, err := r.Read()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrInvalidArchive, err)
+	}
+
+	cols := newStravaColumns(header)
+	root := path.Dir(csvFile.Name)
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		record, err := r.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+
+		if err != nil {
+			sink.Fail(stravaActivitiesCSV, err)
+			continue
+		}
+
+		a := cols.activity(record)
+		if a.Filename == "" {
+			// Manually added activities have no file
+			sink.Skip(stravaActivitiesCSV + ": " + a.ID)
+			continue
+		}
+
+		zf, ok := files[path.Join(root, a.Filename)]
+		if !ok {
+			sink.Fail(a.Filename, errors.New("file not found in archive"))
+			continue
+		}
+
+		if err := importStravaActivity(zf, a, sink); err != nil {
+			sink.Fail(a.Filename, err)
+		}
+	}
+
+	return nil
+}
+
+func importStravaActivity(zf *zip.File, a *stravaActivity, sink ArchiveSink) error {
+	content, err := readZipFile(zf)
+	if err != nil {
+		return err
+	}
+
+	workouts, err := converters.ParseCollection(zf.Name, content)
+	if err != nil {
+		return err
+	}
+
+	if len(workouts) == 0 {
+		return converters.ErrNoTracksFound
+	}
+
+	aw := &ArchiveWorkout{
+		Entry:      zf.Name,
+		Workouts:   workouts,
+		Name:       a.Name,
+		Notes:      a.Description,
+		Type:       stravaWorkoutType(a.Type),
+		Visibility: a.visibility(),
+		Commute:    cast.ToBool(a.Commute),
+	}
+
+	if a.PrivateNote != "" {
+		aw.Notes = strings.TrimSpace(aw.Notes + "\n\n" + a.PrivateNote)
+	}
+
+	if a.Gear != "" {
+		aw.Equipment = []string{a.Gear}
+	}
+
+	sink.AddWorkout(aw)
+
+	return nil
+}
+
+func newStravaColumns(header []string) stravaColumns {
+	cols := stravaColumns{}
+
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if _, ok := cols[h]; !ok {
+			cols[h] = i
+		}
+	}
+
+	return cols
+}
+
+func (cols stravaColumns) get(record []string, names ...string) string {
+	for _, n := range names {
+		if i, ok := cols[n]; ok && i < len(record) {
+			return strings.TrimSpace(record[i])
+		}
+	}
+
+	return ""
+}
+
+func (cols stravaColumns) activity(record []string) *stravaActivity {
+	return &stravaActivity{
+		ID:          cols.get(record, "activity id"),
+		Name:        cols.get(record, "activity name"),
+		Description: cols.get(record, "activity description"),
+		PrivateNote: cols.get(record, "activity private note"),
+		Type:        cols.get(record, "activity type"),
+		Gear:        cols.get(record, "activity gear"),
+		Filename:    cols.get(record, "filename"),
+		Visibility:  cols.get(record, "activity visibility", "visibility"),
+		Private:     cols.get(record, "private", "activity private"),
+		Commute:     cols.get(record, "commute", "activity commute"),
+	}
+}
+
+func (a *stravaActivity) visibility() *model.WorkoutVisibility {
+	var v model.WorkoutVisibility
+
+	switch strings.ToLower(a.Visibility) {
+	case "everyone":
+		v = model.WorkoutVisibilityPublic
+	case "followers_only":
+		v = model.WorkoutVisibilityFollowers
+	case "only_me":
+		v = model.WorkoutVisibilityPrivate
+	default:
+		if !cast.ToBool(a.Private) {
+			return nil
+		}
+
+		v = model.WorkoutVisibilityPrivate
+	}
+
+	return &v
+}
+
+// stravaWorkoutType maps Strava's activity types (e.g. "Ride", "Virtual
+// Ride", "E-Bike Ride") to a workout type
+func stravaWorkoutType(t string) model.WorkoutType {
+	t = strings.ToLower(strings.NewReplacer(" ", "", "-", "", "_", "").Replace(t))
+
+	switch t {
+	case "run", "trailrun", "virtualrun":
+		return model.WorkoutTypeRunning
+	case "ride", "virtualride", "mountainbikeride", "gravelride", "handcycle", "velomobile":
+		return model.WorkoutTypeCycling
+	case "ebikeride", "emountainbikeride":
+		return model.WorkoutTypeECycling
+	case "walk":
+		return model.WorkoutTypeWalking
+	case "hike":
+		return model.WorkoutTypeHiking
+	case "swim":
+		return model.WorkoutTypeSwimming
+	case "alpineski", "backcountryski", "nordicski":
+		return model.WorkoutTypeSkiing
+	case "snowboard":
+		return model.WorkoutTypeSnowboarding
+	case "inlineskate":
+		return model.WorkoutTypeInlineSkating
+	case "kayaking", "canoeing":
+		return model.WorkoutTypeKayaking
+	case "rowing", "virtualrow":
+		return model.WorkoutTypeRowing
+	case "golf":
+		return model.WorkoutTypeGolfing
+	case "weighttraining":
+		return model.WorkoutTypeWeightLifting
+	case "horsebackriding", "horseriding":
+		return model.WorkoutTypeHorseRiding
+	default:
+		return model.WorkoutTypeAutoDetect
+	}
+}
@@ -0,0 +1,57 @@
+package importers
+
+import (
+	"context"
+	"testing"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestImportArchive_Strava(t *testing.T) {
+	csv := "\ufeffActivity ID,Activity Date,Activity Name,Activity Type,Activity Description,Activity Gear,Filename,Activity Private Note,Activity Visibility,Commute\n" +
+		`1,"May 1, 2024, 8:00:00 AM",Morning Ride,Ride,"Nice, sunny",Road bike,activities/1.gpx,,followers_only,true` + "\n" +
+		`2,"May 2, 2024, 8:00:00 AM",Manual entry,Run,,,,,everyone,false` + "\n" +
+		`3,"May 3, 2024, 8:00:00 AM",Lost file,Run,,,activities/3.gpx,,everyone,false` + "\n"
+
+	archivePath := writeTestArchive(t, map[string][]byte{
+		"export_123/activities.csv":    []byte(csv),
+		"export_123/activities/1.gpx":  []byte(testGPX),
+		"export_123/profile_image.jpg": []byte("not an activity"),
+	})
+
+	sink := &testSink{}
+	require.NoError(t, ImportArchive(context.Background(), ArchiveProgramStrava, archivePath, sink))
+
+	require.Len(t, sink.workouts, 1)
+
+	w := sink.workouts[0]
+	assert.Equal(t, "export_123/activities/1.gpx", w.Entry)
+	assert.Equal(t, "Morning Ride", w.Name)
+	assert.Equal(t, "Nice, sunny", w.Notes)
+	assert.Equal(t, model.WorkoutTypeCycling, w.Type)
+	assert.Equal(t, []string{"Road bike"}, w.Equipment)
+	require.NotNil(t, w.Visibility)
+	assert.Equal(t, model.WorkoutVisibilityFollowers, *w.Visibility)
+	assert.True(t, w.Commute)
+	require.Len(t, w.Workouts, 1)
+	assert.Len(t, w.Workouts[0].Data.Details.Points, 3)
+
+	assert.Len(t, sink.skipped, 1)
+	assert.Contains(t, sink.failed, "activities/3.gpx")
+}
+
+func TestImportArchive_StravaWithoutActivities(t *testing.T) {
+	archivePath := writeTestArchive(t, map[string][]byte{
+		"activities/1.gpx": []byte(testGPX),
+	})
+
+	err := ImportArchive(context.Background(), ArchiveProgramStrava, archivePath, &testSink{})
+	require.ErrorIs(t, err, ErrInvalidArchive)
+}
+
+func TestImportArchive_Unsupported(t *testing.T) {
+	err := ImportArchive(context.Background(), ArchiveProgram("unknown"), "", &testSink{})
+	require.ErrorIs(t, err, ErrUnsupportedArchive)
+}
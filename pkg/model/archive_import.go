@@ -0,0 +1,58 @@
+package model
+
+import (
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+// maxArchiveImportErrors limits how many per-entry errors are kept
+const maxArchiveImportErrors = 100
+
+type ArchiveImportStatus string
+
+const (
+	ArchiveImportStatusPending ArchiveImportStatus = "pending"
+	ArchiveImportStatusRunning ArchiveImportStatus = "running"
+	ArchiveImportStatusDone    ArchiveImportStatus = "done"
+	ArchiveImportStatusFailed  ArchiveImportStatus = "failed"
+)
+
+// ArchiveImport tracks the background import of a bulk export archive
+// (e.g. a Strava, Garmin or Apple Health export)
+type ArchiveImport struct {
+	Model
+	User         *User               `gorm:"foreignKey:UserID" json:"-"`    // The user who uploaded the archive
+	UserID       uint64              `gorm:"not null;index" json:"userID"`  // The ID of the user who uploaded the archive
+	Program      string              `gorm:"not null" json:"program"`       // The program the archive was exported from
+	Filename     string              `json:"filename"`                      // The original filename of the archive
+	Path         string              `json:"-"`                             // Where the archive is stored while it is being imported
+	Status       ArchiveImportStatus `gorm:"not null" json:"status"`        // The status of the import
+	Workouts     int                 `json:"workouts"`                      // The number of imported workouts
+	Measurements int                 `json:"measurements"`                  // The number of imported or updated measurements
+	Skipped      int                 `json:"skipped"`                       // The number of entries skipped, e.g. duplicates
+	Failed       int                 `json:"failed"`                        // The number of entries that could not be imported
+	Errors       []string            `gorm:"serializer:json" json:"errors"` // The first errors that occurred
+	Error        string              `json:"error"`                         // The error that aborted the import
+}
+
+func (a *ArchiveImport) Save(db *gorm.DB) error {
+	return db.Save(a).Error
+}
+
+func (a *ArchiveImport) Delete(db *gorm.DB) error {
+	return db.Delete(a).Error
+}
+
+// AddError counts a failed entry and keeps the error message
+func (a *ArchiveImport) AddError(entry string, err error) {
+	a.Failed++
+
+	if len(a.Errors) < maxArchiveImportErrors {
+		a.Errors = append(a.Errors, fmt.Sprintf("%s: %s", entry, err))
+	}
+}
+
+func (a *ArchiveImport) Finished() bool {
+	return a.Status == ArchiveImportStatusDone || a.Status == ArchiveImportStatusFailed
+}
@@ -36,6 +36,9 @@ type EnvConfig struct {
 	WorkerDelaySeconds int    `mapstructure:"worker_delay_seconds" gorm:"-"` // Time in seconds between worker runs
 	AutoImportEnabled  bool   `mapstructure:"auto_import_enabled" gorm:"-"`  // Enable auto-import scheduler and profile setting
 	ActivityPubActive  bool   `mapstructure:"activity_pub_active" gorm:"-"`  // Whether the ActivityPub implementation is active
+	ImportDirectory    string `mapstructure:"import_directory" gorm:"-"`     // Where uploaded archives are stored until they are imported
+	DEMDirectory       string `mapstructure:"dem_directory" gorm:"-"`        // Where the elevation model files (HGT or GeoTIFF) are stored
+	TrackLimits        string `mapstructure:"track_limits" gorm:"-"`         // Overrides of the GPS track cleaning limits per workout type
 
 	JWTEncryptionKeyFile string `mapstructure:"jwt_encryption_key_file" gorm:"-"` // File containing the encryption key for JWT
 	DSNFile              string `mapstructure:"dsn_file" gorm:"-"`                // File containing the database DSN
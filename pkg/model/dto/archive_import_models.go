@@ -0,0 +1,53 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// ArchiveImportResponse represents the import of an export archive in API v2 responses
+type ArchiveImportResponse struct {
+	ID           uint64    `json:"id"`
+	Program      string    `json:"program"`
+	Filename     string    `json:"filename"`
+	Status       string    `json:"status"`
+	Workouts     int       `json:"workouts"`
+	Measurements int       `json:"measurements"`
+	Skipped      int       `json:"skipped"`
+	Failed       int       `json:"failed"`
+	Errors       []string  `json:"errors,omitempty"`
+	Error        string    `json:"error,omitempty"`
+	UserID       uint64    `json:"user_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// NewArchiveImportResponse converts a database archive import to API response
+func NewArchiveImportResponse(a *model.ArchiveImport) ArchiveImportResponse {
+	return ArchiveImportResponse{
+		ID:           a.ID,
+		Program:      a.Program,
+		Filename:     a.Filename,
+		Status:       string(a.Status),
+		Workouts:     a.Workouts,
+		Measurements: a.Measurements,
+		Skipped:      a.Skipped,
+		Failed:       a.Failed,
+		Errors:       a.Errors,
+		Error:        a.Error,
+		UserID:       a.UserID,
+		CreatedAt:    a.CreatedAt,
+		UpdatedAt:    a.UpdatedAt,
+	}
+}
+
+// NewArchiveImportsResponse converts a list of archive imports to API responses
+func NewArchiveImportsResponse(archiveImports []*model.ArchiveImport) []ArchiveImportResponse {
+	results := make([]ArchiveImportResponse, len(archiveImports))
+	for i, a := range archiveImports {
+		results[i] = NewArchiveImportResponse(a)
+	}
+
+	return results
+}
@@ -0,0 +1,45 @@
+package dto
+
+import (
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// DuplicatePairResponse represents two workouts that are probably recordings
+// of the same activity
+type DuplicatePairResponse struct {
+	Workout WorkoutResponse `json:"workout"`
+	Other   WorkoutResponse `json:"other"`
+	Overlap float64         `json:"overlap"` // The part of the shorter workout that overlaps with the other, between 0 and 1
+}
+
+// MergeWorkoutRequest is the workout to merge with the workout of the path
+type MergeWorkoutRequest struct {
+	WorkoutID uint64 `json:"workout_id"`
+}
+
+// MergeWorkoutResponse is the result of merging two workouts
+type MergeWorkoutResponse struct {
+	Workout          WorkoutResponse `json:"workout"`            // The kept workout
+	DeletedWorkoutID uint64          `json:"deleted_workout_id"` // The ID of the deleted workout
+}
+
+func NewDuplicatePairResponses(pairs []model.DuplicatePair) []DuplicatePairResponse {
+	results := make([]DuplicatePairResponse, len(pairs))
+
+	for i, p := range pairs {
+		results[i] = DuplicatePairResponse{
+			Workout: NewWorkoutResponse(p.Workout),
+			Other:   NewWorkoutResponse(p.Other),
+			Overlap: p.Overlap,
+		}
+	}
+
+	return results
+}
+
+// MergeSensorsResponse is the result of merging the sensor streams of another
+// file into a workout
+type MergeSensorsResponse struct {
+	Workout WorkoutResponse `json:"workout"`
+	Metrics []string        `json:"metrics"` // The merged metrics
+}
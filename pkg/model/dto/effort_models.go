@@ -0,0 +1,78 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// EffortRecordResponse represents the best average of a metric over a duration
+type EffortRecordResponse struct {
+	Metric          string    `json:"metric"`           // The metric: power, speed or heart-rate
+	Label           string    `json:"label"`            // The duration label (e.g. 5 min)
+	DurationSeconds float64   `json:"duration_seconds"` // The duration of the effort
+	Value           float64   `json:"value"`            // The average of the metric over the effort
+	Distance        float64   `json:"distance"`         // The distance covered during the effort
+	WorkoutID       uint64    `json:"workout_id"`
+	Date            time.Time `json:"date"`
+	StartIndex      int       `json:"start_index,omitempty"`
+	EndIndex        int       `json:"end_index,omitempty"`
+}
+
+// WorkoutEffortRecordResponse represents a stored best effort of a workout with its rank
+type WorkoutEffortRecordResponse struct {
+	Metric          string  `json:"metric"`
+	Label           string  `json:"label"`
+	DurationSeconds float64 `json:"duration_seconds"`
+	Value           float64 `json:"value"`
+	Distance        float64 `json:"distance"`
+	StartIndex      int     `json:"start_index"`
+	EndIndex        int     `json:"end_index"`
+	Rank            int64   `json:"rank"`
+}
+
+// NewEffortRecordResponses converts best efforts to API responses
+func NewEffortRecordResponses(records []model.EffortRecord) []EffortRecordResponse {
+	result := make([]EffortRecordResponse, len(records))
+
+	for i, r := range records {
+		result[i] = EffortRecordResponse{
+			Metric:          string(r.Metric),
+			Label:           r.Label,
+			DurationSeconds: r.Duration.Seconds(),
+			Value:           r.Value,
+			Distance:        r.Distance,
+			WorkoutID:       r.WorkoutID,
+			Date:            r.Date,
+			StartIndex:      r.StartIndex,
+			EndIndex:        r.EndIndex,
+		}
+	}
+
+	return result
+}
+
+// NewWorkoutEffortRecordResponses converts the ranked best efforts of a
+// workout to API responses
+func NewWorkoutEffortRecordResponses(records []model.WorkoutEffortRecordWithRank) []WorkoutEffortRecordResponse {
+	if len(records) == 0 {
+		return nil
+	}
+
+	result := make([]WorkoutEffortRecordResponse, len(records))
+
+	for i, r := range records {
+		result[i] = WorkoutEffortRecordResponse{
+			Metric:          string(r.Metric),
+			Label:           r.Label,
+			DurationSeconds: r.DurationSeconds,
+			Value:           r.Value,
+			Distance:        r.Distance,
+			StartIndex:      r.StartIndex,
+			EndIndex:        r.EndIndex,
+			Rank:            r.Rank,
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,79 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// HeartRateZonesResponse is the time spent in each of the heart rate zones of
+// the user
+type HeartRateZonesResponse struct {
+	Model   string              `json:"model"`   // The zone model: "" (heart rate reserve), "percent-max" or "threshold"
+	Ranges  []ZoneRangeResponse `json:"ranges"`  // The bounds of the zones, in bpm
+	Seconds []float64           `json:"seconds"` // The time spent in each zone
+}
+
+// WeeklyHeartRateZonesResponse is the time spent in each of the heart rate
+// zones in all workouts of a week
+type WeeklyHeartRateZonesResponse struct {
+	Week    string    `json:"week"`    // The first day of the week, as YYYY-MM-DD
+	Seconds []float64 `json:"seconds"` // The time spent in each zone
+}
+
+// NewHeartRateZonesResponse returns the time spent in each of the zones, or
+// nil when there is none
+func NewHeartRateZonesResponse(zones model.HeartRateZones, durations []time.Duration) *HeartRateZonesResponse {
+	if len(durations) == 0 {
+		return nil
+	}
+
+	return &HeartRateZonesResponse{
+		Model:   string(zones.Model),
+		Ranges:  NewHeartRateZoneRanges(zones),
+		Seconds: durationSeconds(durations),
+	}
+}
+
+// NewWeeklyHeartRateZonesResponses converts the weekly time in the heart rate
+// zones to API responses
+func NewWeeklyHeartRateZonesResponses(weeks []model.WeeklyHeartRateZones) []WeeklyHeartRateZonesResponse {
+	if len(weeks) == 0 {
+		return nil
+	}
+
+	resp := make([]WeeklyHeartRateZonesResponse, len(weeks))
+	for i, w := range weeks {
+		resp[i] = WeeklyHeartRateZonesResponse{
+			Week:    w.Week.Format("2006-01-02"),
+			Seconds: durationSeconds(w.Durations),
+		}
+	}
+
+	return resp
+}
+
+// SetBreakdownHeartRateZones sets the time spent in each of the heart rate
+// zones of the breakdown items, from the points between their indexes
+func SetBreakdownHeartRateZones(items []WorkoutBreakdownItemResponse, details *model.MapDataDetails, zones model.HeartRateZones) {
+	if details == nil {
+		return
+	}
+
+	for i := range items {
+		items[i].HeartRateZones = durationSeconds(details.TimeInHeartRateZones(zones, items[i].StartIndex, items[i].EndIndex))
+	}
+}
+
+func durationSeconds(durations []time.Duration) []float64 {
+	if len(durations) == 0 {
+		return nil
+	}
+
+	seconds := make([]float64, len(durations))
+	for i, d := range durations {
+		seconds[i] = d.Seconds()
+	}
+
+	return seconds
+}
@@ -0,0 +1,64 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// WorkoutHRVResponse represents the heart-rate variability of a workout,
+// calculated from the beat-to-beat (RR) intervals
+type WorkoutHRVResponse struct {
+	Beats     int     `json:"beats"`
+	Artifacts int     `json:"artifacts"`
+	MeanRR    float64 `json:"mean_rr"`    // In ms
+	HeartRate float64 `json:"heart_rate"` // In bpm
+	RMSSD     float64 `json:"rmssd"`      // In ms
+	SDNN      float64 `json:"sdnn"`       // In ms
+	DFAAlpha1 float64 `json:"dfa_alpha1"`
+	Window    int64   `json:"window"` // The length of the windows, in seconds
+
+	Windows []WorkoutHRVWindowResponse `json:"windows"`
+}
+
+// WorkoutHRVWindowResponse represents the heart-rate variability of a window
+// of the workout
+type WorkoutHRVWindowResponse struct {
+	Start     time.Time `json:"start"`
+	Offset    float64   `json:"offset"` // Seconds since the start of the workout
+	Beats     int       `json:"beats"`
+	Artifacts int       `json:"artifacts"`
+	HeartRate float64   `json:"heart_rate"`
+	RMSSD     float64   `json:"rmssd"`
+	SDNN      float64   `json:"sdnn"`
+	DFAAlpha1 *float64  `json:"dfa_alpha1,omitempty"` // Omitted when the window has too few beats
+}
+
+func NewWorkoutHRVResponse(a *model.HRVAnalysis, window time.Duration) WorkoutHRVResponse {
+	resp := WorkoutHRVResponse{
+		Beats:     a.Beats,
+		Artifacts: a.Artifacts,
+		MeanRR:    a.MeanRR,
+		HeartRate: a.HeartRate,
+		RMSSD:     a.RMSSD,
+		SDNN:      a.SDNN,
+		DFAAlpha1: a.DFAAlpha1,
+		Window:    int64(window.Seconds()),
+		Windows:   make([]WorkoutHRVWindowResponse, 0, len(a.Windows)),
+	}
+
+	for _, w := range a.Windows {
+		resp.Windows = append(resp.Windows, WorkoutHRVWindowResponse{
+			Start:     w.Start,
+			Offset:    w.Offset.Seconds(),
+			Beats:     w.Beats,
+			Artifacts: w.Artifacts,
+			HeartRate: w.HeartRate,
+			RMSSD:     w.RMSSD,
+			SDNN:      w.SDNN,
+			DFAAlpha1: optionalMetric(w.DFAAlpha1),
+		})
+	}
+
+	return resp
+}
@@ -0,0 +1,54 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// ImportPreviewItem represents a workout detected in an upload, before it is
+// created
+type ImportPreviewItem struct {
+	FileIndex     int       `json:"file_index"` // The index of the uploaded file
+	Index         int       `json:"index"`      // The index of the workout in the file
+	Filename      string    `json:"filename"`
+	Name          string    `json:"name"`
+	Date          time.Time `json:"date"`
+	Type          string    `json:"type"`
+	SubType       string    `json:"sub_type,omitempty"`
+	TotalDistance float64   `json:"total_distance"` // In meters
+	TotalDuration int64     `json:"total_duration"` // In seconds
+	Multisport    bool      `json:"multisport"`     // Whether the workout is a leg of a multisport activity
+	Duplicate     bool      `json:"duplicate"`      // Whether the workout collides with an existing workout or an earlier one of the upload
+}
+
+// ImportSelection is a previewed workout the user confirmed, with the values
+// that replace the parsed or default ones
+type ImportSelection struct {
+	FileIndex    int                      `json:"file_index"`
+	Index        int                      `json:"index"`
+	Name         *string                  `json:"name"`
+	Type         *model.WorkoutType       `json:"type"`
+	Visibility   *model.WorkoutVisibility `json:"visibility"`
+	EquipmentIDs *[]uint64                `json:"equipment_ids"` // Nil keeps the default equipment, empty removes it
+}
+
+func NewImportPreviewItem(fileIndex, index int, filename string, w *model.Workout) ImportPreviewItem {
+	item := ImportPreviewItem{
+		FileIndex:  fileIndex,
+		Index:      index,
+		Filename:   filename,
+		Name:       w.Name,
+		Date:       w.Date,
+		Type:       string(w.Type),
+		Multisport: w.Multisport != nil,
+	}
+
+	if w.Data != nil {
+		item.SubType = w.Data.SubType
+		item.TotalDistance = w.Data.TotalDistance
+		item.TotalDuration = int64(w.Data.TotalDuration.Seconds())
+	}
+
+	return item
+}
@@ -16,6 +16,11 @@ type MeasurementResponse struct {
 	FTP              *float64  `json:"ftp,omitempty"`
 	RestingHeartRate *float64  `json:"resting_heart_rate,omitempty"`
 	MaxHeartRate     *float64  `json:"max_heart_rate,omitempty"`
+	LTHR             *float64  `json:"lthr,omitempty"`        // Lactate threshold heart rate
+	BodyFat          *float64  `json:"body_fat,omitempty"`    // In percent
+	MuscleMass       *float64  `json:"muscle_mass,omitempty"` // In kg
+	BoneMass         *float64  `json:"bone_mass,omitempty"`   // In kg
+	BodyWater        *float64  `json:"body_water,omitempty"`  // In percent
 	UserID           uint64    `json:"user_id"`
 	CreatedAt        time.Time `json:"created_at"`
 	UpdatedAt        time.Time `json:"updated_at"`
@@ -55,6 +60,26 @@ func NewMeasurementResponse(m *model.Measurement) MeasurementResponse {
 		mhr := m.MaxHeartRate
 		mr.MaxHeartRate = &mhr
 	}
+	if m.LTHR != 0 {
+		lthr := m.LTHR
+		mr.LTHR = &lthr
+	}
+	if m.BodyFat != 0 {
+		bf := m.BodyFat
+		mr.BodyFat = &bf
+	}
+	if m.MuscleMass != 0 {
+		mm := m.MuscleMass
+		mr.MuscleMass = &mm
+	}
+	if m.BoneMass != 0 {
+		bm := m.BoneMass
+		mr.BoneMass = &bm
+	}
+	if m.BodyWater != 0 {
+		bw := m.BodyWater
+		mr.BodyWater = &bw
+	}
 
 	return mr
 }
@@ -0,0 +1,67 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// MultisportResponse represents a multisport activity (e.g. a triathlon) in
+// API v2 responses, with the combined totals and the stats of every leg
+type MultisportResponse struct {
+	ID                 uint64                         `json:"id"`
+	Name               string                         `json:"name"`
+	Date               time.Time                      `json:"date"`
+	UserID             uint64                         `json:"user_id"`
+	TotalDistance      float64                        `json:"total_distance"`      // Legs and transitions, in meters
+	TotalDuration      int64                          `json:"total_duration"`      // Legs and transitions, in seconds
+	TransitionDuration int64                          `json:"transition_duration"` // In seconds
+	Legs               []WorkoutResponse              `json:"legs"`
+	Transitions        []MultisportTransitionResponse `json:"transitions"`
+	CreatedAt          time.Time                      `json:"created_at"`
+	UpdatedAt          time.Time                      `json:"updated_at"`
+}
+
+// MultisportTransitionResponse represents the transition between two legs
+type MultisportTransitionResponse struct {
+	Start    time.Time `json:"start"`
+	Duration int64     `json:"duration"` // In seconds
+	Distance float64   `json:"distance"`
+}
+
+// NewMultisportResponse converts a database multisport activity to API response
+func NewMultisportResponse(m *model.Multisport) MultisportResponse {
+	r := MultisportResponse{
+		ID:                 m.ID,
+		Name:               m.Name,
+		Date:               m.Date,
+		UserID:             m.UserID,
+		TotalDistance:      m.TotalDistance(),
+		TotalDuration:      int64(m.TotalDuration().Seconds()),
+		TransitionDuration: int64(m.TransitionDuration().Seconds()),
+		Legs:               NewWorkoutsResponse(m.Legs),
+		Transitions:        make([]MultisportTransitionResponse, 0, len(m.Transitions)),
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+
+	for _, t := range m.Transitions {
+		r.Transitions = append(r.Transitions, MultisportTransitionResponse{
+			Start:    t.Start,
+			Duration: int64(t.Duration.Seconds()),
+			Distance: t.Distance,
+		})
+	}
+
+	return r
+}
+
+// NewMultisportsResponse converts a list of multisport activities to API responses
+func NewMultisportsResponse(multisports []*model.Multisport) []MultisportResponse {
+	results := make([]MultisportResponse, len(multisports))
+	for i, m := range multisports {
+		results[i] = NewMultisportResponse(m)
+	}
+
+	return results
+}
@@ -26,11 +26,23 @@ type ManualWorkout struct {
 	Notes           *string                  `form:"notes" json:"notes"`
 	Type            *model.WorkoutType       `form:"type" json:"type"`
 	CustomType      *string                  `form:"custom_type" json:"custom_type"`
+	Commute         *bool                    `form:"commute" json:"commute"`
 	EquipmentIDs    []uint64                 `form:"equipment_ids" json:"equipment_ids"`
+	Sets            *[]ManualWorkoutSet      `form:"-" json:"sets"`
 
 	Units *model.UserPreferredUnits `json:"-" form:"-"`
 }
 
+// ManualWorkoutSet is a single set of a manual strength workout
+type ManualWorkoutSet struct {
+	Exercise    string  `json:"exercise"`    // The name of the exercise, e.g. barbell_bench_press
+	Repetitions int     `json:"repetitions"` // The number of repetitions
+	Weight      float64 `json:"weight"`      // The weight, in the preferred weight unit
+	Duration    int     `json:"duration"`    // The duration of the set, in seconds
+	Rest        int     `json:"rest"`        // The rest after the set, in seconds
+	RPE         float64 `json:"rpe"`         // The rate of perceived exertion, from 1 to 10
+}
+
 func (m *ManualWorkout) ToDate() *time.Time {
 	if m.Date == nil {
 		return nil
@@ -74,6 +86,55 @@ func (m *ManualWorkout) ToWeight() *float64 {
 	return &d
 }
 
+// ToSets converts the sets to the database format; the start of each set
+// follows the previous set and its rest, from the start of the workout
+func (m *ManualWorkout) ToSets(start time.Time) ([]model.WorkoutSet, error) {
+	if m.Sets == nil {
+		return nil, nil
+	}
+
+	unit := "kg"
+	if m.Units != nil {
+		unit = m.Units.Weight()
+	}
+
+	sets := make([]model.WorkoutSet, 0, len(*m.Sets))
+
+	for _, s := range *m.Sets {
+		name := model.NormalizeExerciseName(s.Exercise)
+		if name == "" {
+			return nil, errors.New("exercise is required for every set")
+		}
+
+		if s.Repetitions < 0 || s.Weight < 0 || s.Duration < 0 || s.Rest < 0 {
+			return nil, errors.New("invalid set")
+		}
+
+		if s.RPE < 0 || s.RPE > 10 {
+			return nil, errors.New("invalid set RPE")
+		}
+
+		set := model.WorkoutSet{
+			Exercise:    &model.Exercise{Name: name},
+			Start:       start,
+			Duration:    time.Duration(s.Duration) * time.Second,
+			Rest:        time.Duration(s.Rest) * time.Second,
+			Repetitions: s.Repetitions,
+			RPE:         s.RPE,
+		}
+
+		if s.Weight > 0 {
+			set.Weight = templatehelpers.WeightToDatabase(s.Weight, unit)
+		}
+
+		start = start.Add(set.Duration + set.Rest)
+
+		sets = append(sets, set)
+	}
+
+	return sets, nil
+}
+
 func (m *ManualWorkout) ToDistance() *float64 {
 	if m.Distance == nil || *m.Distance == 0 {
 		return nil
@@ -127,6 +188,7 @@ func (m *ManualWorkout) Update(w *model.Workout) error {
 	setIfNotNil(&w.Visibility, m.Visibility)
 	setIfNotNil(&w.Type, m.Type)
 	setIfNotNil(&w.CustomType, m.CustomType)
+	setIfNotNil(&w.Commute, m.Commute)
 
 	setIfNotNil(&w.Data.AddressString, m.Location)
 	setIfNotNil(&w.Data.TotalDistance, m.ToDistance())
@@ -134,6 +196,26 @@ func (m *ManualWorkout) Update(w *model.Workout) error {
 	setIfNotNil(&w.Data.TotalRepetitions, m.Repetitions)
 	setIfNotNil(&w.Data.TotalWeight, m.ToWeight())
 
+	if m.Sets != nil {
+		sets, err := m.ToSets(w.Date)
+		if err != nil {
+			return err
+		}
+
+		w.Data.Sets = sets
+
+		// Totals that were not given, follow the sets
+		if m.Repetitions == nil {
+			w.Data.TotalRepetitions = 0
+		}
+
+		if m.ToWeight() == nil {
+			w.Data.TotalWeight = 0
+		}
+
+		w.Data.UpdateSetTotals()
+	}
+
 	if m.Location != nil && w.FullAddress() != *m.Location {
 		a, err := geocoder.Find(*m.Location)
 		if err != nil {
@@ -159,6 +241,11 @@ type Measurement struct {
 	FTP              float64 `form:"ftp" json:"ftp"`
 	RestingHeartRate float64 `form:"resting_heart_rate" json:"resting_heart_rate"`
 	MaxHeartRate     float64 `form:"max_heart_rate" json:"max_heart_rate"`
+	LTHR             float64 `form:"lthr" json:"lthr"`
+	BodyFat          float64 `form:"body_fat" json:"body_fat"`
+	MuscleMass       float64 `form:"muscle_mass" json:"muscle_mass"`
+	BoneMass         float64 `form:"bone_mass" json:"bone_mass"`
+	BodyWater        float64 `form:"body_water" json:"body_water"`
 
 	Units *model.UserPreferredUnits `json:"-" form:"-"`
 }
@@ -212,6 +299,59 @@ func (m *Measurement) ToMaxHeartRate() *float64 {
 	return &d
 }
 
+func (m *Measurement) ToLTHR() *float64 {
+	if m.LTHR == 0 {
+		return nil
+	}
+
+	d := m.LTHR
+	return &d
+}
+
+func (m *Measurement) ToBodyFat() *float64 {
+	if m.BodyFat == 0 {
+		return nil
+	}
+
+	d := m.BodyFat
+	return &d
+}
+
+func (m *Measurement) ToBodyWater() *float64 {
+	if m.BodyWater == 0 {
+		return nil
+	}
+
+	d := m.BodyWater
+	return &d
+}
+
+func (m *Measurement) ToMuscleMass() *float64 {
+	return m.toMass(m.MuscleMass)
+}
+
+func (m *Measurement) ToBoneMass() *float64 {
+	return m.toMass(m.BoneMass)
+}
+
+// toMass converts a mass in the weight unit to kilograms
+func (m *Measurement) toMass(v float64) *float64 {
+	if v == 0 {
+		return nil
+	}
+
+	if m.WeightUnit == "" {
+		if m.Units != nil {
+			m.WeightUnit = m.Units.Weight()
+		} else {
+			m.WeightUnit = "kg"
+		}
+	}
+
+	d := templatehelpers.WeightToDatabase(v, m.WeightUnit)
+	return &d
+}
+
 func (m *Measurement) ToHeight() *float64 {
 	if m.Height == 0 {
 		return nil
@@ -253,6 +393,11 @@ func (m *Measurement) Update(measurement *model.Measurement) {
 	setIfNotNil(&measurement.FTP, m.ToFTP())
 	setIfNotNil(&measurement.RestingHeartRate, m.ToRestingHeartRate())
 	setIfNotNil(&measurement.MaxHeartRate, m.ToMaxHeartRate())
+	setIfNotNil(&measurement.LTHR, m.ToLTHR())
+	setIfNotNil(&measurement.BodyFat, m.ToBodyFat())
+	setIfNotNil(&measurement.MuscleMass, m.ToMuscleMass())
+	setIfNotNil(&measurement.BoneMass, m.ToBoneMass())
+	setIfNotNil(&measurement.BodyWater, m.ToBodyWater())
 }
 
 func setIfNotNil[T any](dst *T, src *T) {
@@ -293,6 +438,9 @@ type ProfileUpdateData struct {
 	DefaultWorkoutVisibility model.WorkoutVisibility  `json:"default_workout_visibility"`
 	APIActive                bool                     `json:"api_active"`
 	PreferFullDate           bool                     `json:"prefer_full_date"`
+	CleanGPSTracks           bool                     `json:"clean_gps_tracks"`
+	SmoothGPSTracks          bool                     `json:"smooth_gps_tracks"`
+	HeartRateZoneModel       string                   `json:"heart_rate_zone_model"`
 }
 
 type CalendarQueryParams struct {
@@ -0,0 +1,28 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// PowerZonesResponse is the time spent in each of the power zones of the user
+type PowerZonesResponse struct {
+	FTP     float64             `json:"ftp"`     // The functional threshold power the zones are derived from
+	Ranges  []ZoneRangeResponse `json:"ranges"`  // The bounds of the zones, in watts
+	Seconds []float64           `json:"seconds"` // The time spent in each zone
+}
+
+// NewPowerZonesResponse returns the time spent in each of the zones, or nil
+// when there are no zones or no time
+func NewPowerZonesResponse(zones *model.PowerZones, durations []time.Duration) *PowerZonesResponse {
+	if zones == nil || len(durations) == 0 {
+		return nil
+	}
+
+	return &PowerZonesResponse{
+		FTP:     zones.FTP,
+		Ranges:  NewPowerZoneRanges(*zones),
+		Seconds: durationSeconds(durations),
+	}
+}
@@ -34,6 +34,7 @@ type WorkoutRecordResponse struct {
 	TotalUp             *RecordResponse          `json:"total_up,omitempty"`
 	DistanceRecords     []DistanceRecordResponse `json:"distance_records,omitempty"`
 	BiggestClimb        *ClimbRecordResponse     `json:"biggest_climb,omitempty"`
+	ExerciseRecords     []ExerciseRecordResponse `json:"exercise_records,omitempty"`
 }
 
 // DistanceRecordResponse represents a distance effort record
@@ -154,6 +155,8 @@ func NewWorkoutRecordResponse(wr *model.WorkoutRecord) WorkoutRecordResponse {
 		}
 	}
 
+	response.ExerciseRecords = NewExerciseRecordResponses(wr.ExerciseRecords)
+
 	return response
 }
 
@@ -9,6 +9,8 @@ type StatisticsResponse struct {
 	UserID       uint                        `json:"user_id"`
 	BucketFormat string                      `json:"bucket_format"`
 	Buckets      map[string]StatisticBuckets `json:"buckets"`
+
+	HeartRateZones []WeeklyHeartRateZonesResponse `json:"heart_rate_zones,omitempty"` // The time spent in each heart rate zone per week
 }
 
 // StatisticBuckets represents statistics grouped by workout type
@@ -61,5 +63,7 @@ func NewStatisticsResponse(stats *model.Statistics) StatisticsResponse {
 		UserID:       uint(stats.UserID),
 		BucketFormat: stats.BucketFormat,
 		Buckets:      buckets,
+
+		HeartRateZones: NewWeeklyHeartRateZonesResponses(stats.HeartRateZones),
 	}
 }
@@ -0,0 +1,142 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// ExerciseResponse represents an exercise of the catalogue
+type ExerciseResponse struct {
+	ID       uint64 `json:"id"`
+	Name     string `json:"name"`
+	Category string `json:"category,omitempty"`
+}
+
+// WorkoutSetResponse represents a single set of a strength workout
+type WorkoutSetResponse struct {
+	Exercise           string    `json:"exercise"`
+	Category           string    `json:"category,omitempty"`
+	Start              time.Time `json:"start"`
+	Duration           float64   `json:"duration"` // In seconds
+	Rest               float64   `json:"rest"`     // In seconds
+	Repetitions        int       `json:"repetitions"`
+	Weight             float64   `json:"weight"`                          // In kg
+	RPE                float64   `json:"rpe,omitempty"`                   // From 1 to 10
+	Volume             float64   `json:"volume"`                          // Weight times repetitions, in kg
+	EstimatedOneRepMax float64   `json:"estimated_one_rep_max,omitempty"` // In kg
+}
+
+// ExerciseEffortResponse represents the sets of one exercise in a workout
+type ExerciseEffortResponse struct {
+	Exercise    string    `json:"exercise"`
+	Category    string    `json:"category,omitempty"`
+	WorkoutID   uint64    `json:"workout_id"`
+	Date        time.Time `json:"date"`
+	Sets        int       `json:"sets"`
+	Repetitions int       `json:"repetitions"`
+	MaxWeight   float64   `json:"max_weight"`  // In kg
+	OneRepMax   float64   `json:"one_rep_max"` // Estimated, in kg
+	Volume      float64   `json:"volume"`      // In kg
+}
+
+// ExerciseRecordResponse represents the records of a strength exercise
+type ExerciseRecordResponse struct {
+	Exercise  string          `json:"exercise"`
+	Category  string          `json:"category,omitempty"`
+	OneRepMax *RecordResponse `json:"one_rep_max,omitempty"` // Estimated, in kg
+	MaxWeight *RecordResponse `json:"max_weight,omitempty"`  // In kg
+	Volume    *RecordResponse `json:"volume,omitempty"`      // In a single workout, in kg
+}
+
+func NewExerciseResponses(exercises []*model.Exercise) []ExerciseResponse {
+	results := make([]ExerciseResponse, len(exercises))
+	for i, e := range exercises {
+		results[i] = ExerciseResponse{
+			ID:       e.ID,
+			Name:     e.Name,
+			Category: e.Category,
+		}
+	}
+
+	return results
+}
+
+func NewWorkoutSetResponses(sets []model.WorkoutSet) []WorkoutSetResponse {
+	if len(sets) == 0 {
+		return nil
+	}
+
+	results := make([]WorkoutSetResponse, len(sets))
+	for i := range sets {
+		s := &sets[i]
+
+		results[i] = WorkoutSetResponse{
+			Exercise:           s.ExerciseName(),
+			Start:              s.Start,
+			Duration:           s.Duration.Seconds(),
+			Rest:               s.Rest.Seconds(),
+			Repetitions:        s.Repetitions,
+			Weight:             s.Weight,
+			RPE:                s.RPE,
+			Volume:             s.Volume(),
+			EstimatedOneRepMax: s.EstimatedOneRepMax(),
+		}
+
+		if s.Exercise != nil {
+			results[i].Category = s.Exercise.Category
+		}
+	}
+
+	return results
+}
+
+func NewExerciseEffortResponses(efforts []model.ExerciseEffort) []ExerciseEffortResponse {
+	results := make([]ExerciseEffortResponse, len(efforts))
+	for i, e := range efforts {
+		results[i] = ExerciseEffortResponse{
+			Exercise:    e.Exercise,
+			Category:    e.Category,
+			WorkoutID:   e.WorkoutID,
+			Date:        e.Date,
+			Sets:        e.Sets,
+			Repetitions: e.Repetitions,
+			MaxWeight:   e.MaxWeight,
+			OneRepMax:   e.OneRepMax,
+			Volume:      e.Volume,
+		}
+	}
+
+	return results
+}
+
+func NewExerciseRecordResponses(records []model.ExerciseRecord) []ExerciseRecordResponse {
+	if len(records) == 0 {
+		return nil
+	}
+
+	results := make([]ExerciseRecordResponse, len(records))
+	for i, r := range records {
+		results[i] = ExerciseRecordResponse{
+			Exercise:  r.Exercise,
+			Category:  r.Category,
+			OneRepMax: newFloat64RecordResponse(r.OneRepMax),
+			MaxWeight: newFloat64RecordResponse(r.MaxWeight),
+			Volume:    newFloat64RecordResponse(r.Volume),
+		}
+	}
+
+	return results
+}
+
+func newFloat64RecordResponse(r model.Float64Record) *RecordResponse {
+	if r.Value == 0 {
+		return nil
+	}
+
+	return &RecordResponse{
+		Value:     r.Value,
+		WorkoutID: r.ID,
+		Date:      r.Date,
+	}
+}
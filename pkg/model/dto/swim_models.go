@@ -0,0 +1,118 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// SwimSummaryResponse represents the totals of the lengths of a pool swim
+type SwimSummaryResponse struct {
+	PoolLength       float64 `json:"pool_length"` // In meters
+	Lengths          int     `json:"lengths"`
+	Intervals        int     `json:"intervals"`
+	Distance         float64 `json:"distance"` // In meters
+	Duration         float64 `json:"duration"` // Without rest, in seconds
+	Rest             float64 `json:"rest"`     // In seconds
+	Strokes          int     `json:"strokes"`
+	AverageSWOLF     float64 `json:"average_swolf"`
+	StrokeRate       float64 `json:"stroke_rate"`        // Strokes per minute
+	StrokesPerLength float64 `json:"strokes_per_length"` // Average number of strokes per length
+	SwimPace         float64 `json:"swim_pace"`          // Seconds per 100 meters or yards
+	SwimPaceUnit     string  `json:"swim_pace_unit"`     // The unit of the swim pace, e.g. "min/100m"
+}
+
+func NewSwimSummaryResponse(s model.SwimSummary, units *model.UserPreferredUnits) *SwimSummaryResponse {
+	if units == nil {
+		units = &model.UserPreferredUnits{}
+	}
+
+	return &SwimSummaryResponse{
+		PoolLength:       s.PoolLength,
+		Lengths:          s.Lengths,
+		Intervals:        s.Intervals,
+		Distance:         s.Distance,
+		Duration:         s.Duration.Seconds(),
+		Rest:             s.Rest.Seconds(),
+		Strokes:          s.Strokes,
+		AverageSWOLF:     s.AverageSWOLF,
+		StrokeRate:       s.StrokeRate,
+		StrokesPerLength: s.StrokesLength,
+		SwimPace:         model.SwimPace(s.Distance, s.Duration, units).Seconds(),
+		SwimPaceUnit:     units.SwimPace(),
+	}
+}
+
+// NewWorkoutBreakdownItemsFromLengths converts the lengths of a pool swim,
+// including the rests, to breakdown items
+func NewWorkoutBreakdownItemsFromLengths(lengths []model.WorkoutLength, points []model.MapPoint, units *model.UserPreferredUnits) []WorkoutBreakdownItemResponse {
+	if len(lengths) == 0 {
+		return nil
+	}
+
+	items := make([]WorkoutBreakdownItemResponse, len(lengths))
+
+	for i := range lengths {
+		l := &lengths[i]
+
+		items[i] = newSwimBreakdownItem(l.Start, l.Duration, l.Distance, points, units)
+		items[i].Lengths = 1
+		items[i].Stroke = l.Stroke
+		items[i].Strokes = l.Strokes
+		items[i].SWOLF = l.SWOLF()
+		items[i].StrokeRate = l.StrokeRate()
+
+		if l.IsRest() {
+			items[i].Rest = l.Duration.Seconds()
+		}
+	}
+
+	return items
+}
+
+// NewWorkoutBreakdownItemsFromSwimIntervals converts the intervals of a pool
+// swim to breakdown items
+func NewWorkoutBreakdownItemsFromSwimIntervals(intervals []model.SwimInterval, points []model.MapPoint, units *model.UserPreferredUnits) []WorkoutBreakdownItemResponse {
+	if len(intervals) == 0 {
+		return nil
+	}
+
+	items := make([]WorkoutBreakdownItemResponse, len(intervals))
+
+	for i := range intervals {
+		iv := &intervals[i]
+
+		items[i] = newSwimBreakdownItem(iv.Start, iv.Duration, iv.Distance, points, units)
+		items[i].Lengths = iv.Lengths
+		items[i].Stroke = iv.Stroke
+		items[i].Strokes = iv.Strokes
+		items[i].SWOLF = iv.SWOLF
+		items[i].StrokeRate = iv.StrokeRate()
+		items[i].Rest = iv.Rest.Seconds()
+	}
+
+	return items
+}
+
+func newSwimBreakdownItem(start time.Time, duration time.Duration, distance float64, points []model.MapPoint, units *model.UserPreferredUnits) WorkoutBreakdownItemResponse {
+	item := WorkoutBreakdownItemResponse{
+		StartIndex: findClosestPointIndex(points, start),
+		EndIndex:   findClosestPointIndex(points, start.Add(duration)),
+		Distance:   convertDistanceToPreferred(distance, units),
+		Duration:   duration.Seconds(),
+		SwimPace:   model.SwimPace(distance, duration, units).Seconds(),
+	}
+
+	if item.Distance > 0 {
+		item.AveragePace = item.Duration / item.Distance
+	}
+
+	if duration > 0 {
+		speed := distance / duration.Seconds()
+		item.AverageSpeed = convertSpeedToPreferred(speed, units)
+		item.AverageSpeedNoPause = item.AverageSpeed
+		item.MaxSpeed = item.AverageSpeed
+	}
+
+	return item
+}
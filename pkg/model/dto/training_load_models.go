@@ -0,0 +1,34 @@
+package dto
+
+import (
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// TrainingLoadResponse is the training load of a user on a day, with their
+// fitness, fatigue and form
+type TrainingLoadResponse struct {
+	Date    string  `json:"date"`    // The day, as YYYY-MM-DD
+	Load    float64 `json:"load"`    // The training load of the workouts of the day
+	Fitness float64 `json:"fitness"` // The chronic training load (CTL)
+	Fatigue float64 `json:"fatigue"` // The acute training load (ATL)
+	Form    float64 `json:"form"`    // The training stress balance (TSB)
+}
+
+// NewTrainingLoadResponses converts the training load per day to API
+// responses
+func NewTrainingLoadResponses(loads []model.TrainingLoad) []TrainingLoadResponse {
+	resp := make([]TrainingLoadResponse, len(loads))
+	for i, l := range loads {
+		resp[i] = TrainingLoadResponse{
+			Date:    time.Time(l.Date).Format("2006-01-02"),
+			Load:    l.Load,
+			Fitness: l.Fitness,
+			Fatigue: l.Fatigue,
+			Form:    l.Form,
+		}
+	}
+
+	return resp
+}
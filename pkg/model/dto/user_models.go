@@ -41,6 +41,9 @@ type ProfileSettings struct {
 	APIActive                bool                     `json:"api_active"`
 	APIKey                   string                   `json:"api_key,omitempty"` // #nosec G117 -- API response key is intentionally named api_key
 	PreferFullDate           bool                     `json:"prefer_full_date"`
+	CleanGPSTracks           bool                     `json:"clean_gps_tracks"`
+	SmoothGPSTracks          bool                     `json:"smooth_gps_tracks"`
+	HeartRateZoneModel       string                   `json:"heart_rate_zone_model"` // "" (heart rate reserve), "percent-max" or "threshold"
 }
 
 // AppInfoResponse represents application info in API v2 responses
@@ -96,6 +99,9 @@ func NewUserProfileResponse(u *model.User) UserProfileResponse {
 			DefaultWorkoutVisibility: u.Profile.EffectiveDefaultWorkoutVisibility(),
 			APIActive:                u.Profile.APIActive,
 			PreferFullDate:           u.Profile.PreferFullDate,
+			CleanGPSTracks:           u.Profile.CleanGPSTracks,
+			SmoothGPSTracks:          u.Profile.SmoothGPSTracks,
+			HeartRateZoneModel:       string(u.Profile.HeartRateZoneModel),
 		},
 	}
 
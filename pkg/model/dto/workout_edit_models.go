@@ -0,0 +1,40 @@
+package dto
+
+// WorkoutPointRangeRequest is an inclusive range of point indexes of a
+// workout, as returned in its map data details
+type WorkoutPointRangeRequest struct {
+	StartIndex int `json:"start_index"`
+	EndIndex   int `json:"end_index"`
+}
+
+// WorkoutSplitRequest is the index of the point where the second workout
+// starts
+type WorkoutSplitRequest struct {
+	Index int `json:"index"`
+}
+
+// ElevationCorrectionRequest is how the elevation of workouts is corrected
+// with the elevation model: "" (not), "replace" or "blend"
+type ElevationCorrectionRequest struct {
+	Mode string `json:"mode"`
+}
+
+// WorkoutTimeShiftRequest is how the timestamps of a workout are repaired:
+// shifted by the offset, or, when the zone is set, re-interpreted as the
+// local time of the zone
+type WorkoutTimeShiftRequest struct {
+	OffsetSeconds int64  `json:"offset_seconds"`
+	Zone          string `json:"zone"`
+}
+
+// WorkoutsTimeShiftRequest repairs the timestamps of several workouts
+type WorkoutsTimeShiftRequest struct {
+	WorkoutTimeShiftRequest
+	WorkoutIDs []uint64 `json:"workout_ids"`
+}
+
+// WorkoutTimezoneRequest is the timezone of a workout; empty restores the
+// timezone of its location
+type WorkoutTimezoneRequest struct {
+	Timezone string `json:"timezone"`
+}
@@ -23,6 +23,7 @@ type WorkoutResponse struct {
 	User                 *UserProfileResponse    `json:"user,omitempty"`
 	Visibility           model.WorkoutVisibility `json:"visibility,omitempty"`
 	Locked               bool                    `json:"locked"`
+	Commute              bool                    `json:"commute"`
 	CreatedAt            time.Time               `json:"created_at"`
 	UpdatedAt            time.Time               `json:"updated_at"`
 	HasFile              bool                    `json:"has_file"`
@@ -32,6 +33,7 @@ type WorkoutResponse struct {
 	LikedByMe            bool                    `json:"liked_by_me"`
 	RepliesCount         int64                   `json:"replies_count"`
 	Attachments          []WorkoutAttachmentItem `json:"attachments,omitempty"`
+	MultisportID         *uint64                 `json:"multisport_id,omitempty"` // The multisport activity this workout is a leg of
 
 	// MapData fields (when available)
 	AddressString       string   `json:"address_string,omitempty"`
@@ -53,6 +55,15 @@ type WorkoutResponse struct {
 	MaxHeartRate        *float64 `json:"max_heart_rate,omitempty"`
 	AveragePower        *float64 `json:"average_power,omitempty"`
 	MaxPower            *float64 `json:"max_power,omitempty"`
+	NormalizedPower     *float64 `json:"normalized_power,omitempty"`
+	VariabilityIndex    *float64 `json:"variability_index,omitempty"`
+	IntensityFactor     *float64 `json:"intensity_factor,omitempty"`
+	TrainingStressScore *float64 `json:"training_stress_score,omitempty"`
+	GradeAdjustedSpeed  *float64 `json:"grade_adjusted_speed,omitempty"` // The average speed while moving, adjusted for the slope, in m/s
+	PaceDecoupling      *float64 `json:"pace_decoupling,omitempty"`      // The drop of grade-adjusted speed per heart beat from the first to the second half, in percent
+	PowerDecoupling     *float64 `json:"power_decoupling,omitempty"`     // The drop of power per heart beat from the first to the second half, in percent
+	TrainingLoad        *float64 `json:"training_load,omitempty"`
+	TrainingLoadMethod  string   `json:"training_load_method,omitempty"` // How the training load is derived: "tss", "trimp" or "met"
 }
 
 type WorkoutAttachmentItem struct {
@@ -83,11 +94,16 @@ type WorkoutLapResponse struct {
 	MaxHeartRate        float64   `json:"max_heart_rate"`
 	AveragePower        float64   `json:"average_power"`
 	MaxPower            float64   `json:"max_power"`
+	NormalizedPower     float64   `json:"normalized_power"`
+	GradeAdjustedSpeed  float64   `json:"grade_adjusted_speed"`
+	PaceDecoupling      float64   `json:"pace_decoupling"`
+	PowerDecoupling     float64   `json:"power_decoupling"`
 }
 
 type WorkoutBreakdownResponse struct {
-	Mode  string                         `json:"mode"` // "laps" or "unit"
-	Items []WorkoutBreakdownItemResponse `json:"items,omitempty"`
+	Mode         string                         `json:"mode"` // "laps", "unit", "lengths" or "intervals"
+	Items        []WorkoutBreakdownItemResponse `json:"items,omitempty"`
+	SwimPaceUnit string                         `json:"swim_pace_unit,omitempty"` // The unit of the swim pace of swims, e.g. "min/100m"
 }
 
 type WorkoutRangeStatsUnitsResponse struct {
@@ -136,13 +152,33 @@ type WorkoutRangeStatsResponse struct {
 	MinPower     *float64 `json:"min_power,omitempty"`
 	MaxPower     *float64 `json:"max_power,omitempty"`
 
+	NormalizedPower  *float64 `json:"normalized_power,omitempty"`
+	VariabilityIndex *float64 `json:"variability_index,omitempty"`
+
+	GradeAdjustedSpeed *float64 `json:"grade_adjusted_speed,omitempty"`
+	PaceDecoupling     *float64 `json:"pace_decoupling,omitempty"`
+	PowerDecoupling    *float64 `json:"power_decoupling,omitempty"`
+
 	AverageTemperature *float64 `json:"average_temperature,omitempty"`
 	MinTemperature     *float64 `json:"min_temperature,omitempty"`
 	MaxTemperature     *float64 `json:"max_temperature,omitempty"`
 
+	ExtraMetrics map[string]MetricStatsResponse `json:"extra_metrics,omitempty"`
+
+	HeartRateZones *HeartRateZonesResponse `json:"heart_rate_zones,omitempty"`
+	PowerZones     *PowerZonesResponse     `json:"power_zones,omitempty"`
+
 	Units WorkoutRangeStatsUnitsResponse `json:"units"`
 }
 
+// MetricStatsResponse are the statistics of an extra metric (e.g. from a FIT
+// developer field) over a range of points
+type MetricStatsResponse struct {
+	Average float64 `json:"average"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
 type WorkoutBreakdownItemResponse struct {
 	StartIndex int `json:"start_index"`
 	EndIndex   int `json:"end_index"`
@@ -169,6 +205,24 @@ type WorkoutBreakdownItemResponse struct {
 	AveragePower float64 `json:"average_power"`
 	MaxPower     float64 `json:"max_power"`
 
+	GradeAdjustedSpeed float64 `json:"grade_adjusted_speed"` // The average speed while moving, adjusted for the slope
+	GradeAdjustedPace  float64 `json:"grade_adjusted_pace"`  // seconds per preferred unit, adjusted for the slope
+	PaceDecoupling     float64 `json:"pace_decoupling"`      // The drop of grade-adjusted speed per heart beat from the first to the second half, in percent
+	PowerDecoupling    float64 `json:"power_decoupling"`     // The drop of power per heart beat from the first to the second half, in percent
+
+	ExtraMetrics map[string]MetricStatsResponse `json:"extra_metrics,omitempty"`
+
+	HeartRateZones []float64 `json:"heart_rate_zones,omitempty"` // The time spent in each of the heart rate zones, in seconds
+
+	// Swimming
+	Lengths    int     `json:"lengths,omitempty"`     // The number of lengths of a pool swim
+	Stroke     string  `json:"stroke,omitempty"`      // The swim stroke, or "mixed"
+	Strokes    int     `json:"strokes,omitempty"`     // The number of strokes
+	SWOLF      float64 `json:"swolf,omitempty"`       // The (average) SWOLF of the lengths
+	StrokeRate float64 `json:"stroke_rate,omitempty"` // Strokes per minute
+	SwimPace   float64 `json:"swim_pace,omitempty"`   // Seconds per 100 meters or yards
+	Rest       float64 `json:"rest,omitempty"`        // The rest after the interval, in seconds
+
 	IsBest  bool `json:"is_best"`
 	IsWorst bool `json:"is_worst"`
 }
@@ -181,7 +235,19 @@ type WorkoutDetailResponse struct {
 	Climbs              []ClimbSegmentResponse          `json:"climbs,omitempty"`
 	RouteSegmentMatches []RouteSegmentMatchResponse     `json:"route_segment_matches,omitempty"`
 	Records             []WorkoutIntervalRecordResponse `json:"records,omitempty"`
+	Efforts             []WorkoutEffortRecordResponse   `json:"efforts,omitempty"` // The best efforts by duration, with their rank
 	Laps                []WorkoutLapResponse            `json:"laps,omitempty"`
+	Swim                *SwimSummaryResponse            `json:"swim,omitempty"`
+	Sets                []WorkoutSetResponse            `json:"sets,omitempty"`
+	Exercises           []ExerciseEffortResponse        `json:"exercises,omitempty"`
+	DuplicateIDs        []uint64                        `json:"duplicate_ids,omitempty"` // The workouts that are probably other recordings of this workout
+	Edited              bool                            `json:"edited"`                  // Whether points of the file were removed; the edits can be reverted, unless the workout was split
+	Split               bool                            `json:"split"`                   // Whether the workout was split from or into another workout
+	ElevationCorrection string                          `json:"elevation_correction"`    // How the elevation is corrected with the elevation model: "", "replace" or "blend"
+	TimeOffsetSeconds   int64                           `json:"time_offset_seconds"`     // How much the timestamps of the file are shifted
+	TimezoneOverride    string                          `json:"timezone_override"`       // The timezone that overrides the timezone of the location
+	HeartRateZones      *HeartRateZonesResponse         `json:"heart_rate_zones,omitempty"`
+	PowerZones          *PowerZonesResponse             `json:"power_zones,omitempty"`
 }
 
 // MapDataResponse represents workout map data in API v2 responses
@@ -190,6 +256,9 @@ type MapDataResponse struct {
 	Center       MapCenterResponse       `json:"center"`
 	ExtraMetrics []string                `json:"extra_metrics,omitempty"`
 	Details      *MapDataDetailsResponse `json:"details,omitempty"`
+
+	CorrectedPoints int `json:"corrected_points,omitempty"` // The number of points corrected by cleaning the GPS track
+	DEMPoints       int `json:"dem_points,omitempty"`       // The number of points with the elevation of the elevation model
 }
 
 // MapCenterResponse represents the center coordinates
@@ -285,20 +354,22 @@ type CalendarEventResponse struct {
 // NewWorkoutResponse converts a database workout to API response
 func NewWorkoutResponse(w *model.Workout) WorkoutResponse {
 	wr := WorkoutResponse{
-		ID:         w.ID,
-		Date:       w.Date,
-		Dirty:      w.Dirty,
-		Name:       w.Name,
-		Notes:      w.Notes,
-		Type:       string(w.Type),
-		CustomType: w.CustomType,
-		UserID:     w.UserID,
-		Visibility: w.Visibility,
-		Locked:     w.Locked,
-		CreatedAt:  w.CreatedAt,
-		UpdatedAt:  w.UpdatedAt,
-		HasFile:    w.HasFile(),
-		HasTracks:  w.HasTracks(),
+		ID:           w.ID,
+		Date:         w.Date,
+		Dirty:        w.Dirty,
+		Name:         w.Name,
+		Notes:        w.Notes,
+		Type:         string(w.Type),
+		CustomType:   w.CustomType,
+		UserID:       w.UserID,
+		Visibility:   w.Visibility,
+		Locked:       w.Locked,
+		Commute:      w.Commute,
+		CreatedAt:    w.CreatedAt,
+		UpdatedAt:    w.UpdatedAt,
+		HasFile:      w.HasFile(),
+		HasTracks:    w.HasTracks(),
+		MultisportID: w.MultisportID,
 	}
 
 	// Add user data if available (preloaded)
@@ -332,6 +403,17 @@ func NewWorkoutResponse(w *model.Workout) WorkoutResponse {
 		wr.MaxHeartRate = &w.Data.MaxHeartRate
 		wr.AveragePower = &w.Data.AveragePower
 		wr.MaxPower = &w.Data.MaxPower
+		wr.NormalizedPower = optionalMetric(w.Data.NormalizedPower)
+		wr.VariabilityIndex = optionalMetric(w.Data.VariabilityIndex)
+		wr.IntensityFactor = optionalMetric(w.Data.IntensityFactor)
+		wr.TrainingStressScore = optionalMetric(w.Data.TrainingStressScore)
+		wr.GradeAdjustedSpeed = optionalMetric(w.Data.GradeAdjustedSpeed)
+		wr.PaceDecoupling = optionalMetric(w.Data.PaceDecoupling)
+		wr.PowerDecoupling = optionalMetric(w.Data.PowerDecoupling)
+
+		load, method := w.TrainingLoad()
+		wr.TrainingLoad = optionalMetric(load)
+		wr.TrainingLoadMethod = string(method)
 
 		// Convert pause duration to seconds (int64)
 		pauseDurationSecs := int64(w.Data.PauseDuration.Seconds())
@@ -407,7 +489,12 @@ func NewWorkoutPopupData(w *model.Workout) WorkoutPopupData {
 //nolint:gocyclo // assembling full workout view touches many optional fields
 func NewWorkoutDetailResponse(w *model.Workout, records []model.WorkoutIntervalRecordWithRank) WorkoutDetailResponse {
 	wr := WorkoutDetailResponse{
-		WorkoutResponse: NewWorkoutResponse(w),
+		WorkoutResponse:     NewWorkoutResponse(w),
+		Edited:              w.IsEdited(),
+		Split:               w.GPX != nil && w.GPX.Split,
+		ElevationCorrection: string(w.ElevationCorrection),
+		TimeOffsetSeconds:   int64(w.TimeOffset / time.Second),
+		TimezoneOverride:    w.TimezoneOverride,
 	}
 
 	// Add equipment
@@ -448,6 +535,21 @@ func NewWorkoutDetailResponse(w *model.Workout, records []model.WorkoutIntervalR
 		}
 
 		wr.MapData = workoutResponseMapData(w)
+
+		if w.User != nil && w.HasHeartRate() && w.Data.Details != nil {
+			zones := w.User.HeartRateZonesAt(w.Date)
+			wr.HeartRateZones = NewHeartRateZonesResponse(zones, w.Data.Details.TimeInHeartRateZones(zones, 0, len(w.Data.Details.Points)-1))
+		}
+
+		if w.User != nil && w.HasPower() && w.Data.Details != nil {
+			zones := w.User.PowerZonesAt(w.Date)
+			wr.PowerZones = NewPowerZonesResponse(zones, w.Data.Details.TimeInPowerZones(zones, 0, len(w.Data.Details.Points)-1))
+		}
+
+		if len(w.Data.Sets) > 0 {
+			wr.Sets = NewWorkoutSetResponses(w.Data.Sets)
+			wr.Exercises = NewExerciseEffortResponses(w.ExerciseEfforts())
+		}
 	}
 
 	// Add route segment matches
@@ -515,6 +617,10 @@ func NewWorkoutLapResponses(laps []model.WorkoutLap) []WorkoutLapResponse {
 			MaxHeartRate:        lap.MaxHeartRate,
 			AveragePower:        lap.AveragePower,
 			MaxPower:            lap.MaxPower,
+			NormalizedPower:     lap.NormalizedPower,
+			GradeAdjustedSpeed:  lap.GradeAdjustedSpeed,
+			PaceDecoupling:      lap.PaceDecoupling,
+			PowerDecoupling:     lap.PowerDecoupling,
 		}
 	}
 
@@ -527,6 +633,7 @@ func NewWorkoutBreakdownItemsFromLaps(laps []model.WorkoutLap, points []model.Ma
 	}
 
 	items := make([]WorkoutBreakdownItemResponse, len(laps))
+	details := &model.MapDataDetails{Points: points}
 
 	for i, lap := range laps {
 		startIdx := findClosestPointIndex(points, lap.Start)
@@ -564,6 +671,14 @@ func NewWorkoutBreakdownItemsFromLaps(laps []model.WorkoutLap, points []model.Ma
 			MaxHeartRate:        lap.MaxHeartRate,
 			AveragePower:        lap.AveragePower,
 			MaxPower:            lap.MaxPower,
+			GradeAdjustedSpeed:  convertSpeedToPreferred(lap.GradeAdjustedSpeed, units),
+			GradeAdjustedPace:   convertPaceToPreferred(lap.GradeAdjustedSpeed, units),
+			PaceDecoupling:      lap.PaceDecoupling,
+			PowerDecoupling:     lap.PowerDecoupling,
+		}
+
+		if stats, ok := details.StatsForRange(startIdx, endIdx); ok {
+			items[i].ExtraMetrics = newMetricStatsResponse(stats.ExtraMetrics)
 		}
 	}
 
@@ -603,6 +718,11 @@ func NewWorkoutBreakdownItemsFromUnit(items []model.BreakdownItem, unit string,
 			MaxHeartRate:        item.MaxHeartRate,
 			AveragePower:        item.AveragePower,
 			MaxPower:            item.MaxPower,
+			GradeAdjustedSpeed:  convertSpeedToPreferred(item.GradeAdjustedSpeed, units),
+			GradeAdjustedPace:   convertPaceToPreferred(item.GradeAdjustedSpeed, units),
+			PaceDecoupling:      item.PaceDecoupling,
+			PowerDecoupling:     item.PowerDecoupling,
+			ExtraMetrics:        newMetricStatsResponse(item.ExtraMetrics),
 			IsBest:              item.IsBest,
 			IsWorst:             item.IsWorst,
 		}
@@ -654,6 +774,30 @@ func convertSpeedToPreferred(speedMS float64, units *model.UserPreferredUnits) f
 	}
 }
 
+// convertPaceToPreferred converts a speed to the seconds per preferred
+// distance unit
+func convertPaceToPreferred(speedMS float64, units *model.UserPreferredUnits) float64 {
+	distance := convertDistanceToPreferred(speedMS, units)
+	if distance <= 0 {
+		return 0
+	}
+
+	return 1 / distance
+}
+
+func newMetricStatsResponse(metrics map[string]model.MetricStats) map[string]MetricStatsResponse {
+	if len(metrics) == 0 {
+		return nil
+	}
+
+	resp := make(map[string]MetricStatsResponse, len(metrics))
+	for name, m := range metrics {
+		resp[name] = MetricStatsResponse{Average: m.Average, Min: m.Min, Max: m.Max}
+	}
+
+	return resp
+}
+
 func optionalMetric(value float64) *float64 {
 	if value == 0 {
 		return nil
@@ -698,6 +842,8 @@ func NewWorkoutRangeStatsResponse(stats model.MapDataRangeStats, startIdx, endId
 		resp.Units.Temperature = units.Temperature()
 	}
 
+	resp.ExtraMetrics = newMetricStatsResponse(stats.ExtraMetrics)
+
 	resp.AverageCadence = optionalMetric(stats.AverageCadence)
 	resp.MinCadence = optionalMetric(stats.MinCadence)
 	resp.MaxCadence = optionalMetric(stats.MaxCadence)
@@ -713,6 +859,16 @@ func NewWorkoutRangeStatsResponse(stats model.MapDataRangeStats, startIdx, endId
 	resp.AveragePower = optionalMetric(stats.AveragePower)
 	resp.MinPower = optionalMetric(stats.MinPower)
 	resp.MaxPower = optionalMetric(stats.MaxPower)
+	resp.NormalizedPower = optionalMetric(stats.NormalizedPower)
+	resp.VariabilityIndex = optionalMetric(stats.VariabilityIndex)
+
+	if stats.GradeAdjustedSpeed > 0 {
+		gas := convertSpeedToPreferred(stats.GradeAdjustedSpeed, units)
+		resp.GradeAdjustedSpeed = &gas
+	}
+
+	resp.PaceDecoupling = optionalMetric(stats.PaceDecoupling)
+	resp.PowerDecoupling = optionalMetric(stats.PowerDecoupling)
 
 	if stats.AverageTemperature != 0 || stats.MinTemperature != 0 || stats.MaxTemperature != 0 {
 		resp.AverageTemperature = &stats.AverageTemperature
@@ -749,11 +905,13 @@ func workoutResponseMapData(w *model.Workout) *MapDataResponse {
 	mapData := &MapDataResponse{
 		Creator: w.Data.Creator,
 		Center: MapCenterResponse{
-			TZ:  w.Data.Center.TZ,
+			TZ:  w.Timezone(),
 			Lat: w.Data.Center.Lat,
 			Lng: w.Data.Center.Lng,
 		},
-		ExtraMetrics: w.Data.ExtraMetrics,
+		ExtraMetrics:    w.Data.ExtraMetrics,
+		CorrectedPoints: w.Data.CorrectedPoints,
+		DEMPoints:       w.Data.DEMPoints,
 	}
 
 	// Add detailed points in compact format
@@ -789,7 +947,7 @@ func workoutResponseMapData(w *model.Workout) *MapDataResponse {
 			mapData.Details.Distance[i] = point.TotalDistance / 1000 // Convert to km
 			mapData.Details.Duration[i] = point.TotalDuration.Seconds()
 			mapData.Details.Slope[i] = point.SlopeGrade
-			mapData.Details.Elevation[i] = point.Elevation
+			mapData.Details.Elevation[i] = point.EnhancedElevation()
 
 			// Calculate speed from extra metrics or derive it
 			speed := point.AverageSpeed()
@@ -825,15 +983,15 @@ const (
 )
 
 type zoneMetricsBuilder struct {
-	user     *model.User
-	date     time.Time
-	maxHR    float64
-	restHR   float64
-	ftp      float64
-	hasHR    bool
-	hasPower bool
-	hrZones  []any
-	ftpZones []any
+	user       *model.User
+	date       time.Time
+	hrZones    *model.HeartRateZones
+	powerZones *model.PowerZones // nil if the user has no FTP
+	populated  bool
+	hasHR      bool
+	hasPower   bool
+	hrBuf      []any
+	ftpZones   []any
 }
 
 func newZoneMetricsBuilder(w *model.Workout) *zoneMetricsBuilder {
@@ -844,11 +1002,8 @@ func newZoneMetricsBuilder(w *model.Workout) *zoneMetricsBuilder {
 	return &zoneMetricsBuilder{
 		user:     w.User,
 		date:     w.Date,
-		maxHR:    0,
-		restHR:   0,
-		ftp:      0,
 		hasHR:    w.HasHeartRate(),
-		hasPower: w.HasExtraMetric("power"),
+		hasPower: w.HasPower(),
 	}
 }
 
@@ -866,7 +1021,7 @@ func (z *zoneMetricsBuilder) ensureBuffers(extra map[string][]any, length int) {
 	if z.hasHR {
 		hrBuf := make([]any, length)
 		extra[hrZoneMetricName] = hrBuf
-		z.hrZones = hrBuf
+		z.hrBuf = hrBuf
 	}
 
 	if z.hasPower {
@@ -881,76 +1036,47 @@ func (z *zoneMetricsBuilder) setForPoint(idx int, metrics model.ExtraMetrics) {
 		return
 	}
 
-	if z.hasHR && z.hrZones != nil {
+	if z.hasHR && z.hrBuf != nil {
 		if hr, ok := metrics["heart-rate"]; ok && hr > 0 {
-			z.hrZones[idx] = calculateHeartRateZone(hr, z.maxHR, z.restHR)
+			z.hrBuf[idx] = z.hrZones.Zone(hr)
 		} else {
-			z.hrZones[idx] = nil
+			z.hrBuf[idx] = nil
 		}
 	}
 
 	if z.hasPower && z.ftpZones != nil {
-		if power, ok := metrics["power"]; ok && power > 0 {
-			z.ftpZones[idx] = calculateFTPZone(power, z.ftp)
+		if power, ok := metrics["power"]; ok && power > 0 && z.powerZones != nil {
+			z.ftpZones[idx] = z.powerZones.Zone(power)
 		} else {
 			z.ftpZones[idx] = nil
 		}
 	}
 }
 
-func buildHeartRateZoneRanges(maxHR float64, restHR float64) []ZoneRangeResponse {
-	if maxHR <= 0 {
-		maxHR = 200
-	}
-
-	if restHR <= 0 {
-		restHR = 60
-	}
+// NewHeartRateZoneRanges returns the bounds of the heart rate zones
+func NewHeartRateZoneRanges(zones model.HeartRateZones) []ZoneRangeResponse {
+	ranges := make([]ZoneRangeResponse, 0, zones.Count())
+	lower := zones.Min
 
-	reserve := maxHR - restHR
-	if reserve <= 0 {
-		reserve = maxHR
+	for i, upper := range zones.Bounds {
+		ranges = append(ranges, ZoneRangeResponse{Zone: i + 1, Min: lower, Max: float64Ptr(upper)})
+		lower = upper
 	}
 
-	upperBounds := []float64{
-		restHR + 0.6*reserve,
-		restHR + 0.7*reserve,
-		restHR + 0.8*reserve,
-		restHR + 0.9*reserve,
-	}
-
-	return []ZoneRangeResponse{
-		{Zone: 1, Min: restHR, Max: float64Ptr(upperBounds[0])},
-		{Zone: 2, Min: upperBounds[0], Max: float64Ptr(upperBounds[1])},
-		{Zone: 3, Min: upperBounds[1], Max: float64Ptr(upperBounds[2])},
-		{Zone: 4, Min: upperBounds[2], Max: float64Ptr(upperBounds[3])},
-		{Zone: 5, Min: upperBounds[3]},
-	}
+	return append(ranges, ZoneRangeResponse{Zone: zones.Count(), Min: lower})
 }
 
-func buildFTPZoneRanges(ftp float64) []ZoneRangeResponse {
-	if ftp <= 0 {
-		ftp = 200
-	}
+// NewPowerZoneRanges returns the bounds of the power zones
+func NewPowerZoneRanges(zones model.PowerZones) []ZoneRangeResponse {
+	ranges := make([]ZoneRangeResponse, 0, zones.Count())
+	lower := 0.0
 
-	thresholds := []float64{
-		0.55 * ftp,
-		0.75 * ftp,
-		0.9 * ftp,
-		1.05 * ftp,
-		1.2 * ftp,
-		1.5 * ftp,
+	for i, upper := range zones.Bounds {
+		ranges = append(ranges, ZoneRangeResponse{Zone: i + 1, Min: lower, Max: float64Ptr(upper)})
+		lower = upper
 	}
 
-	return []ZoneRangeResponse{
-		{Zone: 1, Min: 0, Max: float64Ptr(thresholds[0])},
-		{Zone: 2, Min: thresholds[0], Max: float64Ptr(thresholds[1])},
-		{Zone: 3, Min: thresholds[1], Max: float64Ptr(thresholds[2])},
-		{Zone: 4, Min: thresholds[2], Max: float64Ptr(thresholds[3])},
-		{Zone: 5, Min: thresholds[3], Max: float64Ptr(thresholds[4])},
-		{Zone: 6, Min: thresholds[4], Max: float64Ptr(thresholds[5])},
-		{Zone: 7, Min: thresholds[5]},
-	}
+	return append(ranges, ZoneRangeResponse{Zone: zones.Count(), Min: lower})
 }
 
 func float64Ptr(val float64) *float64 {
@@ -959,19 +1085,14 @@ func float64Ptr(val float64) *float64 {
 }
 
 func (z *zoneMetricsBuilder) populateUserMetrics() {
-	if z.user == nil {
+	if z.user == nil || z.populated {
 		return
 	}
 
-	if z.maxHR == 0 {
-		z.maxHR = z.user.MaxHeartRateAt(z.date)
-	}
-	if z.restHR == 0 {
-		z.restHR = z.user.RestingHeartRateAt(z.date)
-	}
-	if z.ftp == 0 {
-		z.ftp = z.user.FTPAt(z.date)
-	}
+	zones := z.user.HeartRateZonesAt(z.date)
+	z.hrZones = &zones
+	z.powerZones = z.user.PowerZonesAt(z.date)
+	z.populated = true
 }
 
 func (z *zoneMetricsBuilder) zoneRanges() map[string][]ZoneRangeResponse {
@@ -983,11 +1104,11 @@ func (z *zoneMetricsBuilder) zoneRanges() map[string][]ZoneRangeResponse {
 	ranges := make(map[string][]ZoneRangeResponse)
 
 	if z.hasHR {
-		ranges["heart-rate"] = buildHeartRateZoneRanges(z.maxHR, z.restHR)
+		ranges["heart-rate"] = NewHeartRateZoneRanges(*z.hrZones)
 	}
 
-	if z.hasPower {
-		ranges["power"] = buildFTPZoneRanges(z.ftp)
+	if z.hasPower && z.powerZones != nil {
+		ranges["power"] = NewPowerZoneRanges(*z.powerZones)
 	}
 
 	if len(ranges) == 0 {
@@ -997,61 +1118,6 @@ func (z *zoneMetricsBuilder) zoneRanges() map[string][]ZoneRangeResponse {
 	return ranges
 }
 
-func calculateHeartRateZone(hr float64, maxHR float64, restHR float64) int {
-	if maxHR <= 0 {
-		maxHR = 200
-	}
-
-	if restHR <= 0 {
-		restHR = 60
-	}
-
-	reserve := maxHR - restHR
-	if reserve <= 0 {
-		reserve = maxHR
-	}
-
-	percent := (hr - restHR) / reserve
-
-	switch {
-	case percent < 0.6:
-		return 1
-	case percent < 0.7:
-		return 2
-	case percent < 0.8:
-		return 3
-	case percent < 0.9:
-		return 4
-	default:
-		return 5
-	}
-}
-
-func calculateFTPZone(power float64, ftp float64) int {
-	if ftp <= 0 {
-		ftp = 200
-	}
-
-	ratio := power / ftp
-
-	switch {
-	case ratio < 0.55:
-		return 1
-	case ratio < 0.75:
-		return 2
-	case ratio < 0.9:
-		return 3
-	case ratio < 1.05:
-		return 4
-	case ratio < 1.2:
-		return 5
-	case ratio < 1.5:
-		return 6
-	default:
-		return 7
-	}
-}
-
 // WorkoutReplyResponse represents a reply/comment to a workout
 type WorkoutReplyResponse struct {
 	ID          uint64               `json:"id"`
@@ -0,0 +1,257 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// DuplicateOverlapThreshold is the minimal part of the shorter of two
+// workouts that has to overlap with the other workout for the two to be
+// probable duplicates
+const DuplicateOverlapThreshold = 0.8
+
+// duplicateSearchWindow is how long before a workout's start other workouts
+// are searched for overlap; longer workouts are not found
+const duplicateSearchWindow = 24 * time.Hour
+
+// MergeStreamMetrics are the sensor streams a merged workout borrows from the
+// other recording when it has none itself
+var MergeStreamMetrics = []string{"heart-rate", "power", "cadence"}
+
+var (
+	ErrMergeSameWorkout    = errors.New("can not merge a workout with itself")
+	ErrMergeOtherUser      = errors.New("can not merge workouts of different users")
+	ErrMergeNotOverlapping = errors.New("workouts do not overlap")
+)
+
+// DuplicatePair is a pair of workouts of the same user that are probably two
+// recordings of the same activity
+type DuplicatePair struct {
+	Workout *Workout // The earlier workout
+	Other   *Workout // The later workout
+	Overlap float64  // The part of the shorter workout that overlaps with the other workout
+}
+
+// TimeRange returns the start and end of the workout
+func (w *Workout) TimeRange() (time.Time, time.Time) {
+	start := w.Date
+
+	if w.Data == nil {
+		return start, start
+	}
+
+	if !w.Data.Stop.IsZero() && w.Data.Stop.After(start) {
+		return start, w.Data.Stop
+	}
+
+	return start, start.Add(w.Data.TotalDuration)
+}
+
+// WorkoutOverlap returns the part of the shorter of both workouts that
+// overlaps with the other workout, between 0 and 1
+func WorkoutOverlap(a, b *Workout) float64 {
+	aStart, aEnd := a.TimeRange()
+	bStart, bEnd := b.TimeRange()
+
+	shortest := min(aEnd.Sub(aStart), bEnd.Sub(bStart))
+	if shortest <= 0 {
+		return 0
+	}
+
+	overlap := minTime(aEnd, bEnd).Sub(maxTime(aStart, bStart))
+	if overlap <= 0 {
+		return 0
+	}
+
+	return min(1, overlap.Seconds()/shortest.Seconds())
+}
+
+// ProbableDuplicates returns the other workouts of the user that overlap with
+// the workout by at least DuplicateOverlapThreshold
+func (w *Workout) ProbableDuplicates(db *gorm.DB) ([]*Workout, error) {
+	start, end := w.TimeRange()
+
+	var candidates []*Workout
+
+	if err := PreloadWorkoutData(db).
+		Where("user_id = ?", w.UserID).
+		Where("id != ?", w.ID).
+		Where("date BETWEEN ? AND ?", start.Add(-duplicateSearchWindow), end).
+		Order("date ASC").
+		Find(&candidates).Error; err != nil {
+		return nil, err
+	}
+
+	duplicates := []*Workout{}
+
+	for _, c := range candidates {
+		if WorkoutOverlap(w, c) >= DuplicateOverlapThreshold {
+			duplicates = append(duplicates, c)
+		}
+	}
+
+	return duplicates, nil
+}
+
+// FindProbableDuplicates returns all pairs of workouts of the user that
+// overlap by at least DuplicateOverlapThreshold, ordered by date
+func FindProbableDuplicates(db *gorm.DB, userID uint64) ([]DuplicatePair, error) {
+	var workouts []*Workout
+
+	if err := PreloadWorkoutData(db).
+		Where("user_id = ?", userID).
+		Order("date ASC").
+		Find(&workouts).Error; err != nil {
+		return nil, err
+	}
+
+	pairs := []DuplicatePair{}
+
+	for i, w := range workouts {
+		_, end := w.TimeRange()
+
+		for _, o := range workouts[i+1:] {
+			if o.Date.After(end) {
+				break
+			}
+
+			if overlap := WorkoutOverlap(w, o); overlap >= DuplicateOverlapThreshold {
+				pairs = append(pairs, DuplicatePair{Workout: w, Other: o, Overlap: overlap})
+			}
+		}
+	}
+
+	return pairs, nil
+}
+
+// MergeWorkouts merges two recordings of the same activity: the workout with
+// the best GPS track is kept, borrows the sensor streams it is missing from
+// the other workout, and the other workout is deleted. Both workouts must
+// have their details and equipment loaded. The kept workout is marked dirty,
+// so its records are updated. It returns the kept and the deleted workout.
+func MergeWorkouts(db *gorm.DB, w, other *Workout) (*Workout, *Workout, error) {
+	if w.ID == other.ID {
+		return nil, nil, ErrMergeSameWorkout
+	}
+
+	if w.UserID != other.UserID {
+		return nil, nil, ErrMergeOtherUser
+	}
+
+	if WorkoutOverlap(w, other) == 0 {
+		return nil, nil, ErrMergeNotOverlapping
+	}
+
+	keep, lose := w, other
+	if other.gpsPointCount() > w.gpsPointCount() {
+		keep, lose = other, w
+	}
+
+	keep.borrowStreams(lose)
+	keep.borrowEquipment(lose)
+
+	if keep.Notes == "" {
+		keep.Notes = lose.Notes
+	}
+
+	keep.UpdateAverages()
+	keep.UpdateExtraMetrics()
+	keep.Dirty = true
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := lose.Delete(tx); err != nil {
+			return err
+		}
+
+		if err := keep.Save(tx); err != nil {
+			return err
+		}
+
+		return tx.Model(keep).Association("Equipment").Replace(keep.Equipment)
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return keep, lose, nil
+}
+
+// gpsPointCount returns the number of points with coordinates
+func (w *Workout) gpsPointCount() int {
+	if w.Data == nil || w.Data.Details == nil {
+		return 0
+	}
+
+	count := 0
+
+	for i := range w.Data.Details.Points {
+		if w.Data.Details.Points[i].Lat != 0 || w.Data.Details.Points[i].Lng != 0 {
+			count++
+		}
+	}
+
+	return count
+}
+
+// borrowStreams adds the sensor streams of the other workout for the metrics
+// the workout has no values for
+func (w *Workout) borrowStreams(other *Workout) {
+	if w.Data == nil || other.Data == nil || other.Data.Details == nil {
+		return
+	}
+
+	if w.Data.Details == nil {
+		w.Data.Details = &MapDataDetails{}
+	}
+
+	for _, metric := range MergeStreamMetrics {
+		if w.Data.Details.HasMetric(metric) {
+			continue
+		}
+
+		s := NewSensorStream(metric, other.Name, other.Data.Details.Points)
+		if len(s.Samples) == 0 {
+			continue
+		}
+
+		w.Data.Details.AddStream(s)
+	}
+}
+
+// borrowEquipment adds the equipment of the other workout
+func (w *Workout) borrowEquipment(other *Workout) {
+	ids := map[uint64]bool{}
+	for _, e := range w.Equipment {
+		ids[e.ID] = true
+	}
+
+	for _, e := range other.Equipment {
+		if !ids[e.ID] {
+			w.Equipment = append(w.Equipment, e)
+			ids[e.ID] = true
+		}
+	}
+
+	sort.SliceStable(w.Equipment, func(i, j int) bool {
+		return w.Equipment[i].ID < w.Equipment[j].ID
+	})
+}
+
+func minTime(a, b time.Time) time.Time {
+	if a.Before(b) {
+		return a
+	}
+
+	return b
+}
+
+func maxTime(a, b time.Time) time.Time {
+	if a.After(b) {
+		return a
+	}
+
+	return b
+}
@@ -0,0 +1,118 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func duplicateTestWorkout(u *User, name string, start time.Time, gps bool, metrics ExtraMetrics) *Workout {
+	points := make([]MapPoint, 0, 60)
+
+	for i := range 60 {
+		p := MapPoint{Time: start.Add(time.Duration(i) * 10 * time.Second)}
+
+		if gps {
+			p.Lat = 51 + float64(i)/10000
+			p.Lng = 4
+		}
+
+		if metrics != nil {
+			p.ExtraMetrics = ExtraMetrics{}
+			for k, v := range metrics {
+				p.ExtraMetrics.Set(k, v)
+			}
+		}
+
+		points = append(points, p)
+	}
+
+	return &Workout{
+		UserID: u.ID,
+		Name:   name,
+		Date:   start,
+		Type:   WorkoutTypeCycling,
+		Data: &MapData{
+			WorkoutData: WorkoutData{
+				Start:         start,
+				Stop:          points[len(points)-1].Time,
+				TotalDuration: points[len(points)-1].Time.Sub(start),
+			},
+			Details: &MapDataDetails{Points: points},
+		},
+	}
+}
+
+func TestWorkoutOverlap(t *testing.T) {
+	u := defaultUser()
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	a := duplicateTestWorkout(u, "a", start, true, nil)
+	b := duplicateTestWorkout(u, "b", start.Add(59*time.Second), false, nil)
+	c := duplicateTestWorkout(u, "c", start.Add(time.Hour), false, nil)
+
+	assert.InDelta(t, 0.9, WorkoutOverlap(a, b), 0.01)
+	assert.InDelta(t, 0.9, WorkoutOverlap(b, a), 0.01)
+	assert.Zero(t, WorkoutOverlap(a, c))
+}
+
+func TestMergeWorkouts(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	outdoor := duplicateTestWorkout(u, "outdoor", start, true, nil)
+	sensors := duplicateTestWorkout(u, "sensors", start.Add(2*time.Second), false, ExtraMetrics{"heart-rate": 140, "power": 210})
+	other := duplicateTestWorkout(u, "other", start.Add(3*time.Hour), true, nil)
+
+	for _, w := range []*Workout{outdoor, sensors, other} {
+		require.NoError(t, w.Create(db))
+	}
+
+	pairs, err := FindProbableDuplicates(db, u.ID)
+	require.NoError(t, err)
+	require.Len(t, pairs, 1)
+	assert.Equal(t, outdoor.ID, pairs[0].Workout.ID)
+	assert.Equal(t, sensors.ID, pairs[0].Other.ID)
+
+	duplicates, err := outdoor.ProbableDuplicates(db)
+	require.NoError(t, err)
+	require.Len(t, duplicates, 1)
+	assert.Equal(t, sensors.ID, duplicates[0].ID)
+
+	a, err := GetWorkoutDetails(db, sensors.ID)
+	require.NoError(t, err)
+
+	b, err := GetWorkoutDetails(db, outdoor.ID)
+	require.NoError(t, err)
+
+	// The workout with the GPS track is kept, even if the merge was requested
+	// from the other one
+	kept, deleted, err := MergeWorkouts(db, a, b)
+	require.NoError(t, err)
+	assert.Equal(t, outdoor.ID, kept.ID)
+	assert.Equal(t, sensors.ID, deleted.ID)
+
+	_, err = GetWorkout(db, sensors.ID)
+	require.Error(t, err)
+
+	stored, err := GetWorkoutDetails(db, outdoor.ID)
+	require.NoError(t, err)
+	assert.True(t, stored.Dirty)
+	assert.Equal(t, []string{"heart-rate", "power"}, stored.Data.ExtraMetrics)
+	assert.InDelta(t, 140, stored.Data.Details.Points[10].ExtraMetrics.Get("heart-rate"), 0.01)
+	require.Len(t, stored.Data.Details.Streams, 2)
+
+	// The borrowed streams survive parsing the file again
+	fresh := duplicateTestWorkout(u, "outdoor", start, true, nil)
+	stored.setData(fresh.Data)
+	assert.InDelta(t, 210, stored.Data.Details.Points[10].ExtraMetrics.Get("power"), 0.01)
+
+	_, _, err = MergeWorkouts(db, stored, stored)
+	require.ErrorIs(t, err, ErrMergeSameWorkout)
+}
@@ -0,0 +1,130 @@
+package model
+
+import (
+	"errors"
+	"math"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/dem"
+	"gorm.io/gorm"
+)
+
+// ElevationCorrection is how the elevation of a workout is corrected with the
+// elevation model (DEM) files
+type ElevationCorrection string
+
+const (
+	ElevationCorrectionNone    ElevationCorrection = ""        // The recorded elevation is used
+	ElevationCorrectionReplace ElevationCorrection = "replace" // The elevation of the DEM is used
+	ElevationCorrectionBlend   ElevationCorrection = "blend"   // The recorded elevation is blended with the elevation of the DEM
+)
+
+// DEMBlendWeight is the weight of the elevation of the DEM when it is blended
+// with the recorded elevation
+const DEMBlendWeight = 0.75
+
+var (
+	ErrInvalidElevationCorrection = errors.New("invalid elevation correction")
+	ErrDEMNotConfigured           = errors.New("no elevation model directory configured")
+)
+
+// Validate returns an error when the correction is unknown, or when it needs
+// the DEM files and no directory is configured
+func (c ElevationCorrection) Validate() error {
+	switch c {
+	case ElevationCorrectionNone:
+		return nil
+	case ElevationCorrectionReplace, ElevationCorrectionBlend:
+	default:
+		return ErrInvalidElevationCorrection
+	}
+
+	if !dem.Enabled() {
+		return ErrDEMNotConfigured
+	}
+
+	return nil
+}
+
+// CorrectElevation replaces or blends the elevation of the points with the
+// elevation of the DEM at their position. The recorded elevation of the
+// points is kept; the corrected elevation is stored as the "elevation" extra
+// metric, which is used for the totals, climbs and slopes. It returns the
+// number of corrected points.
+func (m *MapData) CorrectElevation(c ElevationCorrection) int {
+	if c == ElevationCorrectionNone || m.Details == nil {
+		return 0
+	}
+
+	corrected := 0
+
+	for i := range m.Details.Points {
+		p := &m.Details.Points[i]
+		if !hasPosition(p) {
+			continue
+		}
+
+		ele, ok := dem.Elevation(p.Lat, p.Lng)
+		if !ok {
+			continue
+		}
+
+		if recorded := p.EnhancedElevation(); c == ElevationCorrectionBlend && !math.IsNaN(recorded) {
+			ele = DEMBlendWeight*ele + (1-DEMBlendWeight)*recorded
+		}
+
+		if p.ExtraMetrics == nil {
+			p.ExtraMetrics = ExtraMetrics{}
+		}
+
+		p.ExtraMetrics.Set("elevation", ele)
+		corrected++
+	}
+
+	return corrected
+}
+
+// SetElevationCorrection changes how the elevation of the workout is
+// corrected, and marks the workout for refresh
+func (w *Workout) SetElevationCorrection(db *gorm.DB, c ElevationCorrection) error {
+	if err := c.Validate(); err != nil {
+		return err
+	}
+
+	if !w.HasFile() {
+		return ErrWorkoutHasNoFile
+	}
+
+	w.ElevationCorrection = c
+	w.Dirty = true
+
+	return w.Save(db)
+}
+
+// SetElevationCorrectionForAll changes how the elevation of all workouts
+// with a file is corrected, and marks the changed workouts for refresh; it
+// returns the IDs of the changed workouts
+func SetElevationCorrectionForAll(db *gorm.DB, c ElevationCorrection) ([]uint64, error) {
+	if err := c.Validate(); err != nil {
+		return nil, err
+	}
+
+	var ids []uint64
+
+	if err := db.Model(&Workout{}).
+		Where("elevation_correction IS NULL OR elevation_correction != ?", c).
+		Where("id IN (?)", db.Model(&GPXData{}).Select("workout_id")).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	return ids, db.Model(&Workout{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{
+			"elevation_correction": c,
+			"dirty":                true,
+		}).Error
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/dem"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// writeTestDEM writes HGT tiles with the same elevation everywhere, for the
+// tiles of all points, and configures their directory
+func writeTestDEM(t *testing.T, points []MapPoint, elevation int16) {
+	t.Helper()
+
+	dir := t.TempDir()
+
+	data := make([]byte, 2*3*3)
+	for i := 0; i < len(data); i += 2 {
+		binary.BigEndian.PutUint16(data[i:], uint16(elevation))
+	}
+
+	for _, p := range points {
+		lat, lng := int(math.Floor(p.Lat)), int(math.Floor(p.Lng))
+		ns, ew := "N", "E"
+
+		if lat < 0 {
+			ns, lat = "S", -lat
+		}
+
+		if lng < 0 {
+			ew, lng = "W", -lng
+		}
+
+		name := fmt.Sprintf("%s%02d%s%03d.hgt", ns, lat, ew, lng)
+
+		require.NoError(t, os.WriteFile(filepath.Join(dir, name), data, 0o600))
+	}
+
+	dem.SetDirectory(dir)
+	t.Cleanup(func() { dem.SetDirectory("") })
+}
+
+func TestElevationCorrection_Validate(t *testing.T) {
+	dem.SetDirectory("")
+
+	require.NoError(t, ElevationCorrectionNone.Validate())
+	require.ErrorIs(t, ElevationCorrectionReplace.Validate(), ErrDEMNotConfigured)
+	require.ErrorIs(t, ElevationCorrection("raise").Validate(), ErrInvalidElevationCorrection)
+
+	writeTestDEM(t, nil, 0)
+
+	require.NoError(t, ElevationCorrectionReplace.Validate())
+	require.NoError(t, ElevationCorrectionBlend.Validate())
+}
+
+func TestMapData_CorrectElevation(t *testing.T) {
+	now := time.Now()
+	points := func() []MapPoint {
+		return []MapPoint{
+			{Time: now, Lat: 50.5, Lng: 4.5, Elevation: 80, ExtraMetrics: ExtraMetrics{"elevation": 120}},
+			{Time: now.Add(time.Second), Lat: 50.6, Lng: 4.5, Elevation: 90},
+			{Time: now.Add(2 * time.Second)},
+			{Time: now.Add(3 * time.Second), Lat: 50.7, Lng: 4.5, Elevation: math.NaN()},
+		}
+	}
+
+	writeTestDEM(t, points()[:1], 100)
+
+	m := &MapData{Details: &MapDataDetails{Points: points()}}
+	assert.Equal(t, 0, m.CorrectElevation(ElevationCorrectionNone))
+	assert.InDelta(t, 120, m.Details.Points[0].EnhancedElevation(), 0.001)
+
+	assert.Equal(t, 3, m.CorrectElevation(ElevationCorrectionReplace))
+	assert.InDelta(t, 100, m.Details.Points[0].EnhancedElevation(), 0.001)
+	assert.InDelta(t, 100, m.Details.Points[1].EnhancedElevation(), 0.001)
+	assert.InDelta(t, 80, m.Details.Points[0].Elevation, 0.001)
+	assert.Empty(t, m.Details.Points[2].ExtraMetrics)
+
+	m = &MapData{Details: &MapDataDetails{Points: points()}}
+	assert.Equal(t, 3, m.CorrectElevation(ElevationCorrectionBlend))
+	assert.InDelta(t, 0.75*100+0.25*120, m.Details.Points[0].EnhancedElevation(), 0.001)
+	assert.InDelta(t, 0.75*100+0.25*90, m.Details.Points[1].EnhancedElevation(), 0.001)
+	// Without a recorded elevation, the elevation of the DEM is used
+	assert.InDelta(t, 100, m.Details.Points[3].EnhancedElevation(), 0.001)
+}
+
+func TestWorkout_SetElevationCorrection(t *testing.T) {
+	db, w := createEditTestWorkout(t)
+
+	require.Greater(t, w.Data.TotalUp, 0.0)
+	raw := w.Data.Details.Points[1].Elevation
+
+	writeTestDEM(t, w.Data.Details.Points, 1000)
+
+	require.NoError(t, w.SetElevationCorrection(db, ElevationCorrectionReplace))
+	assert.True(t, w.Dirty)
+	require.NoError(t, w.UpdateData(db))
+
+	assert.Equal(t, len(w.Data.Details.Points), w.Data.DEMPoints)
+	assert.InDelta(t, 1000, w.Data.MinElevation, 0.001)
+	assert.InDelta(t, 1000, w.Data.MaxElevation, 0.001)
+	assert.InDelta(t, 0, w.Data.TotalUp, 0.001)
+	assert.Empty(t, w.Data.Climbs)
+	assert.InDelta(t, raw, w.Data.Details.Points[1].Elevation, 0.001)
+
+	ids, err := SetElevationCorrectionForAll(db, ElevationCorrectionReplace)
+	require.NoError(t, err)
+	assert.Empty(t, ids)
+
+	ids, err = SetElevationCorrectionForAll(db, ElevationCorrectionNone)
+	require.NoError(t, err)
+	assert.Equal(t, []uint64{w.ID}, ids)
+
+	w, err = GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	assert.True(t, w.Dirty)
+	require.NoError(t, w.UpdateData(db))
+
+	assert.Equal(t, 0, w.Data.DEMPoints)
+	assert.Greater(t, w.Data.TotalUp, 0.0)
+}
@@ -54,8 +54,9 @@ func Connect(driver, dsn string, debug bool, logger *slog.Logger) (*gorm.DB, err
 	if err := RunMigrations(db, func(db *gorm.DB) error {
 		return db.AutoMigrate(
 			&User{}, &Profile{}, &Config{}, &Equipment{}, &WorkoutEquipment{}, &Measurement{},
-			&Workout{}, &GPXData{}, &MapData{}, &Segment{}, &MapDataDetails{}, &MapPoint{}, &WorkoutAttachment{}, &RouteSegment{}, &RouteSegmentMatch{},
-			&WorkoutIntervalRecord{}, &Follower{}, &APOutboxWorkout{}, &APOutboxEntry{}, &APOutboxDelivery{}, &WorkoutLike{}, &WorkoutReply{},
+			&Workout{}, &GPXData{}, &MapData{}, &Segment{}, &WorkoutLength{}, &Exercise{}, &WorkoutSet{}, &MapDataDetails{}, &MapPoint{}, &WorkoutAttachment{}, &RouteSegment{}, &RouteSegmentMatch{},
+			&WorkoutIntervalRecord{}, &WorkoutEffortRecord{}, &Follower{}, &APOutboxWorkout{}, &APOutboxEntry{}, &APOutboxDelivery{}, &WorkoutLike{}, &WorkoutReply{},
+			&ArchiveImport{}, &Multisport{}, &TrainingLoad{},
 		)
 	}); err != nil {
 		return nil, err
@@ -65,6 +66,10 @@ func Connect(driver, dsn string, debug bool, logger *slog.Logger) (*gorm.DB, err
 		return nil, err
 	}
 
+	if err := seedExercises(db); err != nil {
+		return nil, err
+	}
+
 	return db, nil
 }
 
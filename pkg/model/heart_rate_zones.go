@@ -0,0 +1,305 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// HeartRateZoneModel is how the heart rate zones of a user are derived from
+// their measurements
+type HeartRateZoneModel string
+
+const (
+	HeartRateZoneModelReserve    HeartRateZoneModel = ""            // Percent of the heart rate reserve (Karvonen), between resting and max heart rate
+	HeartRateZoneModelPercentMax HeartRateZoneModel = "percent-max" // Percent of the max heart rate
+	HeartRateZoneModelThreshold  HeartRateZoneModel = "threshold"   // Percent of the lactate threshold heart rate (LTHR)
+)
+
+// ZoneMaxGap is the longest time between two points that is counted in the
+// time in zone; longer gaps are pauses of the recording
+const ZoneMaxGap = time.Minute
+
+var ErrInvalidHeartRateZoneModel = errors.New("invalid heart rate zone model")
+
+// Validate returns an error when the zone model is unknown
+func (m HeartRateZoneModel) Validate() error {
+	switch m {
+	case HeartRateZoneModelReserve, HeartRateZoneModelPercentMax, HeartRateZoneModelThreshold:
+		return nil
+	default:
+		return ErrInvalidHeartRateZoneModel
+	}
+}
+
+// HeartRateZones are the five heart rate zones of a user at some date
+type HeartRateZones struct {
+	Model  HeartRateZoneModel `json:"model"`  // The model the zones are derived with
+	Min    float64            `json:"min"`    // The lower bound of zone 1, for display
+	Bounds []float64          `json:"bounds"` // The lower bounds of zones 2 and up, in bpm
+}
+
+// NewHeartRateReserveZones returns the zones at 60, 70, 80 and 90 percent of
+// the heart rate reserve
+func NewHeartRateReserveZones(maxHR, restHR float64) HeartRateZones {
+	if maxHR <= 0 {
+		maxHR = 200
+	}
+
+	if restHR <= 0 {
+		restHR = 60
+	}
+
+	reserve := maxHR - restHR
+	if reserve <= 0 {
+		reserve = maxHR
+	}
+
+	return HeartRateZones{
+		Model: HeartRateZoneModelReserve,
+		Min:   restHR,
+		Bounds: []float64{
+			restHR + 0.6*reserve,
+			restHR + 0.7*reserve,
+			restHR + 0.8*reserve,
+			restHR + 0.9*reserve,
+		},
+	}
+}
+
+// NewPercentMaxHeartRateZones returns the zones at 60, 70, 80 and 90 percent
+// of the max heart rate
+func NewPercentMaxHeartRateZones(maxHR float64) HeartRateZones {
+	if maxHR <= 0 {
+		maxHR = 200
+	}
+
+	return HeartRateZones{
+		Model:  HeartRateZoneModelPercentMax,
+		Min:    0.5 * maxHR,
+		Bounds: []float64{0.6 * maxHR, 0.7 * maxHR, 0.8 * maxHR, 0.9 * maxHR},
+	}
+}
+
+// NewThresholdHeartRateZones returns the zones at 85, 90, 95 and 100 percent
+// of the lactate threshold heart rate
+func NewThresholdHeartRateZones(lthr float64) HeartRateZones {
+	return HeartRateZones{
+		Model:  HeartRateZoneModelThreshold,
+		Min:    0.75 * lthr,
+		Bounds: []float64{0.85 * lthr, 0.9 * lthr, 0.95 * lthr, lthr},
+	}
+}
+
+// Count returns the number of zones
+func (z HeartRateZones) Count() int {
+	return len(z.Bounds) + 1
+}
+
+// Zone returns the zone of the heart rate, starting at 1
+func (z HeartRateZones) Zone(hr float64) int {
+	return sort.Search(len(z.Bounds), func(i int) bool {
+		return hr < z.Bounds[i]
+	}) + 1
+}
+
+// HeartRateZonesAt returns the heart rate zones of the user, with the zone
+// model of their profile and the measurements valid at the date. The
+// threshold model falls back to the heart rate reserve when the user has no
+// lactate threshold heart rate.
+func (u *User) HeartRateZonesAt(d time.Time) HeartRateZones {
+	switch u.Profile.HeartRateZoneModel {
+	case HeartRateZoneModelPercentMax:
+		return NewPercentMaxHeartRateZones(u.MaxHeartRateAt(d))
+	case HeartRateZoneModelThreshold:
+		if lthr := u.LTHRAt(d); lthr > 0 {
+			return NewThresholdHeartRateZones(lthr)
+		}
+	}
+
+	return NewHeartRateReserveZones(u.MaxHeartRateAt(d), u.RestingHeartRateAt(d))
+}
+
+// TimeInHeartRateZones returns the time spent in each of the zones, between
+// the points with the indexes (inclusive); it returns nil when the points
+// have no heart rate
+func (d *MapDataDetails) TimeInHeartRateZones(z HeartRateZones, startIdx, endIdx int) []time.Duration {
+	return d.timeInZones("heart-rate", z.Count(), z.Zone, startIdx, endIdx)
+}
+
+// timeInZones returns the time spent in each of the zones of the metric,
+// between the points with the indexes (inclusive); it returns nil when the
+// points do not have the metric
+func (d *MapDataDetails) timeInZones(metric string, count int, zone func(float64) int, startIdx, endIdx int) []time.Duration {
+	points := d.Points
+	if len(points) == 0 || startIdx < 0 || endIdx >= len(points) || startIdx > endIdx {
+		return nil
+	}
+
+	durations := make([]time.Duration, count)
+	found := false
+
+	for i := startIdx; i <= endIdx; i++ {
+		p := &points[i]
+
+		v, ok := p.ExtraMetrics[metric]
+		if !ok || math.IsNaN(v) || v <= 0 || p.Duration <= 0 || p.Duration > ZoneMaxGap {
+			continue
+		}
+
+		durations[zone(v)-1] += p.Duration
+		found = true
+	}
+
+	if !found {
+		return nil
+	}
+
+	return durations
+}
+
+// UpdateHeartRateZones calculates the time the workout spent in each of the
+// heart rate zones of its user
+func (w *Workout) UpdateHeartRateZones(db *gorm.DB) error {
+	if w.Data == nil {
+		return nil
+	}
+
+	w.Data.HeartRateZones = nil
+
+	if w.Data.Details == nil || !w.HasHeartRate() {
+		return nil
+	}
+
+	u, err := w.userWithMeasurements(db)
+	if err != nil {
+		return err
+	}
+
+	zones := u.HeartRateZonesAt(w.Date)
+	w.Data.HeartRateZones = w.Data.Details.TimeInHeartRateZones(zones, 0, len(w.Data.Details.Points)-1)
+
+	return nil
+}
+
+// userWithMeasurements returns the user of the workout, able to look up their
+// measurements
+func (w *Workout) userWithMeasurements(db *gorm.DB) (*User, error) {
+	if w.User != nil && w.User.db != nil {
+		return w.User, nil
+	}
+
+	u := &User{}
+	if err := db.Preload("Profile").First(u, w.UserID).Error; err != nil {
+		return nil, err
+	}
+
+	u.db = db
+
+	return u, nil
+}
+
+// MarkHeartRateWorkoutsDirty marks the workouts of the user with a heart
+// rate from the time on (all workouts if the time is zero) for refresh, e.g.
+// because their heart rate zones changed; it returns the IDs of the marked
+// workouts
+func (u *User) MarkHeartRateWorkoutsDirty(db *gorm.DB, from time.Time) ([]uint64, error) {
+	return u.markMetricWorkoutsDirty(db, "heart-rate", from)
+}
+
+// markMetricWorkoutsDirty marks the workouts of the user with the extra
+// metric from the time on (all workouts if the time is zero) for refresh; it
+// returns the IDs of the marked workouts
+func (u *User) markMetricWorkoutsDirty(db *gorm.DB, metric string, from time.Time) ([]uint64, error) {
+	var ids []uint64
+
+	q := db.Model(&Workout{}).
+		Joins("join map_data on workouts.id = map_data.workout_id").
+		Where("workouts.user_id = ?", u.ID).
+		Where("map_data.extra_metrics LIKE ?", `%"`+metric+`"%`)
+
+	if !from.IsZero() {
+		q = q.Where("workouts.date >= ?", from)
+	}
+
+	if err := q.Pluck("workouts.id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	return ids, db.Model(&Workout{}).Where("id IN ?", ids).Update("dirty", true).Error
+}
+
+// WeeklyHeartRateZones is the time spent in each of the heart rate zones, in
+// all workouts of a week
+type WeeklyHeartRateZones struct {
+	Week      time.Time       `json:"week"`      // The first day of the week
+	Durations []time.Duration `json:"durations"` // The time spent in each zone
+}
+
+// GetWeeklyHeartRateZones returns the time spent in each of the heart rate
+// zones per week, for the period of the statistics
+func (u *User) GetWeeklyHeartRateZones(statConfig StatConfig) ([]WeeklyHeartRateZones, error) {
+	var rows []struct {
+		Date           time.Time
+		HeartRateZones []time.Duration `gorm:"serializer:json"`
+	}
+
+	q := u.db.
+		Table("workouts").
+		Select("workouts.date", "map_data.heart_rate_zones").
+		Joins("join map_data on workouts.id = map_data.workout_id").
+		Where("user_id = ?", u.ID).
+		Where("map_data.heart_rate_zones IS NOT NULL")
+
+	if statConfig.Since != "" && statConfig.Since != "forever" {
+		q = q.Where(GetDateLimitExpression(u.db.Dialector.Name()), "-"+statConfig.GetSince())
+	}
+
+	if err := q.Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	loc := u.Timezone()
+	weeks := map[time.Time]*WeeklyHeartRateZones{}
+
+	for _, r := range rows {
+		if len(r.HeartRateZones) == 0 {
+			continue
+		}
+
+		d := r.Date.In(loc)
+		start := time.Date(d.Year(), d.Month(), d.Day()-(int(d.Weekday())+6)%7, 0, 0, 0, 0, loc)
+
+		week, ok := weeks[start]
+		if !ok {
+			week = &WeeklyHeartRateZones{Week: start}
+			weeks[start] = week
+		}
+
+		for i, dur := range r.HeartRateZones {
+			if i >= len(week.Durations) {
+				week.Durations = append(week.Durations, 0)
+			}
+
+			week.Durations[i] += dur
+		}
+	}
+
+	result := make([]WeeklyHeartRateZones, 0, len(weeks))
+	for _, week := range weeks {
+		result = append(result, *week)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Week.Before(result[j].Week)
+	})
+
+	return result, nil
+}
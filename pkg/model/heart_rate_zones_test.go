@@ -0,0 +1,164 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHeartRateZones(t *testing.T) {
+	reserve := NewHeartRateReserveZones(190, 50)
+	assert.Equal(t, []float64{134, 148, 162, 176}, reserve.Bounds)
+	assert.Equal(t, 5, reserve.Count())
+
+	percent := NewPercentMaxHeartRateZones(200)
+	assert.Equal(t, []float64{120, 140, 160, 180}, percent.Bounds)
+
+	threshold := NewThresholdHeartRateZones(160)
+	assert.InDeltaSlice(t, []float64{136, 144, 152, 160}, threshold.Bounds, 0.001)
+
+	assert.Equal(t, 1, percent.Zone(90))
+	assert.Equal(t, 2, percent.Zone(120))
+	assert.Equal(t, 4, percent.Zone(179))
+	assert.Equal(t, 5, percent.Zone(220))
+
+	require.NoError(t, HeartRateZoneModelThreshold.Validate())
+	require.ErrorIs(t, HeartRateZoneModel("zone-2").Validate(), ErrInvalidHeartRateZoneModel)
+}
+
+func TestMapDataDetails_TimeInHeartRateZones(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	zones := NewPercentMaxHeartRateZones(200)
+
+	d := &MapDataDetails{}
+	for i, hr := range []float64{0, 110, 130, 130, 190, 150} {
+		p := MapPoint{Time: start.Add(time.Duration(i) * 10 * time.Second), Duration: 10 * time.Second}
+		if hr > 0 {
+			p.ExtraMetrics = ExtraMetrics{"heart-rate": hr}
+		}
+
+		d.Points = append(d.Points, p)
+	}
+
+	// A pause of the recording is not counted
+	d.Points[5].Duration = time.Hour
+
+	assert.Equal(t, []time.Duration{10 * time.Second, 20 * time.Second, 0, 0, 10 * time.Second}, d.TimeInHeartRateZones(zones, 0, 5))
+	assert.Equal(t, []time.Duration{0, 10 * time.Second, 0, 0, 0}, d.TimeInHeartRateZones(zones, 3, 3))
+	assert.Nil(t, d.TimeInHeartRateZones(zones, 0, 0))
+	assert.Nil(t, d.TimeInHeartRateZones(zones, 4, 9))
+}
+
+func TestUser_HeartRateZonesAt(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+	u.SetDB(db)
+
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	m := u.NewMeasurement(date)
+	m.MaxHeartRate = 190
+	m.RestingHeartRate = 50
+	require.NoError(t, m.Save(db))
+
+	assert.Equal(t, NewHeartRateReserveZones(190, 50), u.HeartRateZonesAt(date.Add(time.Hour)))
+	// Measurements after the workout are not used
+	assert.Equal(t, NewHeartRateReserveZones(200, 60), u.HeartRateZonesAt(date.Add(-time.Hour)))
+
+	u.Profile.HeartRateZoneModel = HeartRateZoneModelPercentMax
+	assert.Equal(t, NewPercentMaxHeartRateZones(190), u.HeartRateZonesAt(date))
+
+	// Without a lactate threshold, the heart rate reserve is used
+	u.Profile.HeartRateZoneModel = HeartRateZoneModelThreshold
+	assert.Equal(t, HeartRateZoneModelReserve, u.HeartRateZonesAt(date).Model)
+
+	m.LTHR = 170
+	require.NoError(t, m.Save(db))
+	assert.Equal(t, NewThresholdHeartRateZones(170), u.HeartRateZonesAt(date))
+}
+
+func TestUser_GetWeeklyHeartRateZones(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+	u.SetDB(db)
+
+	for _, day := range []int{-2, 3, 4} {
+		start := time.Now().UTC().Truncate(24*time.Hour).AddDate(0, 0, -14+day).Add(8 * time.Hour)
+		w := duplicateTestWorkout(u, "zones", start, false, ExtraMetrics{"heart-rate": 150})
+		w.Data.ExtraMetrics = []string{"heart-rate"}
+
+		for i := range w.Data.Details.Points {
+			w.Data.Details.Points[i].Duration = 10 * time.Second
+		}
+
+		require.NoError(t, w.UpdateHeartRateZones(db))
+		require.NoError(t, w.Save(db))
+	}
+
+	weeks, err := u.GetWeeklyHeartRateZones(StatConfig{Since: "1 month"})
+	require.NoError(t, err)
+	require.NotEmpty(t, weeks)
+
+	total := time.Duration(0)
+	for _, w := range weeks {
+		assert.Equal(t, time.Monday, w.Week.Weekday())
+		require.Len(t, w.Durations, 5)
+
+		// 150 bpm is zone 2 of the default heart rate reserve (60-200)
+		total += w.Durations[1]
+	}
+
+	assert.Equal(t, 3*60*10*time.Second, total)
+}
+
+func TestUser_MarkMeasurementWorkoutsDirty(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+	u.SetDB(db)
+
+	date := time.Date(2024, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	// A workout with a heart rate the day before, one on the day of the
+	// measurement, and one with power the day after
+	var ids []uint64
+
+	for i, metric := range []string{"heart-rate", "heart-rate", "power"} {
+		w := duplicateTestWorkout(u, metric, date.AddDate(0, 0, i-1).Add(8*time.Hour), false, ExtraMetrics{metric: 150})
+		w.Data.ExtraMetrics = []string{metric}
+		require.NoError(t, w.Save(db))
+
+		ids = append(ids, w.ID)
+	}
+
+	before := u.NewMeasurement(date)
+	after := u.NewMeasurement(date)
+	after.Weight = 80
+
+	marked, err := u.MarkMeasurementWorkoutsDirty(db, before, after)
+	require.NoError(t, err)
+	assert.Empty(t, marked)
+
+	after.MaxHeartRate = 190
+
+	marked, err = u.MarkMeasurementWorkoutsDirty(db, before, after)
+	require.NoError(t, err)
+	assert.Equal(t, ids[1:2], marked)
+
+	after.FTP = 250
+
+	marked, err = u.MarkMeasurementWorkoutsDirty(db, before, after)
+	require.NoError(t, err)
+	assert.Equal(t, ids[1:], marked)
+
+	w, err := GetWorkout(db, ids[2])
+	require.NoError(t, err)
+	assert.True(t, w.Dirty)
+}
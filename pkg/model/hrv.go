@@ -0,0 +1,329 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+)
+
+const (
+	// DefaultHRVWindow is the length of the windows over which the heart-rate
+	// variability is analyzed; DFA alpha1 needs about 2 minutes of beats
+	DefaultHRVWindow = 2 * time.Minute
+	// DefaultHRVStep is the time between the start of two windows
+	DefaultHRVStep = 30 * time.Second
+
+	// RR intervals outside this range (in ms) are artifacts, i.e. a missed or
+	// an extra beat, rather than heart beats (30-200 bpm)
+	hrvMinRR = 300.0
+	hrvMaxRR = 2000.0
+	// hrvMaxRRChange is the maximum relative change between consecutive beats
+	hrvMaxRRChange = 0.2
+
+	// The range of box sizes (in beats) used for DFA alpha1, the short-term
+	// scaling exponent
+	dfaMinBox = 4
+	dfaMaxBox = 16
+	// dfaMinBeats is the minimum number of beats needed to estimate alpha1
+	dfaMinBeats = 50
+)
+
+var ErrNoHRVData = errors.New("workout has no heart-rate variability data")
+
+// HRVAnalysis is the heart-rate variability of a workout, calculated from the
+// beat-to-beat (RR) intervals
+type HRVAnalysis struct {
+	Beats     int     `json:"beats"`     // The number of beats
+	Artifacts int     `json:"artifacts"` // The number of beats discarded as artifact
+	MeanRR    float64 `json:"meanRR"`    // The average RR interval, in ms
+	HeartRate float64 `json:"heartRate"` // The average heart rate, based on the RR intervals
+	RMSSD     float64 `json:"rmssd"`     // The root mean square of successive differences, in ms
+	SDNN      float64 `json:"sdnn"`      // The standard deviation of the RR intervals, in ms
+	DFAAlpha1 float64 `json:"dfaAlpha1"` // The short-term scaling exponent of the detrended fluctuation analysis
+
+	Windows []HRVWindow `json:"windows"` // The heart-rate variability over time
+}
+
+// HRVWindow is the heart-rate variability of a window of the workout
+type HRVWindow struct {
+	Start     time.Time     `json:"start"`     // The start of the window
+	Offset    time.Duration `json:"offset"`    // The time between the start of the workout and the window
+	Beats     int           `json:"beats"`     // The number of beats in the window, without artifacts
+	Artifacts int           `json:"artifacts"` // The number of beats discarded as artifact
+	HeartRate float64       `json:"heartRate"`
+	RMSSD     float64       `json:"rmssd"`
+	SDNN      float64       `json:"sdnn"`
+	DFAAlpha1 float64       `json:"dfaAlpha1"` // 0 when the window has too few beats
+}
+
+// HasHRV returns whether the workout has beat-to-beat intervals, e.g. from a
+// chest strap
+func (w *Workout) HasHRV() bool {
+	return w.Data != nil && w.Data.Details != nil && len(w.Data.Details.RRIntervals) > 0
+}
+
+// HRV analyzes the heart-rate variability of the workout over windows of the
+// given length
+func (w *Workout) HRV(window, step time.Duration) (*HRVAnalysis, error) {
+	if !w.HasHRV() {
+		return nil, ErrNoHRVData
+	}
+
+	start := w.Data.Start
+	if start.IsZero() {
+		start = w.Date
+	}
+
+	return AnalyzeHRV(start, w.Data.Details.RRIntervals, window, step), nil
+}
+
+// AnalyzeHRV calculates the heart-rate variability of a series of RR
+// intervals (in ms) starting at start, in total and per window
+func AnalyzeHRV(start time.Time, rr []float64, window, step time.Duration) *HRVAnalysis {
+	if window <= 0 {
+		window = DefaultHRVWindow
+	}
+
+	if step <= 0 {
+		step = DefaultHRVStep
+	}
+
+	beats := hrvBeats(rr)
+	clean := hrvCleanIntervals(beats, 0, len(beats))
+
+	a := &HRVAnalysis{
+		Beats:     len(rr),
+		Artifacts: len(rr) - len(clean),
+	}
+	a.fill(clean)
+
+	if len(beats) == 0 {
+		return a
+	}
+
+	total := beats[len(beats)-1].offset
+	first := 0
+
+	for offset := time.Duration(0); offset == 0 || offset+window <= total; offset += step {
+		for first < len(beats) && beats[first].offset < offset {
+			first++
+		}
+
+		last := first
+		for last < len(beats) && beats[last].offset < offset+window {
+			last++
+		}
+
+		intervals := hrvCleanIntervals(beats, first, last)
+		if len(intervals) < 2 {
+			continue
+		}
+
+		hw := HRVWindow{
+			Start:     start.Add(offset),
+			Offset:    offset,
+			Beats:     len(intervals),
+			Artifacts: last - first - len(intervals),
+			HeartRate: 60000 / mean(intervals),
+			RMSSD:     rmssd(intervals),
+			SDNN:      stddev(intervals),
+			DFAAlpha1: dfaAlpha1(intervals),
+		}
+
+		a.Windows = append(a.Windows, hw)
+	}
+
+	return a
+}
+
+func (a *HRVAnalysis) fill(intervals []float64) {
+	if len(intervals) == 0 {
+		return
+	}
+
+	a.MeanRR = mean(intervals)
+	a.HeartRate = 60000 / a.MeanRR
+	a.RMSSD = rmssd(intervals)
+	a.SDNN = stddev(intervals)
+	a.DFAAlpha1 = dfaAlpha1(intervals)
+}
+
+type hrvBeat struct {
+	offset   time.Duration // The time of the beat since the start
+	interval float64       // The RR interval to this beat, in ms
+	artifact bool
+}
+
+// hrvBeats positions the beats in time and marks the artifacts: intervals
+// which are out of range, or change too much compared to the previous valid
+// interval
+func hrvBeats(rr []float64) []hrvBeat {
+	beats := make([]hrvBeat, 0, len(rr))
+
+	var (
+		offset time.Duration
+		prev   float64
+	)
+
+	for _, interval := range rr {
+		offset += time.Duration(interval * float64(time.Millisecond))
+
+		b := hrvBeat{offset: offset, interval: interval}
+
+		switch {
+		case interval < hrvMinRR || interval > hrvMaxRR:
+			b.artifact = true
+		case prev > 0 && math.Abs(interval-prev)/prev > hrvMaxRRChange:
+			b.artifact = true
+		default:
+			prev = interval
+		}
+
+		beats = append(beats, b)
+	}
+
+	return beats
+}
+
+func hrvCleanIntervals(beats []hrvBeat, from, to int) []float64 {
+	intervals := make([]float64, 0, to-from)
+
+	for _, b := range beats[from:to] {
+		if !b.artifact {
+			intervals = append(intervals, b.interval)
+		}
+	}
+
+	return intervals
+}
+
+func mean(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+
+	sum := 0.0
+	for _, v := range values {
+		sum += v
+	}
+
+	return sum / float64(len(values))
+}
+
+func stddev(values []float64) float64 {
+	if len(values) < 2 {
+		return 0
+	}
+
+	m := mean(values)
+	sum := 0.0
+
+	for _, v := range values {
+		sum += (v - m) * (v - m)
+	}
+
+	return math.Sqrt(sum / float64(len(values)-1))
+}
+
+func rmssd(intervals []float64) float64 {
+	if len(intervals) < 2 {
+		return 0
+	}
+
+	sum := 0.0
+
+	for i := 1; i < len(intervals); i++ {
+		d := intervals[i] - intervals[i-1]
+		sum += d * d
+	}
+
+	return math.Sqrt(sum / float64(len(intervals)-1))
+}
+
+// dfaAlpha1 calculates the short-term scaling exponent of the detrended
+// fluctuation analysis: the slope of log F(n) versus log n, for box sizes of 4
+// to 16 beats; it is around 0.75 at the aerobic threshold
+func dfaAlpha1(intervals []float64) float64 {
+	if len(intervals) < dfaMinBeats {
+		return 0
+	}
+
+	// The integrated series, i.e. the cumulative sum of the deviations from
+	// the mean
+	m := mean(intervals)
+	y := make([]float64, len(intervals))
+	sum := 0.0
+
+	for i, v := range intervals {
+		sum += v - m
+		y[i] = sum
+	}
+
+	var logN, logF []float64
+
+	for n := dfaMinBox; n <= dfaMaxBox; n++ {
+		boxes := len(y) / n
+		if boxes == 0 {
+			break
+		}
+
+		sq := 0.0
+		for b := range boxes {
+			sq += detrendedSquares(y[b*n : (b+1)*n])
+		}
+
+		f := math.Sqrt(sq / float64(boxes*n))
+		if f <= 0 {
+			continue
+		}
+
+		logN = append(logN, math.Log(float64(n)))
+		logF = append(logF, math.Log(f))
+	}
+
+	if len(logN) < 2 {
+		return 0
+	}
+
+	slope, _ := linearRegression(logN, logF)
+
+	return slope
+}
+
+// detrendedSquares returns the sum of the squared residuals of the series
+// after removing its linear (least-squares) trend
+func detrendedSquares(y []float64) float64 {
+	x := make([]float64, len(y))
+	for i := range x {
+		x[i] = float64(i)
+	}
+
+	slope, intercept := linearRegression(x, y)
+	sum := 0.0
+
+	for i, v := range y {
+		r := v - (slope*x[i] + intercept)
+		sum += r * r
+	}
+
+	return sum
+}
+
+func linearRegression(x, y []float64) (slope, intercept float64) {
+	mx, my := mean(x), mean(y)
+
+	var num, den float64
+
+	for i := range x {
+		num += (x[i] - mx) * (y[i] - my)
+		den += (x[i] - mx) * (x[i] - mx)
+	}
+
+	if den == 0 {
+		return 0, my
+	}
+
+	slope = num / den
+
+	return slope, my - slope*mx
+}
@@ -0,0 +1,100 @@
+package model
+
+import (
+	"math/rand/v2"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAnalyzeHRV_Statistics(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	rr := []float64{800, 810, 790, 800, 810, 790}
+
+	a := AnalyzeHRV(start, rr, 0, 0)
+
+	assert.Equal(t, 6, a.Beats)
+	assert.Equal(t, 0, a.Artifacts)
+	assert.InDelta(t, 800, a.MeanRR, 0.001)
+	assert.InDelta(t, 75, a.HeartRate, 0.001)
+	// Successive differences: 10, -20, 10, 10, -20
+	assert.InDelta(t, 14.832, a.RMSSD, 0.001)
+	assert.InDelta(t, 8.944, a.SDNN, 0.001)
+	// Too few beats for DFA alpha1
+	assert.Zero(t, a.DFAAlpha1)
+
+	require.Len(t, a.Windows, 1)
+	assert.Equal(t, start, a.Windows[0].Start)
+	assert.Equal(t, 6, a.Windows[0].Beats)
+}
+
+func TestAnalyzeHRV_Artifacts(t *testing.T) {
+	rr := []float64{800, 810, 250, 790, 1600, 800, 2500}
+
+	a := AnalyzeHRV(time.Now(), rr, 0, 0)
+
+	assert.Equal(t, 7, a.Beats)
+	assert.Equal(t, 3, a.Artifacts)
+	assert.InDelta(t, 800, a.MeanRR, 0.001)
+}
+
+func TestAnalyzeHRV_Windows(t *testing.T) {
+	start := time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+
+	// 10 minutes at 60 bpm
+	rr := make([]float64, 600)
+	for i := range rr {
+		rr[i] = 1000
+	}
+
+	a := AnalyzeHRV(start, rr, 2*time.Minute, time.Minute)
+
+	// Windows start at 0, 1, ..., 8 minutes
+	require.Len(t, a.Windows, 9)
+	assert.Equal(t, start.Add(8*time.Minute), a.Windows[8].Start)
+	assert.Equal(t, 8*time.Minute, a.Windows[8].Offset)
+	assert.InDelta(t, 60, a.Windows[3].HeartRate, 0.001)
+	assert.Zero(t, a.Windows[3].RMSSD)
+}
+
+func TestDFAAlpha1(t *testing.T) {
+	r := rand.New(rand.NewPCG(1, 2))
+
+	// Uncorrelated intervals (white noise) have an alpha1 of about 0.5
+	noise := make([]float64, 1000)
+	for i := range noise {
+		noise[i] = 800 + r.NormFloat64()*20
+	}
+
+	assert.InDelta(t, 0.5, dfaAlpha1(noise), 0.15)
+
+	// Strongly correlated intervals (a random walk) have an alpha1 of about 1.5
+	walk := make([]float64, 1000)
+	v := 800.0
+
+	for i := range walk {
+		v += r.NormFloat64() * 5
+		walk[i] = v
+	}
+
+	assert.InDelta(t, 1.5, dfaAlpha1(walk), 0.2)
+
+	assert.Zero(t, dfaAlpha1(noise[:dfaMinBeats-1]))
+}
+
+func TestWorkout_HRV(t *testing.T) {
+	w := &Workout{Data: &MapData{Details: &MapDataDetails{}}}
+
+	_, err := w.HRV(0, 0)
+	require.ErrorIs(t, err, ErrNoHRVData)
+
+	w.Data.Start = time.Date(2024, 3, 1, 10, 0, 0, 0, time.UTC)
+	w.Data.Details.RRIntervals = []float64{1000, 1000, 1000}
+
+	a, err := w.HRV(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 3, a.Beats)
+	assert.Equal(t, w.Data.Start, a.Windows[0].Start)
+}
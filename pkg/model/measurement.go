@@ -1,6 +1,7 @@
 package model
 
 import (
+	"slices"
 	"time"
 
 	"gorm.io/datatypes"
@@ -16,6 +17,11 @@ type Measurement struct {
 	FTP              float64        `form:"ftp" json:"ftp"`                                                   // Functional Threshold Power, in watts
 	RestingHeartRate float64        `form:"resting_heart_rate" json:"resting_heart_rate"`                     // Resting heart rate, in bpm
 	MaxHeartRate     float64        `form:"max_heart_rate" json:"max_heart_rate"`                             // Maximum heart rate, in bpm
+	LTHR             float64        `form:"lthr" json:"lthr"`                                                 // Lactate threshold heart rate, in bpm
+	BodyFat          float64        `form:"body_fat" json:"body_fat"`                                         // Body fat, in percent
+	MuscleMass       float64        `form:"muscle_mass" json:"muscle_mass"`                                   // Muscle mass, in kilograms
+	BoneMass         float64        `form:"bone_mass" json:"bone_mass"`                                       // Bone mass, in kilograms
+	BodyWater        float64        `form:"body_water" json:"body_water"`                                     // Body water, in percent
 	UserID           uint64         `gorm:"not null;index;uniqueIndex:idx_user_date" json:"userID"`           // The ID of the user who owns the workout
 }
 
@@ -26,8 +32,112 @@ func (u *User) NewMeasurement(d time.Time) *Measurement {
 	}
 }
 
+// MergeNonZero copies non-zero values from the provided measurement into the
+// receiver; the date and user are kept
+//
+//gocyclo:ignore
+func (m *Measurement) MergeNonZero(from Measurement) {
+	if from.Weight != 0 {
+		m.Weight = from.Weight
+	}
+
+	if from.Height != 0 {
+		m.Height = from.Height
+	}
+
+	if from.Steps != 0 {
+		m.Steps = from.Steps
+	}
+
+	if from.FTP != 0 {
+		m.FTP = from.FTP
+	}
+
+	if from.RestingHeartRate != 0 {
+		m.RestingHeartRate = from.RestingHeartRate
+	}
+
+	if from.MaxHeartRate != 0 {
+		m.MaxHeartRate = from.MaxHeartRate
+	}
+
+	if from.LTHR != 0 {
+		m.LTHR = from.LTHR
+	}
+
+	if from.BodyFat != 0 {
+		m.BodyFat = from.BodyFat
+	}
+
+	if from.MuscleMass != 0 {
+		m.MuscleMass = from.MuscleMass
+	}
+
+	if from.BoneMass != 0 {
+		m.BoneMass = from.BoneMass
+	}
+
+	if from.BodyWater != 0 {
+		m.BodyWater = from.BodyWater
+	}
+}
+
+// HeartRateChanged returns whether the values the heart rate zones are derived
+// from differ from the other measurement
+func (m *Measurement) HeartRateChanged(other *Measurement) bool {
+	return m.RestingHeartRate != other.RestingHeartRate ||
+		m.MaxHeartRate != other.MaxHeartRate ||
+		m.LTHR != other.LTHR
+}
+
+// FTPChanged returns whether the FTP, which the power zones are derived from,
+// differs from the other measurement
+func (m *Measurement) FTPChanged(other *Measurement) bool {
+	return m.FTP != other.FTP
+}
+
+// MarkMeasurementWorkoutsDirty marks the workouts of the user from the date of
+// the measurement on for refresh when the measurement changed the heart rate
+// or power values their zones are derived from; it returns the IDs of the
+// marked workouts
+func (u *User) MarkMeasurementWorkoutsDirty(db *gorm.DB, before, after *Measurement) ([]uint64, error) {
+	from := time.Time(after.Date)
+
+	var ids []uint64
+
+	if after.HeartRateChanged(before) {
+		marked, err := u.MarkHeartRateWorkoutsDirty(db, from)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, marked...)
+	}
+
+	if after.FTPChanged(before) {
+		marked, err := u.MarkPowerWorkoutsDirty(db, from)
+		if err != nil {
+			return nil, err
+		}
+
+		ids = append(ids, marked...)
+	}
+
+	slices.Sort(ids)
+
+	return slices.Compact(ids), nil
+}
+
+// Save stores the measurement; the training load from its date on is
+// calculated again, since it depends on the heart rate measurements
 func (m *Measurement) Save(db *gorm.DB) error {
-	return db.Save(m).Error
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(m).Error; err != nil {
+			return err
+		}
+
+		return invalidateTrainingLoads(tx, m.UserID, time.Time(m.Date))
+	})
 }
 
 func (m *Measurement) Time() *time.Time {
@@ -35,8 +145,16 @@ func (m *Measurement) Time() *time.Time {
 	return &t
 }
 
+// Delete removes the measurement; the training load from its date on is
+// calculated again
 func (m *Measurement) Delete(db *gorm.DB) error {
-	return db.Delete(m).Error
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(m).Error; err != nil {
+			return err
+		}
+
+		return invalidateTrainingLoads(tx, m.UserID, time.Time(m.Date))
+	})
 }
 
 func (m *Measurement) DateString() string {
@@ -0,0 +1,119 @@
+package model
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Multisport groups the legs of a multisport activity (e.g. a triathlon or a
+// brick session), which are recorded as a single file with a session per sport;
+// every leg is stored as a separate workout
+type Multisport struct {
+	Model
+	Date        time.Time              `gorm:"not null" json:"date"`                                                       // The start of the first leg
+	User        *User                  `gorm:"foreignKey:UserID" json:"-"`                                                 // The user who owns the activity
+	Legs        []*Workout             `gorm:"foreignKey:MultisportID;constraint:OnDelete:SET NULL" json:"legs,omitempty"` // The workouts of the individual sports
+	Transitions []MultisportTransition `gorm:"serializer:json" json:"transitions"`                                         // The transitions between the legs
+	Name        string                 `gorm:"not null" json:"name"`                                                       // The name of the activity
+	UserID      uint64                 `gorm:"not null;index" json:"userID"`                                               // The ID of the user who owns the activity
+}
+
+// MultisportTransition is the time spent between two legs, e.g. changing
+// from the swim to the bike
+type MultisportTransition struct {
+	Start    time.Time     `json:"start"`    // The start of the transition
+	Duration time.Duration `json:"duration"` // The duration of the transition
+	Distance float64       `json:"distance"` // The distance covered during the transition, in meters
+}
+
+func (m *Multisport) Save(db *gorm.DB) error {
+	return db.Omit("Legs").Save(m).Error
+}
+
+// TransitionDuration returns the total time spent in transitions
+func (m *Multisport) TransitionDuration() time.Duration {
+	var d time.Duration
+
+	for _, t := range m.Transitions {
+		d += t.Duration
+	}
+
+	return d
+}
+
+// TransitionDistance returns the total distance covered in transitions
+func (m *Multisport) TransitionDistance() float64 {
+	var d float64
+
+	for _, t := range m.Transitions {
+		d += t.Distance
+	}
+
+	return d
+}
+
+// TotalDistance returns the distance of all legs and transitions combined
+func (m *Multisport) TotalDistance() float64 {
+	d := m.TransitionDistance()
+
+	for _, l := range m.Legs {
+		d += l.TotalDistance()
+	}
+
+	return d
+}
+
+// TotalDuration returns the duration of all legs and transitions combined
+func (m *Multisport) TotalDuration() time.Duration {
+	d := m.TransitionDuration()
+
+	for _, l := range m.Legs {
+		d += l.TotalDuration()
+	}
+
+	return d
+}
+
+// linkMultisport stores the multisport activity of a leg that is about to be
+// created, if it was not yet stored by one of the other legs; it returns
+// whether the activity was created
+func (w *Workout) linkMultisport(tx *gorm.DB) (bool, error) {
+	if w.Multisport == nil {
+		return false, nil
+	}
+
+	created := false
+
+	if w.Multisport.ID == 0 {
+		w.Multisport.UserID = w.UserID
+		if w.Multisport.Date.IsZero() {
+			w.Multisport.Date = w.Date
+		}
+
+		if err := w.Multisport.Save(tx); err != nil {
+			return false, err
+		}
+
+		created = true
+	}
+
+	w.MultisportID = &w.Multisport.ID
+
+	return created, nil
+}
+
+// deleteEmptyMultisport removes a multisport activity after its last leg was
+// deleted
+func deleteEmptyMultisport(db *gorm.DB, id uint64) error {
+	var legs int64
+	if err := db.Model(&Workout{}).Where("multisport_id = ?", id).Count(&legs).Error; err != nil {
+		return err
+	}
+
+	if legs > 0 {
+		return nil
+	}
+
+	return db.Delete(&Multisport{}, id).Error
+}
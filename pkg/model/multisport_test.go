@@ -0,0 +1,90 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func multisportLegs(t *testing.T) []*Workout {
+	t.Helper()
+
+	start := time.Date(2024, 6, 2, 8, 0, 0, 0, time.UTC)
+	multisport := &Multisport{
+		Name: "multisport",
+		Transitions: []MultisportTransition{
+			{Start: start.Add(10 * time.Minute), Duration: 2 * time.Minute, Distance: 100},
+		},
+	}
+
+	legs := make([]*Workout, 0, 2)
+
+	for i, d := range []float64{500, 2000} {
+		w := &Workout{
+			Name:       "leg",
+			Date:       start.Add(time.Duration(i) * 12 * time.Minute),
+			UserID:     1,
+			Multisport: multisport,
+			Data: &MapData{WorkoutData: WorkoutData{
+				TotalDistance: d,
+				TotalDuration: 10 * time.Minute,
+			}},
+		}
+		w.SetContent("triathlon.fit", []byte("content"))
+		w.SetPart(i)
+
+		legs = append(legs, w)
+	}
+
+	return legs
+}
+
+func TestMultisport_CreateLegs(t *testing.T) {
+	db := createMemoryDB(t)
+	createDefaultUser(t, db)
+
+	legs := multisportLegs(t)
+	for _, w := range legs {
+		require.NoError(t, w.Create(db))
+	}
+
+	require.NotNil(t, legs[0].MultisportID)
+	assert.Equal(t, legs[0].MultisportID, legs[1].MultisportID)
+
+	var m Multisport
+	require.NoError(t, db.Preload("Legs.Data").First(&m, *legs[0].MultisportID).Error)
+
+	assert.Equal(t, uint64(1), m.UserID)
+	assert.Equal(t, legs[0].Date, m.Date.UTC())
+	assert.Len(t, m.Legs, 2)
+	assert.InDelta(t, 2600, m.TotalDistance(), 0.01)
+	assert.Equal(t, 22*time.Minute, m.TotalDuration())
+
+	// The multisport activity is removed with its last leg
+	require.NoError(t, legs[0].Delete(db))
+	require.NoError(t, db.First(&Multisport{}, m.ID).Error)
+
+	require.NoError(t, legs[1].Delete(db))
+	require.Error(t, db.First(&Multisport{}, m.ID).Error)
+}
+
+func TestMultisport_CreateDuplicateLeg(t *testing.T) {
+	db := createMemoryDB(t)
+	createDefaultUser(t, db)
+
+	legs := multisportLegs(t)
+	require.NoError(t, legs[0].Create(db))
+
+	duplicates := multisportLegs(t)
+	require.ErrorIs(t, duplicates[0].Create(db), ErrWorkoutAlreadyExists)
+
+	// The multisport activity of the duplicate was rolled back
+	assert.Nil(t, duplicates[0].MultisportID)
+	assert.Zero(t, duplicates[0].Multisport.ID)
+
+	var count int64
+	require.NoError(t, db.Model(&Multisport{}).Count(&count).Error)
+	assert.Equal(t, int64(1), count)
+}
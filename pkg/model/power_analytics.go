@@ -0,0 +1,195 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// NormalizedPowerWindow is the length of the rolling average of the power,
+// before it is normalized
+const NormalizedPowerWindow = 30 * time.Second
+
+// PowerZones are the seven power zones of Coggan, relative to the functional
+// threshold power (FTP) of a user
+type PowerZones struct {
+	FTP    float64   `json:"ftp"`    // The functional threshold power the zones are derived from
+	Bounds []float64 `json:"bounds"` // The lower bounds of zones 2 and up, in watts
+}
+
+// NewPowerZones returns the zones at 55, 75, 90, 105, 120 and 150 percent of
+// the FTP, or nil without an FTP
+func NewPowerZones(ftp float64) *PowerZones {
+	if ftp <= 0 {
+		return nil
+	}
+
+	return &PowerZones{
+		FTP:    ftp,
+		Bounds: []float64{0.55 * ftp, 0.75 * ftp, 0.9 * ftp, 1.05 * ftp, 1.2 * ftp, 1.5 * ftp},
+	}
+}
+
+// Count returns the number of zones
+func (z PowerZones) Count() int {
+	return len(z.Bounds) + 1
+}
+
+// Zone returns the zone of the power, starting at 1
+func (z PowerZones) Zone(power float64) int {
+	return sort.Search(len(z.Bounds), func(i int) bool {
+		return power < z.Bounds[i]
+	}) + 1
+}
+
+// PowerZonesAt returns the power zones of the user, with the FTP valid at the
+// date, or nil if the user has no FTP yet
+func (u *User) PowerZonesAt(d time.Time) *PowerZones {
+	return NewPowerZones(u.FTPAt(d))
+}
+
+// MarkPowerWorkoutsDirty marks the workouts of the user with power from the
+// time on (all workouts if the time is zero) for refresh, e.g. because their
+// FTP changed; it returns the IDs of the marked workouts
+func (u *User) MarkPowerWorkoutsDirty(db *gorm.DB, from time.Time) ([]uint64, error) {
+	return u.markMetricWorkoutsDirty(db, "power", from)
+}
+
+// TimeInPowerZones returns the time spent in each of the zones, between the
+// points with the indexes (inclusive); it returns nil without zones or when
+// the points have no power
+func (d *MapDataDetails) TimeInPowerZones(z *PowerZones, startIdx, endIdx int) []time.Duration {
+	if z == nil {
+		return nil
+	}
+
+	return d.timeInZones("power", z.Count(), z.Zone, startIdx, endIdx)
+}
+
+// normalizedPower returns the normalized power of the points and the duration
+// it was calculated over. The power is resampled per second, holding the power
+// of a point over its duration; points without power count as 0 and pauses of
+// the recording are skipped. The normalized power is the fourth root of the
+// average of the fourth power of the rolling 30 second average.
+func normalizedPower(points []MapPoint) (float64, time.Duration) {
+	var (
+		samples []float64
+		carry   time.Duration
+		found   bool
+	)
+
+	for _, p := range points {
+		if p.Duration <= 0 || p.Duration > ZoneMaxGap {
+			continue
+		}
+
+		power, ok := p.ExtraMetrics["power"]
+		if !ok || math.IsNaN(power) || power < 0 {
+			power = 0
+		}
+
+		if power > 0 {
+			found = true
+		}
+
+		carry += p.Duration
+		for ; carry >= time.Second; carry -= time.Second {
+			samples = append(samples, power)
+		}
+	}
+
+	window := int(NormalizedPowerWindow / time.Second)
+	if !found || len(samples) < window {
+		return 0, 0
+	}
+
+	var sum, total float64
+
+	for i, s := range samples {
+		sum += s
+		if i >= window {
+			sum -= samples[i-window]
+		}
+
+		if i >= window-1 {
+			total += math.Pow(sum/float64(window), 4)
+		}
+	}
+
+	return math.Pow(total/float64(len(samples)-window+1), 0.25), time.Duration(len(samples)) * time.Second
+}
+
+// updateNormalizedPower sets the normalized power and variability index of the
+// stats from the points; it returns the duration the normalized power was
+// calculated over
+func (s *WorkoutStats) updateNormalizedPower(points []MapPoint) time.Duration {
+	var d time.Duration
+
+	s.NormalizedPower, d = normalizedPower(points)
+	s.VariabilityIndex = 0
+
+	if s.NormalizedPower > 0 && s.AveragePower > 0 {
+		s.VariabilityIndex = s.NormalizedPower / s.AveragePower
+	}
+
+	return d
+}
+
+// updateTrainingLoad sets the intensity factor and training stress score of
+// the stats, for the FTP and the duration the normalized power was calculated
+// over
+func (s *WorkoutStats) updateTrainingLoad(ftp float64, d time.Duration) {
+	s.IntensityFactor = 0
+	s.TrainingStressScore = 0
+
+	if s.NormalizedPower <= 0 || ftp <= 0 {
+		return
+	}
+
+	s.IntensityFactor = s.NormalizedPower / ftp
+	s.TrainingStressScore = d.Hours() * s.IntensityFactor * s.IntensityFactor * 100
+}
+
+// HasPower returns whether the workout has power data
+func (w *Workout) HasPower() bool {
+	return w.HasExtraMetric("power")
+}
+
+// UpdatePowerAnalytics calculates the normalized power, variability index,
+// intensity factor, training stress score and time in the power zones of the
+// workout, with the FTP of its user valid at the date of the workout
+func (w *Workout) UpdatePowerAnalytics(db *gorm.DB) error {
+	if w.Data == nil {
+		return nil
+	}
+
+	w.Data.PowerZones = nil
+	w.Data.IntensityFactor = 0
+	w.Data.TrainingStressScore = 0
+
+	if w.Data.Details == nil || !w.HasPower() {
+		return nil
+	}
+
+	u, err := w.userWithMeasurements(db)
+	if err != nil {
+		return err
+	}
+
+	points := w.Data.Details.Points
+	zones := u.PowerZonesAt(w.Date)
+
+	d := w.Data.updateNormalizedPower(points)
+
+	// Without an FTP, there is no intensity or time in the zones
+	if zones == nil {
+		return nil
+	}
+
+	w.Data.updateTrainingLoad(zones.FTP, d)
+	w.Data.PowerZones = w.Data.Details.TimeInPowerZones(zones, 0, len(points)-1)
+
+	return nil
+}
@@ -0,0 +1,151 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func powerTestPoints(start time.Time, powers ...float64) []MapPoint {
+	points := make([]MapPoint, len(powers))
+
+	for i, power := range powers {
+		points[i] = MapPoint{Time: start.Add(time.Duration(i) * time.Second), Duration: time.Second}
+		if power > 0 {
+			points[i].ExtraMetrics = ExtraMetrics{"power": power}
+		}
+	}
+
+	return points
+}
+
+func TestPowerZones(t *testing.T) {
+	zones := NewPowerZones(200)
+	assert.InDeltaSlice(t, []float64{110, 150, 180, 210, 240, 300}, zones.Bounds, 0.001)
+	assert.Equal(t, 7, zones.Count())
+
+	assert.Equal(t, 1, zones.Zone(50))
+	assert.Equal(t, 4, zones.Zone(200))
+	assert.Equal(t, 7, zones.Zone(400))
+
+	// Without an FTP, there are no zones
+	assert.Nil(t, NewPowerZones(0))
+}
+
+func TestNormalizedPower(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	steady := make([]float64, 60)
+	for i := range steady {
+		steady[i] = 200
+	}
+
+	np, d := normalizedPower(powerTestPoints(start, steady...))
+	assert.InDelta(t, 200, np, 0.001)
+	assert.Equal(t, time.Minute, d)
+
+	// Intervals weigh the hard efforts more than the average does
+	var intervals []float64
+	for i := range 240 {
+		if (i/60)%2 == 0 {
+			intervals = append(intervals, 100)
+		} else {
+			intervals = append(intervals, 300)
+		}
+	}
+
+	np, _ = normalizedPower(powerTestPoints(start, intervals...))
+	assert.Greater(t, np, 200.0)
+	assert.Less(t, np, 300.0)
+
+	// Less than the rolling window, or no power at all
+	np, _ = normalizedPower(powerTestPoints(start, steady[:20]...))
+	assert.Zero(t, np)
+
+	np, _ = normalizedPower(powerTestPoints(start, make([]float64, 60)...))
+	assert.Zero(t, np)
+
+	// Points of 10 seconds hold their power for 10 seconds
+	points := powerTestPoints(start, 200, 200, 200, 200)
+	for i := range points {
+		points[i].Duration = 10 * time.Second
+	}
+
+	np, d = normalizedPower(points)
+	assert.InDelta(t, 200, np, 0.001)
+	assert.Equal(t, 40*time.Second, d)
+}
+
+func TestWorkout_UpdatePowerAnalytics(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+
+	date := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	m := u.NewMeasurement(date.Add(-24 * time.Hour))
+	m.FTP = 250
+	require.NoError(t, m.Save(db))
+
+	w := duplicateTestWorkout(u, "power", date, false, ExtraMetrics{"power": 250})
+	w.Data.ExtraMetrics = []string{"power"}
+
+	for i := range w.Data.Details.Points {
+		w.Data.Details.Points[i].Duration = 10 * time.Second
+	}
+
+	w.UpdateAverages()
+	require.NoError(t, w.UpdatePowerAnalytics(db))
+
+	assert.InDelta(t, 250, w.Data.NormalizedPower, 0.001)
+	assert.InDelta(t, 1, w.Data.VariabilityIndex, 0.001)
+	assert.InDelta(t, 1, w.Data.IntensityFactor, 0.001)
+	// 10 minutes at FTP
+	assert.InDelta(t, 100.0/6, w.Data.TrainingStressScore, 0.001)
+	assert.Equal(t, []time.Duration{0, 0, 0, 10 * time.Minute, 0, 0, 0}, w.Data.PowerZones)
+
+	require.NoError(t, w.Save(db))
+
+	easy := duplicateTestWorkout(u, "easy", date.Add(time.Hour), false, ExtraMetrics{"power": 100})
+	easy.Data.ExtraMetrics = []string{"power"}
+
+	for i := range easy.Data.Details.Points {
+		easy.Data.Details.Points[i].Duration = 10 * time.Second
+	}
+
+	easy.UpdateAverages()
+	require.NoError(t, easy.UpdatePowerAnalytics(db))
+	require.NoError(t, easy.Save(db))
+
+	var workouts []*Workout
+
+	filters := &WorkoutFilters{MinIntensityFactor: 0.9, OrderBy: "training_stress_score", OrderDir: "desc"}
+	require.NoError(t, filters.ToQuery(db.Model(&Workout{})).Find(&workouts).Error)
+	require.Len(t, workouts, 1)
+	assert.Equal(t, w.ID, workouts[0].ID)
+
+	filters = &WorkoutFilters{OrderBy: "normalized_power", OrderDir: "asc"}
+	require.NoError(t, filters.ToQuery(db.Model(&Workout{})).Find(&workouts).Error)
+	require.Len(t, workouts, 2)
+	assert.Equal(t, easy.ID, workouts[0].ID)
+
+	// Before the first FTP measurement, there is no intensity and no time in
+	// the zones
+	early := duplicateTestWorkout(u, "early", date.AddDate(0, 0, -7), false, ExtraMetrics{"power": 250})
+	early.Data.ExtraMetrics = []string{"power"}
+
+	for i := range early.Data.Details.Points {
+		early.Data.Details.Points[i].Duration = 10 * time.Second
+	}
+
+	early.UpdateAverages()
+	require.NoError(t, early.UpdatePowerAnalytics(db))
+
+	assert.InDelta(t, 250, early.Data.NormalizedPower, 0.001)
+	assert.Zero(t, early.Data.IntensityFactor)
+	assert.Zero(t, early.Data.TrainingStressScore)
+	assert.Nil(t, early.Data.PowerZones)
+}
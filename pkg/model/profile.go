@@ -24,6 +24,10 @@ type Profile struct {
 	APIActive                bool              `form:"api_active" json:"api_active"`                                 // Whether the user's API key is active
 	PreferFullDate           bool              `form:"prefer_full_date" json:"prefer_full_date"`                     // Whether to show full dates in the workout details
 	ShowTabs                 bool              `form:"show_tabs" json:"show_tabs"`                                   // Whether to show tabs in web UI
+	CleanGPSTracks           bool              `form:"clean_gps_tracks" json:"clean_gps_tracks"`                     // Whether GPS errors of the tracks of workouts are corrected
+	SmoothGPSTracks          bool              `form:"smooth_gps_tracks" json:"smooth_gps_tracks"`                   // Whether the corrected tracks are smoothed as well
+
+	HeartRateZoneModel HeartRateZoneModel `form:"heart_rate_zone_model" json:"heart_rate_zone_model"` // How the heart rate zones are derived from the measurements
 }
 
 type UserPreferredUnits struct {
@@ -32,6 +36,7 @@ type UserPreferredUnits struct {
 	ElevationRaw string `form:"elevation" json:"elevation"` // The user's preferred elevation unit
 	WeightRaw    string `form:"weight" json:"weight"`       // The user's preferred weight unit
 	HeightRaw    string `form:"height" json:"height"`       // The user's preferred height unit
+	SwimPaceRaw  string `form:"swim_pace" json:"swim_pace"` // The user's preferred swim pace unit (per 100m or 100yd)
 }
 
 func (u UserPreferredUnits) Tempo() string {
@@ -86,6 +91,32 @@ func (u UserPreferredUnits) Distance() string {
 	}
 }
 
+// SwimPace returns the unit of the swim pace, i.e. the time per 100 meters or
+// 100 yards; by default it follows the preferred distance unit
+func (u UserPreferredUnits) SwimPace() string {
+	switch u.SwimPaceRaw {
+	case "100yd":
+		return "min/100yd"
+	case "100m":
+		return "min/100m"
+	}
+
+	if u.Distance() == "mi" {
+		return "min/100yd"
+	}
+
+	return "min/100m"
+}
+
+// SwimPaceDistance returns the distance of the swim pace unit, in meters
+func (u UserPreferredUnits) SwimPaceDistance() float64 {
+	if u.SwimPace() == "min/100yd" {
+		return 100 * MeterPerYard
+	}
+
+	return 100
+}
+
 func (u UserPreferredUnits) Speed() string {
 	switch u.SpeedRaw {
 	case "mph":
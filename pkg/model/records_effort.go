@@ -0,0 +1,370 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// EffortMetric is the metric of a best effort by duration
+type EffortMetric string
+
+const (
+	EffortMetricPower     EffortMetric = "power"      // The average power, in watts
+	EffortMetricSpeed     EffortMetric = "speed"      // The average speed, in m/s
+	EffortMetricHeartRate EffortMetric = "heart-rate" // The average heart rate, in bpm
+)
+
+var (
+	ErrInvalidEffortMetric = errors.New("invalid effort metric")
+	ErrInvalidEffortLabel  = errors.New("invalid effort duration label")
+)
+
+// Validate returns an error when the metric is unknown
+func (m EffortMetric) Validate() error {
+	switch m {
+	case EffortMetricPower, EffortMetricSpeed, EffortMetricHeartRate:
+		return nil
+	default:
+		return ErrInvalidEffortMetric
+	}
+}
+
+// EffortRecordTarget defines a target duration label and its length.
+type EffortRecordTarget struct {
+	Label          string
+	TargetDuration time.Duration
+}
+
+var effortRecordTargets = []EffortRecordTarget{
+	{Label: "1 s", TargetDuration: time.Second},
+	{Label: "5 s", TargetDuration: 5 * time.Second},
+	{Label: "10 s", TargetDuration: 10 * time.Second},
+	{Label: "30 s", TargetDuration: 30 * time.Second},
+	{Label: "1 min", TargetDuration: time.Minute},
+	{Label: "2 min", TargetDuration: 2 * time.Minute},
+	{Label: "5 min", TargetDuration: 5 * time.Minute},
+	{Label: "10 min", TargetDuration: 10 * time.Minute},
+	{Label: "20 min", TargetDuration: 20 * time.Minute},
+	{Label: "30 min", TargetDuration: 30 * time.Minute},
+	{Label: "1 h", TargetDuration: time.Hour},
+	{Label: "2 h", TargetDuration: 2 * time.Hour},
+	{Label: "3 h", TargetDuration: 3 * time.Hour},
+	{Label: "5 h", TargetDuration: 5 * time.Hour},
+}
+
+func effortRecordTarget(label string) (EffortRecordTarget, bool) {
+	for _, target := range effortRecordTargets {
+		if target.Label == label {
+			return target, true
+		}
+	}
+
+	return EffortRecordTarget{}, false
+}
+
+// WorkoutEffortRecord stores best efforts by duration per workout for ranking purposes.
+type WorkoutEffortRecord struct {
+	Model
+	WorkoutID       uint64       `gorm:"index:idx_workout_effort"`
+	Metric          EffortMetric `gorm:"size:32;index:idx_workout_effort"`
+	Label           string       `gorm:"size:64"`
+	DurationSeconds float64
+	Value           float64
+	Distance        float64
+	StartIndex      int
+	EndIndex        int
+}
+
+// WorkoutEffortRecordWithRank represents a stored effort together with its computed rank.
+type WorkoutEffortRecordWithRank struct {
+	WorkoutEffortRecord
+	Rank int64 `gorm:"column:rank"`
+}
+
+// EffortRecord is the best average of a metric over a duration
+type EffortRecord struct {
+	Metric     EffortMetric  `json:"metric"`     // The metric of the effort
+	Label      string        `json:"label"`      // Human label (e.g. "5 min")
+	Duration   time.Duration `json:"duration"`   // The duration of the effort
+	Value      float64       `json:"value"`      // The average of the metric over the effort
+	Distance   float64       `json:"distance"`   // The distance covered in meters
+	WorkoutID  uint64        `json:"workoutID"`  // Workout ID where the record was set
+	Date       time.Time     `json:"date"`       // Workout date
+	StartIndex int           `json:"startIndex"` // Start point index in the workout map
+	EndIndex   int           `json:"endIndex"`   // End point index in the workout map
+}
+
+// effortMetrics returns the metrics the workout has best efforts for
+func (w *Workout) effortMetrics() []EffortMetric {
+	metrics := []EffortMetric{}
+
+	if w.HasPower() {
+		metrics = append(metrics, EffortMetricPower)
+	}
+
+	if w.Type.IsDistance() {
+		metrics = append(metrics, EffortMetricSpeed)
+	}
+
+	if w.HasHeartRate() {
+		metrics = append(metrics, EffortMetricHeartRate)
+	}
+
+	return metrics
+}
+
+type effortSample struct {
+	value    float64
+	distance float64
+	index    int
+}
+
+// effortSamples resamples the metric of the points per second, holding the
+// value of a point over its duration. Pauses of the recording are skipped;
+// points without power count as 0, other points without the metric are
+// skipped.
+func effortSamples(points []MapPoint, metric EffortMetric) []effortSample {
+	var (
+		samples []effortSample
+		carry   time.Duration
+	)
+
+	for i, p := range points {
+		if p.Duration <= 0 || p.Duration > ZoneMaxGap {
+			continue
+		}
+
+		v, ok := p.ExtraMetrics[string(metric)]
+		if !ok || math.IsNaN(v) || v <= 0 {
+			switch metric {
+			case EffortMetricPower:
+				v = 0
+			case EffortMetricSpeed:
+				v = p.AverageSpeed()
+			default:
+				continue
+			}
+		}
+
+		perSecond := p.Distance / p.Duration.Seconds()
+
+		carry += p.Duration
+		for ; carry >= time.Second; carry -= time.Second {
+			samples = append(samples, effortSample{value: v, distance: perSecond, index: i})
+		}
+	}
+
+	return samples
+}
+
+// bestEffortsForWorkout returns the best average of the metric over each of
+// the target durations that fits in the workout
+func bestEffortsForWorkout(w *Workout, metric EffortMetric, targets []EffortRecordTarget) []EffortRecord {
+	if w == nil || w.Data == nil || w.Data.Details == nil || len(w.Data.Details.Points) < 2 {
+		return nil
+	}
+
+	samples := effortSamples(w.Data.Details.Points, metric)
+	results := []EffortRecord{}
+
+	for _, target := range targets {
+		n := int(target.TargetDuration / time.Second)
+		if n <= 0 || n > len(samples) {
+			continue
+		}
+
+		var sum, distance, bestSum, bestDistance float64
+
+		bestEnd := -1
+
+		for i, s := range samples {
+			sum += s.value
+			distance += s.distance
+
+			if i >= n {
+				sum -= samples[i-n].value
+				distance -= samples[i-n].distance
+			}
+
+			if i >= n-1 && (bestEnd < 0 || sum > bestSum) {
+				bestSum, bestDistance, bestEnd = sum, distance, i
+			}
+		}
+
+		if bestSum <= 0 {
+			continue
+		}
+
+		results = append(results, EffortRecord{
+			Metric:     metric,
+			Label:      target.Label,
+			Duration:   target.TargetDuration,
+			Value:      bestSum / float64(n),
+			Distance:   bestDistance,
+			WorkoutID:  w.ID,
+			Date:       w.Date,
+			StartIndex: samples[bestEnd-n+1].index,
+			EndIndex:   samples[bestEnd].index,
+		})
+	}
+
+	return results
+}
+
+// updateEffortRecords recalculates and persists the best efforts by duration
+// for this workout.
+func (w *Workout) updateEffortRecords(tx *gorm.DB) error {
+	if err := tx.Where("workout_id = ?", w.ID).Delete(&WorkoutEffortRecord{}).Error; err != nil {
+		return err
+	}
+
+	if w.Data == nil || w.Data.Details == nil || len(w.Data.Details.Points) < 2 {
+		return nil
+	}
+
+	rows := []*WorkoutEffortRecord{}
+
+	for _, metric := range w.effortMetrics() {
+		for _, r := range bestEffortsForWorkout(w, metric, effortRecordTargets) {
+			rows = append(rows, &WorkoutEffortRecord{
+				WorkoutID:       w.ID,
+				Metric:          r.Metric,
+				Label:           r.Label,
+				DurationSeconds: r.Duration.Seconds(),
+				Value:           r.Value,
+				Distance:        r.Distance,
+				StartIndex:      r.StartIndex,
+				EndIndex:        r.EndIndex,
+			})
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil
+	}
+
+	return tx.Create(&rows).Error
+}
+
+// GetWorkoutEffortRecordsWithRank returns all stored effort records for the given workout with
+// their rank computed on the fly for the owning user and workout type.
+func GetWorkoutEffortRecordsWithRank(db *gorm.DB, userID uint64, workoutType WorkoutType, workoutID uint64) ([]WorkoutEffortRecordWithRank, error) {
+	base := db.
+		Table("workout_effort_records as wer").
+		Select(`wer.*, RANK() OVER (
+			PARTITION BY wer.metric, wer.label
+			ORDER BY wer.value DESC, workouts.date ASC, wer.workout_id ASC
+		) AS rank`).
+		Joins("join workouts on workouts.id = wer.workout_id").
+		Where("workouts.user_id = ?", userID).
+		Where("workouts.type = ?", workoutType)
+
+	rows := []WorkoutEffortRecordWithRank{}
+	if err := db.Table("(?) as ranked", base).
+		Where("workout_id = ?", workoutID).
+		Order("metric asc, duration_seconds asc").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	return rows, nil
+}
+
+type effortRecordRow struct {
+	WorkoutEffortRecord
+	Date time.Time
+}
+
+func (r effortRecordRow) toEffortRecord() EffortRecord {
+	return EffortRecord{
+		Metric:     r.Metric,
+		Label:      r.Label,
+		Duration:   time.Duration(r.DurationSeconds * float64(time.Second)),
+		Value:      r.Value,
+		Distance:   r.Distance,
+		WorkoutID:  r.WorkoutID,
+		Date:       r.Date,
+		StartIndex: r.StartIndex,
+		EndIndex:   r.EndIndex,
+	}
+}
+
+// EffortRecordsQuery returns the query of the stored effort records joined with
+// their workouts, to be scoped to the workouts of a user
+func EffortRecordsQuery(db *gorm.DB) *gorm.DB {
+	return db.Table("workout_effort_records").
+		Select("workout_effort_records.*, workouts.date as date").
+		Joins("join workouts on workouts.id = workout_effort_records.workout_id")
+}
+
+// GetEffortCurve returns the best effort of the metric for each duration, from
+// the effort records of the query, together with the workout that set it
+func GetEffortCurve(q *gorm.DB, metric EffortMetric) ([]EffortRecord, error) {
+	if err := metric.Validate(); err != nil {
+		return nil, err
+	}
+
+	rows := []effortRecordRow{}
+	if err := q.Where("workout_effort_records.metric = ?", metric).
+		Order("workout_effort_records.duration_seconds asc, workout_effort_records.value desc, workouts.date asc, workout_effort_records.workout_id asc").
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	result := []EffortRecord{}
+
+	for _, r := range rows {
+		if n := len(result); n > 0 && result[n-1].Label == r.Label {
+			continue
+		}
+
+		result = append(result, r.toEffortRecord())
+	}
+
+	return result, nil
+}
+
+// GetEffortRecordRanking returns the stored efforts of the metric for a
+// duration label from the query, ordered best-first with pagination.
+func GetEffortRecordRanking(q *gorm.DB, metric EffortMetric, label string, limit, offset int) ([]EffortRecord, int64, error) {
+	if err := metric.Validate(); err != nil {
+		return nil, 0, err
+	}
+
+	if _, ok := effortRecordTarget(label); !ok {
+		return nil, 0, fmt.Errorf("%w: %s", ErrInvalidEffortLabel, label)
+	}
+
+	base := q.Where("workout_effort_records.metric = ?", metric).
+		Where("workout_effort_records.label = ?", label)
+
+	var totalCount int64
+	if err := base.Count(&totalCount).Error; err != nil {
+		return nil, 0, err
+	}
+
+	if limit > 0 {
+		base = base.Limit(limit)
+	}
+
+	if offset > 0 {
+		base = base.Offset(offset)
+	}
+
+	rows := []effortRecordRow{}
+	if err := base.Order("workout_effort_records.value desc, workouts.date asc, workout_effort_records.workout_id asc").
+		Find(&rows).Error; err != nil {
+		return nil, 0, err
+	}
+
+	result := make([]EffortRecord, 0, len(rows))
+	for _, r := range rows {
+		result = append(result, r.toEffortRecord())
+	}
+
+	return result, totalCount, nil
+}
@@ -0,0 +1,111 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBestEffortsForWorkout(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	var powers []float64
+	for i := range 150 {
+		if i >= 60 && i < 90 {
+			powers = append(powers, 400)
+		} else {
+			powers = append(powers, 100)
+		}
+	}
+
+	w := &Workout{
+		Type: WorkoutTypeCycling,
+		Date: start,
+		Data: &MapData{Details: &MapDataDetails{Points: powerTestPoints(start, powers...)}},
+	}
+
+	efforts := bestEffortsForWorkout(w, EffortMetricPower, effortRecordTargets)
+	require.Len(t, efforts, 6)
+
+	assert.Equal(t, "1 s", efforts[0].Label)
+	assert.InDelta(t, 400, efforts[0].Value, 0.001)
+	assert.Equal(t, 60, efforts[0].StartIndex)
+
+	assert.Equal(t, "30 s", efforts[3].Label)
+	assert.InDelta(t, 400, efforts[3].Value, 0.001)
+	assert.Equal(t, 60, efforts[3].StartIndex)
+	assert.Equal(t, 89, efforts[3].EndIndex)
+
+	assert.Equal(t, "2 min", efforts[5].Label)
+	assert.InDelta(t, (30*400+90*100)/120.0, efforts[5].Value, 0.001)
+
+	// The workout has no heart rate
+	assert.Empty(t, bestEffortsForWorkout(w, EffortMetricHeartRate, effortRecordTargets))
+}
+
+func TestWorkout_UpdateEffortRecords(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+
+	date := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	create := func(name string, start time.Time, power float64) *Workout {
+		w := duplicateTestWorkout(u, name, start, false, ExtraMetrics{"power": power})
+		w.Data.ExtraMetrics = []string{"power"}
+
+		for i := range w.Data.Details.Points {
+			w.Data.Details.Points[i].Duration = 10 * time.Second
+		}
+
+		require.NoError(t, w.Save(db))
+		require.NoError(t, w.UpdateRecords(db))
+
+		return w
+	}
+
+	easy := create("easy", date, 250)
+	hard := create("hard", date.AddDate(0, 0, 7), 300)
+
+	ranked, err := GetWorkoutEffortRecordsWithRank(db, u.ID, WorkoutTypeCycling, easy.ID)
+	require.NoError(t, err)
+	require.Len(t, ranked, 8)
+	assert.Equal(t, EffortMetricPower, ranked[0].Metric)
+	assert.Equal(t, int64(2), ranked[0].Rank)
+
+	curve, err := GetEffortCurve(EffortRecordsQuery(db).Where("workouts.user_id = ?", u.ID), EffortMetricPower)
+	require.NoError(t, err)
+	require.Len(t, curve, 8)
+	assert.Equal(t, "10 min", curve[7].Label)
+	assert.Equal(t, hard.ID, curve[7].WorkoutID)
+	assert.InDelta(t, 300, curve[7].Value, 0.001)
+
+	// The envelope of a date range only has the workouts in the range
+	curve, err = GetEffortCurve(EffortRecordsQuery(db).Where("workouts.date < ?", hard.Date), EffortMetricPower)
+	require.NoError(t, err)
+	require.Len(t, curve, 8)
+	assert.Equal(t, easy.ID, curve[0].WorkoutID)
+
+	_, err = GetEffortCurve(EffortRecordsQuery(db), "cadence")
+	require.ErrorIs(t, err, ErrInvalidEffortMetric)
+
+	ranking, total, err := GetEffortRecordRanking(EffortRecordsQuery(db), EffortMetricPower, "5 min", 1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), total)
+	require.Len(t, ranking, 1)
+	assert.Equal(t, hard.ID, ranking[0].WorkoutID)
+	assert.Equal(t, 5*time.Minute, ranking[0].Duration)
+
+	_, _, err = GetEffortRecordRanking(EffortRecordsQuery(db), EffortMetricPower, "7 min", 10, 0)
+	require.ErrorIs(t, err, ErrInvalidEffortLabel)
+
+	// Deleting a workout deletes its efforts
+	require.NoError(t, hard.Delete(db))
+
+	var count int64
+	require.NoError(t, db.Model(&WorkoutEffortRecord{}).Where("workout_id = ?", hard.ID).Count(&count).Error)
+	assert.Zero(t, count)
+}
@@ -0,0 +1,209 @@
+package model
+
+import (
+	"math"
+	"sort"
+	"time"
+)
+
+// MaxGradeAdjustmentSlope is the steepest slope (up or down) the energy cost of
+// running is known for; steeper slopes are adjusted as this slope
+const MaxGradeAdjustmentSlope = 0.45
+
+// runningCost returns the energy cost of running on the slope, in J/kg/m
+// (Minetti et al., 2002)
+func runningCost(slope float64) float64 {
+	i := min(max(slope, -MaxGradeAdjustmentSlope), MaxGradeAdjustmentSlope)
+
+	return 155.4*math.Pow(i, 5) - 30.4*math.Pow(i, 4) - 43.3*math.Pow(i, 3) + 46.3*i*i + 19.5*i + 3.6
+}
+
+// GradeAdjustmentFactor returns how much harder running on the slope is than
+// running on the flat: the speed on the slope times the factor is the speed
+// on the flat for the same effort
+func GradeAdjustmentFactor(slope float64) float64 {
+	if math.IsNaN(slope) {
+		return 1
+	}
+
+	return runningCost(slope) / runningCost(0)
+}
+
+// GradeAdjustedSpeed returns the speed of the point adjusted for its slope
+// grade, ie. the speed on the flat for the same effort
+func (m *MapPoint) GradeAdjustedSpeed() float64 {
+	return m.AverageSpeed() * GradeAdjustmentFactor(m.SlopeGrade)
+}
+
+// isMoving returns whether the point counts as moving: faster than 1 km/h, or
+// producing power (e.g. on a trainer)
+func (m *MapPoint) isMoving() bool {
+	if m.AverageSpeed()*3.6 >= 1.0 {
+		return true
+	}
+
+	power, ok := m.ExtraMetrics["power"]
+
+	return ok && power > 0
+}
+
+// updateGradeAdjustedSpeed sets the average grade-adjusted speed of the stats
+// while moving, from the points
+func (s *WorkoutStats) updateGradeAdjustedSpeed(points []MapPoint) {
+	var distance, seconds float64
+
+	for i := range points {
+		p := &points[i]
+		if p.Duration <= 0 || p.AverageSpeed()*3.6 < 1.0 {
+			continue
+		}
+
+		distance += p.Distance * GradeAdjustmentFactor(p.SlopeGrade)
+		seconds += p.Duration.Seconds()
+	}
+
+	s.GradeAdjustedSpeed = 0
+
+	if seconds > 0 {
+		s.GradeAdjustedSpeed = distance / seconds
+	}
+}
+
+type decouplingHalf struct {
+	distance float64 // The grade-adjusted distance
+	work     float64 // The power times the duration
+	beats    float64 // The heart rate times the duration
+	power    bool
+}
+
+// updateDecoupling sets the aerobic decoupling of the stats from the points:
+// how much the power and, with pace, the grade-adjusted speed per heart beat
+// drop from the first half of the moving time to the second half, in percent.
+// Only the points with a heart rate count; pauses of the recording are
+// skipped.
+func (s *WorkoutStats) updateDecoupling(points []MapPoint, pace bool) {
+	s.PaceDecoupling = 0
+	s.PowerDecoupling = 0
+
+	valid := func(p *MapPoint) (float64, bool) {
+		hr, ok := p.ExtraMetrics["heart-rate"]
+		if !ok || math.IsNaN(hr) || hr <= 0 || p.Duration <= 0 || p.Duration > ZoneMaxGap || !p.isMoving() {
+			return 0, false
+		}
+
+		return hr, true
+	}
+
+	var total time.Duration
+
+	for i := range points {
+		if _, ok := valid(&points[i]); ok {
+			total += points[i].Duration
+		}
+	}
+
+	if total == 0 {
+		return
+	}
+
+	var (
+		halves  [2]decouplingHalf
+		elapsed time.Duration
+	)
+
+	for i := range points {
+		p := &points[i]
+
+		hr, ok := valid(p)
+		if !ok {
+			continue
+		}
+
+		h := &halves[0]
+		if elapsed >= total/2 {
+			h = &halves[1]
+		}
+
+		elapsed += p.Duration
+		seconds := p.Duration.Seconds()
+
+		h.distance += p.Distance * GradeAdjustmentFactor(p.SlopeGrade)
+		h.beats += hr * seconds
+
+		if power, ok := p.ExtraMetrics["power"]; ok && !math.IsNaN(power) && power > 0 {
+			h.work += power * seconds
+			h.power = true
+		}
+	}
+
+	first, second := halves[0], halves[1]
+	if first.beats <= 0 || second.beats <= 0 {
+		return
+	}
+
+	if pace && first.distance > 0 {
+		s.PaceDecoupling = (1 - (second.distance/second.beats)/(first.distance/first.beats)) * 100
+	}
+
+	if first.power && second.power {
+		s.PowerDecoupling = (1 - (second.work/second.beats)/(first.work/first.beats)) * 100
+	}
+}
+
+// lapRange returns the indexes of the first and last point of the lap
+func (m *MapData) lapRange(l *WorkoutLap) (int, int) {
+	points := m.Details.Points
+
+	start := sort.Search(len(points), func(j int) bool { return !points[j].Time.Before(l.Start) })
+	end := sort.Search(len(points), func(j int) bool { return points[j].Time.After(l.Stop) }) - 1
+
+	return start, end
+}
+
+// updateLapRunningAnalytics recalculates the grade-adjusted speed and the
+// decoupling of the laps from the points of the laps; the grade-adjusted
+// speed and pace decoupling are only calculated for workouts on foot
+func (m *MapData) updateLapRunningAnalytics(onFoot bool) {
+	if m.Details == nil {
+		return
+	}
+
+	for i := range m.Laps {
+		l := &m.Laps[i]
+
+		start, end := m.lapRange(l)
+		if start < 0 || end >= len(m.Details.Points) || start > end {
+			continue
+		}
+
+		points := m.Details.Points[start : end+1]
+
+		l.GradeAdjustedSpeed = 0
+		if onFoot {
+			l.updateGradeAdjustedSpeed(points)
+		}
+
+		l.updateDecoupling(points, onFoot)
+	}
+}
+
+// StatsForRange aggregates the statistics of the points between both indexes,
+// inclusive; the grade-adjusted speed and pace decoupling are only calculated
+// for workouts on foot, since the slope model only applies to them
+func (w *Workout) StatsForRange(startIdx, endIdx int) (MapDataRangeStats, bool) {
+	if w.Data == nil || w.Data.Details == nil {
+		return MapDataRangeStats{}, false
+	}
+
+	stats, ok := w.Data.Details.StatsForRange(startIdx, endIdx)
+	if !ok || !w.Type.IsOnFoot() {
+		return stats, ok
+	}
+
+	points := w.Data.Details.Points[startIdx : endIdx+1]
+
+	stats.updateGradeAdjustedSpeed(points)
+	stats.updateDecoupling(points, true)
+
+	return stats, true
+}
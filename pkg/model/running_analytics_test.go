@@ -0,0 +1,127 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func runningTestPoints(start time.Time, count int, slope float64, hr func(i int) float64) []MapPoint {
+	points := make([]MapPoint, count)
+
+	for i := range points {
+		points[i] = MapPoint{
+			Time:          start.Add(time.Duration(i) * 10 * time.Second),
+			Duration:      10 * time.Second,
+			Distance:      30,
+			TotalDistance: float64(i+1) * 30,
+			SlopeGrade:    slope,
+			ExtraMetrics:  ExtraMetrics{"heart-rate": hr(i)},
+		}
+	}
+
+	return points
+}
+
+func TestGradeAdjustmentFactor(t *testing.T) {
+	assert.InDelta(t, 1, GradeAdjustmentFactor(0), 0.001)
+	assert.Greater(t, GradeAdjustmentFactor(0.1), 1.5)
+	assert.Less(t, GradeAdjustmentFactor(-0.05), 1.0)
+
+	// Slopes steeper than the model are adjusted as the steepest slope
+	assert.InDelta(t, GradeAdjustmentFactor(MaxGradeAdjustmentSlope), GradeAdjustmentFactor(0.8), 0.001)
+
+	p := MapPoint{Duration: 10 * time.Second, Distance: 30, SlopeGrade: 0.1}
+	assert.InDelta(t, 3*GradeAdjustmentFactor(0.1), p.GradeAdjustedSpeed(), 0.001)
+}
+
+func runningTestWorkout(wt WorkoutType, points []MapPoint) *Workout {
+	return &Workout{Type: wt, Data: &MapData{Details: &MapDataDetails{Points: points}}}
+}
+
+func TestWorkout_StatsForRange_RunningAnalytics(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	steady := func(int) float64 { return 150 }
+
+	flat := runningTestWorkout(WorkoutTypeRunning, runningTestPoints(start, 20, 0, steady))
+	stats, ok := flat.StatsForRange(0, 19)
+	require.True(t, ok)
+	assert.InDelta(t, 3, stats.GradeAdjustedSpeed, 0.001)
+	assert.InDelta(t, 0, stats.PaceDecoupling, 0.001)
+	assert.Zero(t, stats.PowerDecoupling)
+
+	// The same speed uphill is a faster grade-adjusted pace
+	hilly := runningTestWorkout(WorkoutTypeHiking, runningTestPoints(start, 20, 0.05, steady))
+	stats, ok = hilly.StatsForRange(0, 19)
+	require.True(t, ok)
+	assert.InDelta(t, 3*GradeAdjustmentFactor(0.05), stats.GradeAdjustedSpeed, 0.001)
+
+	// The heart rate drifts up in the second half at the same speed and power
+	driftPoints := func() []MapPoint {
+		points := runningTestPoints(start, 20, 0, func(i int) float64 {
+			if i < 10 {
+				return 140
+			}
+
+			return 154
+		})
+
+		for i := range points {
+			points[i].ExtraMetrics["power"] = 250
+		}
+
+		return points
+	}
+
+	stats, ok = runningTestWorkout(WorkoutTypeRunning, driftPoints()).StatsForRange(0, 19)
+	require.True(t, ok)
+	assert.InDelta(t, (1-140.0/154)*100, stats.PaceDecoupling, 0.001)
+	assert.InDelta(t, (1-140.0/154)*100, stats.PowerDecoupling, 0.001)
+
+	// Only the power decoupling applies to workouts that are not on foot
+	stats, ok = runningTestWorkout(WorkoutTypeCycling, driftPoints()).StatsForRange(0, 19)
+	require.True(t, ok)
+	assert.Zero(t, stats.GradeAdjustedSpeed)
+	assert.Zero(t, stats.PaceDecoupling)
+	assert.InDelta(t, (1-140.0/154)*100, stats.PowerDecoupling, 0.001)
+}
+
+func TestWorkout_StatisticsPer_RunningAnalytics(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	w := &Workout{
+		Type: WorkoutTypeRunning,
+		Data: &MapData{
+			Details: &MapDataDetails{Points: runningTestPoints(start, 100, 0.05, func(int) float64 { return 150 })},
+			WorkoutData: WorkoutData{Laps: []WorkoutLap{
+				{Start: start, Stop: start.Add(490 * time.Second)},
+			}},
+		},
+	}
+
+	w.UpdateAverages()
+	assert.InDelta(t, 3*GradeAdjustmentFactor(0.05), w.Data.GradeAdjustedSpeed, 0.001)
+	assert.InDelta(t, w.Data.GradeAdjustedSpeed, w.Data.Laps[0].GradeAdjustedSpeed, 0.001)
+
+	breakdown, err := w.StatisticsPer(1, "km")
+	require.NoError(t, err)
+	require.Len(t, breakdown.Items, 4)
+
+	for _, item := range breakdown.Items {
+		assert.InDelta(t, w.Data.GradeAdjustedSpeed, item.GradeAdjustedSpeed, 0.001)
+	}
+
+	w.Type = WorkoutTypeCycling
+	w.UpdateAverages()
+	assert.Zero(t, w.Data.GradeAdjustedSpeed)
+	assert.Zero(t, w.Data.Laps[0].GradeAdjustedSpeed)
+
+	breakdown, err = w.StatisticsPer(1, "km")
+	require.NoError(t, err)
+
+	for _, item := range breakdown.Items {
+		assert.Zero(t, item.GradeAdjustedSpeed)
+	}
+}
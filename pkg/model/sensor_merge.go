@@ -0,0 +1,93 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// SensorMergeMetrics are the sensor streams that can be merged into a
+// workout from another recording, e.g. of a heart rate strap or bike computer
+var SensorMergeMetrics = []string{"heart-rate", "power", "cadence", "temperature"}
+
+var (
+	ErrInvalidSensorMetric = errors.New("metric can not be merged")
+	ErrNoSensorValues      = errors.New("the recording has no values for the metrics during the workout")
+)
+
+// MergeSensorStreams merges the metrics of another recording of the workout,
+// e.g. parsed from the file of a heart rate strap, into the workout. The
+// samples are aligned by their time, after shifting them by the offset, and
+// replace the values of the workout. The sensor statistics of the workout and
+// its laps are recalculated, and the workout is marked dirty so its records
+// are updated. It returns the merged metrics.
+func (w *Workout) MergeSensorStreams(db *gorm.DB, source string, recordings []*Workout, metrics []string, offset time.Duration) ([]string, error) {
+	if len(metrics) == 0 {
+		metrics = SensorMergeMetrics
+	}
+
+	for _, m := range metrics {
+		if !slices.Contains(SensorMergeMetrics, m) {
+			return nil, ErrInvalidSensorMetric
+		}
+	}
+
+	if w.Data == nil || w.Data.Details == nil || len(w.Data.Details.Points) == 0 {
+		return nil, ErrWorkoutHasNoPoints
+	}
+
+	var points []MapPoint
+
+	for _, r := range recordings {
+		if r.Data == nil || r.Data.Details == nil {
+			continue
+		}
+
+		for _, p := range r.Data.Details.Points {
+			p.Time = p.Time.Add(offset)
+			points = append(points, p)
+		}
+	}
+
+	merged := []string{}
+
+	for _, m := range metrics {
+		s := NewSensorStream(m, source, points)
+		s.Replace = true
+
+		if !s.covers(w.Data.Details.Points) {
+			continue
+		}
+
+		w.Data.Details.AddStream(s)
+		merged = append(merged, m)
+	}
+
+	if len(merged) == 0 {
+		return nil, ErrNoSensorValues
+	}
+
+	w.Data.updateLapStats()
+	w.UpdateAverages()
+	w.UpdateExtraMetrics()
+	w.Dirty = true
+
+	if err := w.Save(db); err != nil {
+		return nil, err
+	}
+
+	return merged, nil
+}
+
+// covers returns whether the stream has a value for any of the points
+func (s *SensorStream) covers(points []MapPoint) bool {
+	for i := range points {
+		if _, ok := s.ValueAt(points[i].Time); ok {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,83 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sensorRecording returns a recording of the points with constant values for
+// the metrics, with a clock that is ahead by the offset
+func sensorRecording(points []MapPoint, offset time.Duration, metrics ExtraMetrics) *Workout {
+	recorded := make([]MapPoint, 0, len(points))
+
+	for _, p := range points {
+		em := ExtraMetrics{}
+		for k, v := range metrics {
+			em.Set(k, v)
+		}
+
+		recorded = append(recorded, MapPoint{Time: p.Time.Add(offset), ExtraMetrics: em})
+	}
+
+	return &Workout{Data: &MapData{Details: &MapDataDetails{Points: recorded}}}
+}
+
+func TestWorkout_MergeSensorStreams(t *testing.T) {
+	db, w := createEditTestWorkout(t)
+
+	require.Greater(t, w.Data.AverageHeartRate, 0.0)
+	require.Zero(t, w.Data.AveragePower)
+
+	points := w.Data.Details.Points
+	recording := sensorRecording(points, 30*time.Second, ExtraMetrics{"heart-rate": 100, "power": 200, "cadence": 90})
+
+	_, err := w.MergeSensorStreams(db, "strap.fit", []*Workout{recording}, []string{"speed"}, 0)
+	require.ErrorIs(t, err, ErrInvalidSensorMetric)
+
+	_, err = w.MergeSensorStreams(db, "strap.fit", []*Workout{recording}, nil, time.Hour)
+	require.ErrorIs(t, err, ErrNoSensorValues)
+
+	merged, err := w.MergeSensorStreams(db, "strap.fit", []*Workout{recording}, []string{"heart-rate", "power"}, -30*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"heart-rate", "power"}, merged)
+	assert.True(t, w.Dirty)
+
+	assert.InDelta(t, 100, w.Data.AverageHeartRate, 0.001)
+	assert.InDelta(t, 200, w.Data.AveragePower, 0.001)
+	assert.Zero(t, w.Data.AverageCadence)
+
+	// The streams are applied again when the file is parsed again, and the
+	// laps are recalculated
+	require.NoError(t, w.UpdateData(db))
+
+	assert.InDelta(t, 100, w.Data.AverageHeartRate, 0.001)
+	assert.InDelta(t, 200, w.Data.AveragePower, 0.001)
+	assert.Contains(t, w.Data.ExtraMetrics, "power")
+
+	for _, l := range w.Data.Laps {
+		assert.InDelta(t, 200, l.AveragePower, 0.001)
+	}
+}
+
+func TestMapData_UpdateLapStats(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	w := duplicateTestWorkout(defaultUser(), "laps", start, true, nil)
+
+	for i := range w.Data.Details.Points {
+		w.Data.Details.Points[i].ExtraMetrics = ExtraMetrics{"power": float64(100 * (1 + i/30))}
+	}
+
+	w.Data.Laps = []WorkoutLap{
+		{Start: start, Stop: start.Add(295 * time.Second)},
+		{Start: start.Add(300 * time.Second), Stop: start.Add(time.Hour)},
+	}
+
+	w.Data.updateLapStats()
+
+	assert.InDelta(t, 100, w.Data.Laps[0].AveragePower, 0.001)
+	assert.InDelta(t, 200, w.Data.Laps[1].AveragePower, 0.001)
+	assert.InDelta(t, 200, w.Data.Laps[1].MaxPower, 0.001)
+}
@@ -0,0 +1,161 @@
+package model
+
+import (
+	"sort"
+	"time"
+)
+
+// SensorStreamMaxGap is the largest time difference between a point and the
+// nearest sample of a sensor stream for the sample to be used
+const SensorStreamMaxGap = 5 * time.Second
+
+// SensorStream is a series of samples of a single metric, borrowed from
+// another recording of the workout; it is stored with the details, so it
+// can be applied again when the workout's file is parsed again
+type SensorStream struct {
+	Metric  string         `json:"metric"`            // The extra metric, e.g. heart-rate
+	Source  string         `json:"source"`            // Where the samples come from, e.g. the name of the merged workout
+	Samples []SensorSample `json:"samples"`           // The samples, ordered by time
+	Replace bool           `json:"replace,omitempty"` // Whether the samples replace the values of the points, instead of only filling in missing values
+}
+
+// SensorSample is a single value of a sensor stream
+type SensorSample struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// NewSensorStream collects the values of the metric of the points
+func NewSensorStream(metric, source string, points []MapPoint) SensorStream {
+	s := SensorStream{Metric: metric, Source: source}
+
+	for i := range points {
+		v, ok := points[i].ExtraMetrics[metric]
+		if !ok || points[i].Time.IsZero() {
+			continue
+		}
+
+		s.Samples = append(s.Samples, SensorSample{Time: points[i].Time, Value: v})
+	}
+
+	sort.SliceStable(s.Samples, func(i, j int) bool {
+		return s.Samples[i].Time.Before(s.Samples[j].Time)
+	})
+
+	return s
+}
+
+// ValueAt returns the value of the sample nearest to the time, if it is
+// within SensorStreamMaxGap
+func (s *SensorStream) ValueAt(t time.Time) (float64, bool) {
+	i := sort.Search(len(s.Samples), func(i int) bool {
+		return !s.Samples[i].Time.Before(t)
+	})
+
+	best := -1
+	bestGap := SensorStreamMaxGap + 1
+
+	for _, j := range []int{i - 1, i} {
+		if j < 0 || j >= len(s.Samples) {
+			continue
+		}
+
+		gap := s.Samples[j].Time.Sub(t).Abs()
+		if gap < bestGap {
+			best, bestGap = j, gap
+		}
+	}
+
+	if best < 0 || bestGap > SensorStreamMaxGap {
+		return 0, false
+	}
+
+	return s.Samples[best].Value, true
+}
+
+// HasMetric returns whether any point has a value for the metric
+func (d *MapDataDetails) HasMetric(metric string) bool {
+	for i := range d.Points {
+		if _, ok := d.Points[i].ExtraMetrics[metric]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// AddStream stores the stream and applies it to the points; a stream of a
+// metric replaces an earlier stream of the same metric
+func (d *MapDataDetails) AddStream(s SensorStream) {
+	d.Streams = append(d.Streams[:0:0], d.Streams...)
+
+	for i := range d.Streams {
+		if d.Streams[i].Metric == s.Metric {
+			d.Streams = append(d.Streams[:i], d.Streams[i+1:]...)
+			break
+		}
+	}
+
+	d.Streams = append(d.Streams, s)
+	d.ApplyStreams()
+}
+
+// ApplyStreams sets the values of the stored streams on the points that have
+// no value for the metric yet, or on all points for streams that replace the
+// values
+func (d *MapDataDetails) ApplyStreams() {
+	for si := range d.Streams {
+		s := &d.Streams[si]
+
+		for i := range d.Points {
+			p := &d.Points[i]
+
+			if _, ok := p.ExtraMetrics[s.Metric]; (ok && !s.Replace) || p.Time.IsZero() {
+				continue
+			}
+
+			v, ok := s.ValueAt(p.Time)
+			if !ok {
+				continue
+			}
+
+			if p.ExtraMetrics == nil {
+				p.ExtraMetrics = ExtraMetrics{}
+			}
+
+			p.ExtraMetrics.Set(s.Metric, v)
+		}
+	}
+}
+
+// updateLapStats recalculates the sensor statistics of the laps from the
+// points of the laps, after sensor streams were applied
+func (m *MapData) updateLapStats() {
+	if m.Details == nil {
+		return
+	}
+
+	for i := range m.Laps {
+		l := &m.Laps[i]
+
+		start, end := m.lapRange(l)
+
+		stats, ok := m.Details.StatsForRange(start, end)
+		if !ok {
+			continue
+		}
+
+		l.AverageHeartRate, l.MinHeartRate, l.MaxHeartRate = stats.AverageHeartRate, stats.MinHeartRate, stats.MaxHeartRate
+		l.AveragePower, l.MinPower, l.MaxPower = stats.AveragePower, stats.MinPower, stats.MaxPower
+		l.NormalizedPower, l.VariabilityIndex = stats.NormalizedPower, stats.VariabilityIndex
+		l.AverageCadence, l.MinCadence, l.MaxCadence = stats.AverageCadence, stats.MinCadence, stats.MaxCadence
+		l.AverageTemperature, l.MinTemperature, l.MaxTemperature = stats.AverageTemperature, stats.MinTemperature, stats.MaxTemperature
+	}
+}
+
+// shiftTime shifts the samples of the stream by the offset
+func (s *SensorStream) shiftTime(offset time.Duration) {
+	for i := range s.Samples {
+		s.Samples[i].Time = s.Samples[i].Time.Add(offset)
+	}
+}
@@ -84,11 +84,17 @@ func (sc *StatConfig) GetBucketFormatExpression(sqlDialect string) string {
 }
 
 func GetDateLimitExpression(sqlDialect string) string {
+	return GetColumnDateLimitExpression(sqlDialect, "workouts.date")
+}
+
+// GetColumnDateLimitExpression returns the condition that the date column is
+// after the relative date of the parameter
+func GetColumnDateLimitExpression(sqlDialect, column string) string {
 	switch sqlDialect {
 	case postgresDialect:
-		return "workouts.date > CURRENT_DATE + cast(? as interval)"
+		return column + " > CURRENT_DATE + cast(? as interval)"
 	default:
-		return "workouts.date > DATE(CURRENT_DATE, ?)"
+		return column + " > DATE(CURRENT_DATE, ?)"
 	}
 }
 
@@ -177,6 +183,10 @@ func (u *User) GetStatistics(statConfig StatConfig) (*Statistics, error) {
 		r.Buckets[result.WorkoutType].Buckets[result.Bucket] = result
 	}
 
+	if r.HeartRateZones, err = u.GetWeeklyHeartRateZones(statConfig); err != nil {
+		return nil, err
+	}
+
 	return r, nil
 }
 
@@ -0,0 +1,385 @@
+package model
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MaxOneRepMaxRepetitions is the highest number of repetitions of a set that
+// is used to estimate the one-rep max; estimates of longer sets are unreliable
+const MaxOneRepMaxRepetitions = 12
+
+// ExerciseUnknown is the exercise of sets without a known exercise
+const ExerciseUnknown = "unknown"
+
+// Exercise is an entry of the exercise catalogue
+type Exercise struct {
+	Model
+
+	UserID   uint64 `gorm:"not null;default:0;uniqueIndex:idx_exercises_user_name" json:"-"` // The user who added the exercise; 0 for the built-in catalogue
+	Name     string `gorm:"not null;uniqueIndex:idx_exercises_user_name" json:"name"`        // The name of the exercise, e.g. barbell_bench_press
+	Category string `gorm:"index" json:"category"`                                           // The category of the exercise, e.g. bench_press
+}
+
+// defaultExercises is the built-in exercise catalogue; exercises of imported
+// or manual sets that are not part of it, are added for the user who owns the
+// sets when they are saved
+var defaultExercises = []Exercise{
+	{Name: "barbell_bench_press", Category: "bench_press"},
+	{Name: "incline_barbell_bench_press", Category: "bench_press"},
+	{Name: "dumbbell_bench_press", Category: "bench_press"},
+	{Name: "incline_dumbbell_bench_press", Category: "bench_press"},
+	{Name: "barbell_back_squat", Category: "squat"},
+	{Name: "barbell_front_squat", Category: "squat"},
+	{Name: "goblet_squat", Category: "squat"},
+	{Name: "leg_press", Category: "squat"},
+	{Name: "barbell_deadlift", Category: "deadlift"},
+	{Name: "romanian_deadlift", Category: "deadlift"},
+	{Name: "sumo_deadlift", Category: "deadlift"},
+	{Name: "trap_bar_deadlift", Category: "deadlift"},
+	{Name: "overhead_barbell_press", Category: "shoulder_press"},
+	{Name: "overhead_dumbbell_press", Category: "shoulder_press"},
+	{Name: "bent_over_row", Category: "row"},
+	{Name: "dumbbell_row", Category: "row"},
+	{Name: "seated_cable_row", Category: "row"},
+	{Name: "pull_up", Category: "pull_up"},
+	{Name: "chin_up", Category: "pull_up"},
+	{Name: "lat_pulldown", Category: "pull_up"},
+	{Name: "push_up", Category: "push_up"},
+	{Name: "dip", Category: "triceps_extension"},
+	{Name: "barbell_biceps_curl", Category: "curl"},
+	{Name: "dumbbell_biceps_curl", Category: "curl"},
+	{Name: "triceps_pressdown", Category: "triceps_extension"},
+	{Name: "lateral_raise", Category: "lateral_raise"},
+	{Name: "barbell_hip_thrust", Category: "hip_raise"},
+	{Name: "walking_lunge", Category: "lunge"},
+	{Name: "leg_curl", Category: "leg_curl"},
+	{Name: "leg_extension", Category: "squat"},
+	{Name: "standing_calf_raise", Category: "calf_raise"},
+	{Name: "barbell_shrug", Category: "shrug"},
+	{Name: "power_clean", Category: "olympic_lift"},
+	{Name: "snatch", Category: "olympic_lift"},
+	{Name: "kettlebell_swing", Category: "hip_swing"},
+	{Name: "plank", Category: "plank"},
+}
+
+// NormalizeExerciseName returns the catalogue name of an exercise, e.g.
+// "Barbell Bench Press" becomes barbell_bench_press
+func NormalizeExerciseName(name string) string {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	return strings.Join(strings.FieldsFunc(name, func(r rune) bool {
+		return r == ' ' || r == '-' || r == '_'
+	}), "_")
+}
+
+// GetExercises returns the built-in exercise catalogue and the exercises added
+// by the user, ordered by category and name
+func GetExercises(db *gorm.DB, userID uint64) ([]*Exercise, error) {
+	var exercises []*Exercise
+
+	if err := scopeExercises(db, userID).Order("category ASC").Order("name ASC").Find(&exercises).Error; err != nil {
+		return nil, err
+	}
+
+	return exercises, nil
+}
+
+func seedExercises(db *gorm.DB) error {
+	exercises := make([]Exercise, len(defaultExercises))
+	copy(exercises, defaultExercises)
+
+	return db.Clauses(clause.OnConflict{DoNothing: true}).Create(&exercises).Error
+}
+
+// scopeExercises limits the query to the built-in catalogue and the exercises
+// added by the user
+func scopeExercises(db *gorm.DB, userID uint64) *gorm.DB {
+	return db.Where("user_id IN ?", []uint64{0, userID})
+}
+
+// findOrCreateExercise looks up the exercise by name in the catalogue and the
+// exercises of the user, and adds it for the user if it does not exist yet
+func findOrCreateExercise(db *gorm.DB, userID uint64, e *Exercise) error {
+	e.Name = NormalizeExerciseName(e.Name)
+
+	err := scopeExercises(db, userID).Where("name = ?", e.Name).Order("user_id ASC").First(e).Error
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	e.UserID = userID
+
+	return db.Create(e).Error
+}
+
+// WorkoutSet is a single set of a strength workout
+type WorkoutSet struct {
+	MapDataID uint64 `gorm:"not null;primaryKey;index:idx_map_data_sets_parent_order,unique" json:"-"`
+	SortOrder int    `gorm:"not null;primaryKey;index:idx_map_data_sets_parent_order,unique" json:"-"`
+
+	ExerciseID uint64    `gorm:"index" json:"exerciseID"`                                // The ID of the exercise in the catalogue
+	Exercise   *Exercise `gorm:"constraint:OnDelete:RESTRICT" json:"exercise,omitempty"` // The exercise of the set
+
+	Start       time.Time     `json:"start"`       // The start of the set
+	Duration    time.Duration `json:"duration"`    // The duration of the set
+	Rest        time.Duration `json:"rest"`        // The rest after the set
+	Repetitions int           `json:"repetitions"` // The number of repetitions
+	Weight      float64       `json:"weight"`      // The weight, in kg
+	RPE         float64       `json:"rpe"`         // The rate of perceived exertion, from 1 to 10; 0 if unknown
+}
+
+func (WorkoutSet) TableName() string {
+	return "map_data_sets"
+}
+
+// ExerciseName returns the name of the exercise of the set
+func (s *WorkoutSet) ExerciseName() string {
+	if s.Exercise == nil {
+		return ""
+	}
+
+	return s.Exercise.Name
+}
+
+// Volume returns the weight times the number of repetitions
+func (s *WorkoutSet) Volume() float64 {
+	return s.Weight * float64(s.Repetitions)
+}
+
+// EstimatedOneRepMax returns the estimated one-rep max of the set, using the
+// Epley formula
+func (s *WorkoutSet) EstimatedOneRepMax() float64 {
+	return EstimatedOneRepMax(s.Weight, s.Repetitions)
+}
+
+// EstimatedOneRepMax returns the estimated one-rep max for a weight lifted a
+// number of times, using the Epley formula
+func EstimatedOneRepMax(weight float64, repetitions int) float64 {
+	if weight <= 0 || repetitions <= 0 || repetitions > MaxOneRepMaxRepetitions {
+		return 0
+	}
+
+	if repetitions == 1 {
+		return weight
+	}
+
+	return weight * (1 + float64(repetitions)/30)
+}
+
+// UpdateSetTotals sets the total repetitions and weight from the sets, unless
+// they are already known
+func (m *MapData) UpdateSetTotals() {
+	if len(m.Sets) == 0 {
+		return
+	}
+
+	var (
+		repetitions int
+		volume      float64
+	)
+
+	for i := range m.Sets {
+		repetitions += m.Sets[i].Repetitions
+		volume += m.Sets[i].Volume()
+	}
+
+	if m.TotalRepetitions == 0 {
+		m.TotalRepetitions = repetitions
+	}
+
+	if m.TotalWeight == 0 {
+		m.TotalWeight = volume
+	}
+}
+
+func (m *MapData) saveSets(tx *gorm.DB) error {
+	var userID uint64
+
+	if len(m.Sets) > 0 {
+		if err := tx.Model(&Workout{}).Where("id = ?", m.WorkoutID).Select("user_id").Scan(&userID).Error; err != nil {
+			return err
+		}
+	}
+
+	for i := range m.Sets {
+		m.Sets[i].MapDataID = m.ID
+		m.Sets[i].SortOrder = i
+
+		if m.Sets[i].Exercise == nil && m.Sets[i].ExerciseID == 0 {
+			m.Sets[i].Exercise = &Exercise{Name: ExerciseUnknown}
+		}
+
+		if e := m.Sets[i].Exercise; e != nil && e.ID == 0 {
+			if err := findOrCreateExercise(tx, userID, e); err != nil {
+				return err
+			}
+		}
+
+		if e := m.Sets[i].Exercise; e != nil {
+			m.Sets[i].ExerciseID = e.ID
+		}
+	}
+
+	if err := tx.Where("map_data_id = ?", m.ID).Delete(&WorkoutSet{}).Error; err != nil {
+		return err
+	}
+
+	if len(m.Sets) == 0 {
+		return nil
+	}
+
+	return tx.Omit("Exercise").CreateInBatches(&m.Sets, mapDataClimbsInsertBatchSize).Error
+}
+
+// ExerciseEffort is the summary of the sets of one exercise in a single
+// workout
+type ExerciseEffort struct {
+	Exercise    string    `json:"exercise"`    // The name of the exercise
+	Category    string    `json:"category"`    // The category of the exercise
+	WorkoutID   uint64    `json:"workoutID"`   // The workout of the sets
+	Date        time.Time `json:"date"`        // The date of the workout
+	Sets        int       `json:"sets"`        // The number of sets
+	Repetitions int       `json:"repetitions"` // The total number of repetitions
+	MaxWeight   float64   `json:"maxWeight"`   // The heaviest weight of the sets, in kg
+	OneRepMax   float64   `json:"oneRepMax"`   // The best estimated one-rep max of the sets, in kg
+	Volume      float64   `json:"volume"`      // The total weight times repetitions of the sets, in kg
+}
+
+// ExerciseEfforts returns the effort per exercise of the sets of the workout,
+// in the order the exercises were first done
+func (w *Workout) ExerciseEfforts() []ExerciseEffort {
+	if w.Data == nil {
+		return nil
+	}
+
+	var efforts []ExerciseEffort
+
+	index := map[string]int{}
+
+	for i := range w.Data.Sets {
+		s := &w.Data.Sets[i]
+
+		name := s.ExerciseName()
+		if name == "" {
+			continue
+		}
+
+		idx, ok := index[name]
+		if !ok {
+			idx = len(efforts)
+			index[name] = idx
+
+			efforts = append(efforts, ExerciseEffort{
+				Exercise:  name,
+				Category:  s.Exercise.Category,
+				WorkoutID: w.ID,
+				Date:      w.Date,
+			})
+		}
+
+		e := &efforts[idx]
+		e.Sets++
+		e.Repetitions += s.Repetitions
+		e.Volume += s.Volume()
+		e.MaxWeight = max(e.MaxWeight, s.Weight)
+		e.OneRepMax = max(e.OneRepMax, s.EstimatedOneRepMax())
+	}
+
+	return efforts
+}
+
+// ExerciseRecords returns the best estimated one-rep max, heaviest weight and
+// biggest volume per exercise of the workouts, ordered by exercise
+func ExerciseRecords(workouts []*Workout) []ExerciseRecord {
+	records := map[string]*ExerciseRecord{}
+
+	for _, w := range workouts {
+		if w == nil {
+			continue
+		}
+
+		for _, e := range w.ExerciseEfforts() {
+			r, ok := records[e.Exercise]
+			if !ok {
+				r = &ExerciseRecord{Exercise: e.Exercise, Category: e.Category}
+				records[e.Exercise] = r
+			}
+
+			r.OneRepMax.update(e.OneRepMax, e.WorkoutID, e.Date)
+			r.MaxWeight.update(e.MaxWeight, e.WorkoutID, e.Date)
+			r.Volume.update(e.Volume, e.WorkoutID, e.Date)
+		}
+	}
+
+	result := make([]ExerciseRecord, 0, len(records))
+	for _, r := range records {
+		result = append(result, *r)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Exercise < result[j].Exercise
+	})
+
+	return result
+}
+
+// ExerciseRanking returns the efforts of the workouts for one exercise,
+// ordered by the metric (one-rep-max, max-weight or volume); the first
+// workout wins a tie
+func ExerciseRanking(workouts []*Workout, exercise, metric string) []ExerciseEffort {
+	exercise = NormalizeExerciseName(exercise)
+
+	var efforts []ExerciseEffort
+
+	for _, w := range workouts {
+		if w == nil {
+			continue
+		}
+
+		for _, e := range w.ExerciseEfforts() {
+			if e.Exercise == exercise && exerciseMetric(e, metric) > 0 {
+				efforts = append(efforts, e)
+			}
+		}
+	}
+
+	sort.SliceStable(efforts, func(i, j int) bool {
+		a, b := exerciseMetric(efforts[i], metric), exerciseMetric(efforts[j], metric)
+		if a != b {
+			return a > b
+		}
+
+		return efforts[i].Date.Before(efforts[j].Date)
+	})
+
+	return efforts
+}
+
+func exerciseMetric(e ExerciseEffort, metric string) float64 {
+	switch metric {
+	case "max-weight":
+		return e.MaxWeight
+	case "volume":
+		return e.Volume
+	default:
+		return e.OneRepMax
+	}
+}
+
+func (r *Float64Record) update(value float64, id uint64, date time.Time) {
+	if value <= 0 {
+		return
+	}
+
+	if value > r.Value || (value == r.Value && date.Before(r.Date)) {
+		r.Value = value
+		r.ID = id
+		r.Date = date
+	}
+}
@@ -0,0 +1,135 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testSets are 3 sets of bench press and a set of squats
+func testSets() []WorkoutSet {
+	bench := &Exercise{Name: "barbell_bench_press", Category: "bench_press"}
+	squat := &Exercise{Name: "barbell_back_squat", Category: "squat"}
+
+	return []WorkoutSet{
+		{Exercise: bench, Repetitions: 10, Weight: 60, Rest: 90 * time.Second},
+		{Exercise: bench, Repetitions: 5, Weight: 80, Rest: 90 * time.Second},
+		{Exercise: bench, Repetitions: 1, Weight: 90},
+		{Exercise: squat, Repetitions: 8, Weight: 100, RPE: 8},
+	}
+}
+
+func TestNormalizeExerciseName(t *testing.T) {
+	assert.Equal(t, "barbell_bench_press", NormalizeExerciseName(" Barbell Bench-Press "))
+	assert.Equal(t, "pull_up", NormalizeExerciseName("pull__up"))
+	assert.Empty(t, NormalizeExerciseName("  "))
+}
+
+func TestEstimatedOneRepMax(t *testing.T) {
+	assert.InDelta(t, 90, EstimatedOneRepMax(90, 1), 0.001)
+	assert.InDelta(t, 80, EstimatedOneRepMax(60, 10), 0.001)
+	assert.Zero(t, EstimatedOneRepMax(60, MaxOneRepMaxRepetitions+1))
+	assert.Zero(t, EstimatedOneRepMax(0, 5))
+}
+
+func TestMapData_UpdateSetTotals(t *testing.T) {
+	m := &MapData{Sets: testSets()}
+	m.UpdateSetTotals()
+
+	assert.Equal(t, 24, m.TotalRepetitions)
+	assert.InDelta(t, 600+400+90+800, m.TotalWeight, 0.001)
+
+	// Known totals are kept
+	m = &MapData{Sets: testSets(), WorkoutData: WorkoutData{TotalRepetitions: 3}}
+	m.UpdateSetTotals()
+
+	assert.Equal(t, 3, m.TotalRepetitions)
+}
+
+func TestWorkout_ExerciseEfforts(t *testing.T) {
+	w := &Workout{Data: &MapData{Sets: testSets()}}
+	w.ID = 1
+
+	efforts := w.ExerciseEfforts()
+	require.Len(t, efforts, 2)
+
+	e := efforts[0]
+	assert.Equal(t, "barbell_bench_press", e.Exercise)
+	assert.Equal(t, 3, e.Sets)
+	assert.Equal(t, 16, e.Repetitions)
+	assert.InDelta(t, 90, e.MaxWeight, 0.001)
+	assert.InDelta(t, 93.333, e.OneRepMax, 0.001)
+	assert.InDelta(t, 1090, e.Volume, 0.001)
+
+	assert.Equal(t, "barbell_back_squat", efforts[1].Exercise)
+}
+
+func TestExerciseRecords(t *testing.T) {
+	first := &Workout{Date: time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC), Data: &MapData{Sets: testSets()}}
+	first.ID = 1
+
+	second := &Workout{Date: time.Date(2024, 3, 8, 18, 0, 0, 0, time.UTC), Data: &MapData{Sets: testSets()[:1]}}
+	second.ID = 2
+	second.Data.Sets[0].Weight = 75
+
+	records := ExerciseRecords([]*Workout{first, second})
+	require.Len(t, records, 2)
+
+	assert.Equal(t, "barbell_back_squat", records[0].Exercise)
+
+	bench := records[1]
+	assert.InDelta(t, 100, bench.OneRepMax.Value, 0.001)
+	assert.Equal(t, uint64(2), bench.OneRepMax.ID)
+	assert.InDelta(t, 90, bench.MaxWeight.Value, 0.001)
+	assert.Equal(t, uint64(1), bench.MaxWeight.ID)
+	assert.Equal(t, uint64(1), bench.Volume.ID)
+
+	ranking := ExerciseRanking([]*Workout{first, second}, "Barbell Bench Press", "max-weight")
+	require.Len(t, ranking, 2)
+	assert.Equal(t, uint64(1), ranking[0].WorkoutID)
+}
+
+func TestMapData_SaveSets(t *testing.T) {
+	db := createMemoryDB(t)
+	createDefaultUser(t, db)
+
+	sets := testSets()
+	sets[3].Exercise = &Exercise{Name: "Zercher Squat", Category: "squat"}
+
+	w := &Workout{
+		Name:   "strength",
+		Date:   time.Date(2024, 3, 1, 18, 0, 0, 0, time.UTC),
+		UserID: 1,
+		Type:   WorkoutTypeWeightLifting,
+		Data:   &MapData{Sets: sets},
+	}
+	w.SetContent("strength.fit", []byte("strength"))
+
+	require.NoError(t, w.Create(db))
+
+	stored, err := GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Data.Sets, 4)
+	assert.Equal(t, "barbell_bench_press", stored.Data.Sets[0].ExerciseName())
+	assert.Equal(t, "zercher_squat", stored.Data.Sets[3].ExerciseName())
+	assert.InDelta(t, 8, stored.Data.Sets[3].RPE, 0.001)
+
+	// New exercises are added for the user only
+	exercises, err := GetExercises(db, 1)
+	require.NoError(t, err)
+	assert.Len(t, exercises, len(defaultExercises)+1)
+
+	exercises, err = GetExercises(db, 2)
+	require.NoError(t, err)
+	assert.Len(t, exercises, len(defaultExercises))
+
+	// Saving again replaces the sets
+	stored.Data.Sets = stored.Data.Sets[:2]
+	require.NoError(t, stored.Data.Save(db))
+
+	stored, err = GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Data.Sets, 2)
+}
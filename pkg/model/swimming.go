@@ -0,0 +1,222 @@
+package model
+
+import (
+	"math"
+	"time"
+)
+
+const (
+	MeterPerYard = 0.9144
+
+	// SwimStrokeMixed is the stroke of an interval with lengths of different
+	// strokes
+	SwimStrokeMixed = "mixed"
+)
+
+// WorkoutLength is a single length of a pool swim; a length without distance
+// is a rest between two intervals
+type WorkoutLength struct {
+	MapDataID uint64 `gorm:"not null;primaryKey;index:idx_map_data_lengths_parent_order,unique" json:"-"`
+	SortOrder int    `gorm:"not null;primaryKey;index:idx_map_data_lengths_parent_order,unique" json:"-"`
+
+	Start    time.Time     `json:"start"`    // The start of the length
+	Duration time.Duration `json:"duration"` // The duration of the length
+	Distance float64       `json:"distance"` // The length of the pool, in meters; 0 for a rest
+	Stroke   string        `json:"stroke"`   // The swim stroke, e.g. freestyle or breaststroke
+	Strokes  int           `json:"strokes"`  // The number of strokes
+}
+
+func (WorkoutLength) TableName() string {
+	return "map_data_lengths"
+}
+
+// IsRest returns whether the length is a rest, rather than swum
+func (l *WorkoutLength) IsRest() bool {
+	return l.Distance <= 0
+}
+
+// SWOLF returns the swim golf score of the length: the number of strokes plus
+// the number of seconds
+func (l *WorkoutLength) SWOLF() float64 {
+	if l.IsRest() || l.Strokes == 0 {
+		return 0
+	}
+
+	return float64(l.Strokes) + math.Round(l.Duration.Seconds())
+}
+
+// StrokeRate returns the number of strokes per minute
+func (l *WorkoutLength) StrokeRate() float64 {
+	return strokeRate(l.Strokes, l.Duration)
+}
+
+// SwimInterval is a series of lengths swum without rest
+type SwimInterval struct {
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"` // The duration of the lengths, without the rest
+	Rest     time.Duration `json:"rest"`     // The rest after the interval
+	Distance float64       `json:"distance"`
+	Stroke   string        `json:"stroke"` // The stroke of all lengths, or mixed
+	Strokes  int           `json:"strokes"`
+	Lengths  int           `json:"lengths"` // The number of lengths
+	SWOLF    float64       `json:"swolf"`   // The average SWOLF of the lengths
+}
+
+// StrokeRate returns the number of strokes per minute
+func (i *SwimInterval) StrokeRate() float64 {
+	return strokeRate(i.Strokes, i.Duration)
+}
+
+// SwimIntervals groups the lengths of the workout in intervals, separated by
+// rests
+func (w *Workout) SwimIntervals() []SwimInterval {
+	if w.Data == nil {
+		return nil
+	}
+
+	return SwimIntervals(w.Data.Lengths)
+}
+
+// SwimIntervals groups lengths in intervals, separated by rests
+func SwimIntervals(lengths []WorkoutLength) []SwimInterval {
+	var (
+		intervals []SwimInterval
+		current   *SwimInterval
+		swolf     float64
+		swolfs    int
+	)
+
+	finish := func() {
+		if current == nil {
+			return
+		}
+
+		if swolfs > 0 {
+			current.SWOLF = swolf / float64(swolfs)
+		}
+
+		intervals = append(intervals, *current)
+		current, swolf, swolfs = nil, 0, 0
+	}
+
+	for i := range lengths {
+		l := &lengths[i]
+
+		if l.IsRest() {
+			if current != nil {
+				current.Rest += l.Duration
+				finish()
+			} else if len(intervals) > 0 {
+				intervals[len(intervals)-1].Rest += l.Duration
+			}
+
+			continue
+		}
+
+		if current == nil {
+			current = &SwimInterval{Start: l.Start, Stroke: l.Stroke}
+		}
+
+		current.Duration += l.Duration
+		current.Distance += l.Distance
+		current.Strokes += l.Strokes
+		current.Lengths++
+
+		if current.Stroke != l.Stroke {
+			current.Stroke = SwimStrokeMixed
+		}
+
+		if s := l.SWOLF(); s > 0 {
+			swolf += s
+			swolfs++
+		}
+	}
+
+	finish()
+
+	return intervals
+}
+
+// SwimSummary are the totals of the lengths of a pool swim
+type SwimSummary struct {
+	PoolLength    float64       `json:"poolLength"`    // In meters
+	Lengths       int           `json:"lengths"`       // The number of lengths swum
+	Intervals     int           `json:"intervals"`     // The number of intervals
+	Distance      float64       `json:"distance"`      // The distance of the lengths swum
+	Duration      time.Duration `json:"duration"`      // The duration of the lengths swum, without rest
+	Rest          time.Duration `json:"rest"`          // The total rest
+	Strokes       int           `json:"strokes"`       // The total number of strokes
+	AverageSWOLF  float64       `json:"averageSWOLF"`  // The average SWOLF of the lengths
+	StrokeRate    float64       `json:"strokeRate"`    // The average number of strokes per minute
+	StrokesLength float64       `json:"strokesLength"` // The average number of strokes per length
+}
+
+// SwimSummary returns the totals of the lengths of a pool swim; it returns
+// false if the workout has no lengths
+func (w *Workout) SwimSummary() (SwimSummary, bool) {
+	if w.Data == nil || len(w.Data.Lengths) == 0 {
+		return SwimSummary{}, false
+	}
+
+	s := SwimSummary{PoolLength: w.Data.PoolLength}
+
+	var (
+		swolf  float64
+		swolfs int
+	)
+
+	for i := range w.Data.Lengths {
+		l := &w.Data.Lengths[i]
+
+		if l.IsRest() {
+			s.Rest += l.Duration
+			continue
+		}
+
+		s.Lengths++
+		s.Distance += l.Distance
+		s.Duration += l.Duration
+		s.Strokes += l.Strokes
+
+		if v := l.SWOLF(); v > 0 {
+			swolf += v
+			swolfs++
+		}
+	}
+
+	s.Intervals = len(w.SwimIntervals())
+	s.StrokeRate = strokeRate(s.Strokes, s.Duration)
+
+	if swolfs > 0 {
+		s.AverageSWOLF = swolf / float64(swolfs)
+	}
+
+	if s.Lengths > 0 {
+		s.StrokesLength = float64(s.Strokes) / float64(s.Lengths)
+	}
+
+	return s, true
+}
+
+// SwimPace returns the time needed to swim 100 meters or yards, depending on
+// the preferred units
+func SwimPace(distance float64, duration time.Duration, units *UserPreferredUnits) time.Duration {
+	if distance <= 0 {
+		return 0
+	}
+
+	per := 100.0
+	if units != nil {
+		per = units.SwimPaceDistance()
+	}
+
+	return time.Duration(float64(duration) * per / distance)
+}
+
+func strokeRate(strokes int, d time.Duration) float64 {
+	if strokes == 0 || d <= 0 {
+		return 0
+	}
+
+	return float64(strokes) / d.Minutes()
+}
@@ -0,0 +1,123 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testLengths are 2 intervals of 25m lengths: 2 lengths freestyle, a rest, and
+// a length of freestyle and one of breaststroke
+func testLengths() []WorkoutLength {
+	start := time.Date(2024, 2, 1, 7, 0, 0, 0, time.UTC)
+
+	lengths := []WorkoutLength{
+		{Duration: 30 * time.Second, Distance: 25, Stroke: "freestyle", Strokes: 15},
+		{Duration: 32 * time.Second, Distance: 25, Stroke: "freestyle", Strokes: 17},
+		{Duration: 60 * time.Second},
+		{Duration: 31 * time.Second, Distance: 25, Stroke: "freestyle", Strokes: 16},
+		{Duration: 40 * time.Second, Distance: 25, Stroke: "breaststroke", Strokes: 10},
+	}
+
+	for i := range lengths {
+		lengths[i].Start = start
+		start = start.Add(lengths[i].Duration)
+	}
+
+	return lengths
+}
+
+func TestWorkoutLength_SWOLF(t *testing.T) {
+	lengths := testLengths()
+
+	assert.InDelta(t, 45, lengths[0].SWOLF(), 0.001)
+	assert.InDelta(t, 30, lengths[0].StrokeRate(), 0.001)
+	assert.True(t, lengths[2].IsRest())
+	assert.Zero(t, lengths[2].SWOLF())
+}
+
+func TestSwimIntervals(t *testing.T) {
+	intervals := SwimIntervals(testLengths())
+	require.Len(t, intervals, 2)
+
+	assert.Equal(t, 2, intervals[0].Lengths)
+	assert.InDelta(t, 50, intervals[0].Distance, 0.001)
+	assert.Equal(t, 62*time.Second, intervals[0].Duration)
+	assert.Equal(t, time.Minute, intervals[0].Rest)
+	assert.Equal(t, "freestyle", intervals[0].Stroke)
+	assert.Equal(t, 32, intervals[0].Strokes)
+	assert.InDelta(t, 47, intervals[0].SWOLF, 0.001)
+
+	assert.Equal(t, SwimStrokeMixed, intervals[1].Stroke)
+	assert.Zero(t, intervals[1].Rest)
+}
+
+func TestWorkout_SwimSummary(t *testing.T) {
+	w := &Workout{Data: &MapData{}}
+
+	_, ok := w.SwimSummary()
+	assert.False(t, ok)
+
+	w.Data.PoolLength = 25
+	w.Data.Lengths = testLengths()
+
+	s, ok := w.SwimSummary()
+	require.True(t, ok)
+
+	assert.Equal(t, 4, s.Lengths)
+	assert.Equal(t, 2, s.Intervals)
+	assert.InDelta(t, 100, s.Distance, 0.001)
+	assert.Equal(t, 133*time.Second, s.Duration)
+	assert.Equal(t, time.Minute, s.Rest)
+	assert.Equal(t, 58, s.Strokes)
+	assert.InDelta(t, 14.5, s.StrokesLength, 0.001)
+}
+
+func TestSwimPace(t *testing.T) {
+	assert.Equal(t, 2*time.Minute, SwimPace(50, time.Minute, nil))
+	assert.Equal(t, 2*time.Minute, SwimPace(50, time.Minute, &UserPreferredUnits{}))
+	assert.Zero(t, SwimPace(0, time.Minute, nil))
+
+	yards := &UserPreferredUnits{SwimPaceRaw: "100yd"}
+	assert.Equal(t, "min/100yd", yards.SwimPace())
+	assert.InDelta(t, (109728 * time.Millisecond).Seconds(), SwimPace(50, time.Minute, yards).Seconds(), 0.001)
+
+	// By default, the swim pace follows the distance unit
+	assert.Equal(t, "min/100yd", UserPreferredUnits{DistanceRaw: "mi"}.SwimPace())
+	assert.Equal(t, "min/100m", UserPreferredUnits{DistanceRaw: "mi", SwimPaceRaw: "100m"}.SwimPace())
+}
+
+func TestMapData_SaveLengths(t *testing.T) {
+	db := createMemoryDB(t)
+	createDefaultUser(t, db)
+
+	w := &Workout{
+		Name:   "pool swim",
+		Date:   time.Date(2024, 2, 1, 7, 0, 0, 0, time.UTC),
+		UserID: 1,
+		Type:   WorkoutTypeSwimming,
+		Data: &MapData{
+			WorkoutData: WorkoutData{PoolLength: 25},
+			Lengths:     testLengths(),
+		},
+	}
+	w.SetContent("pool.fit", []byte("pool"))
+
+	require.NoError(t, w.Create(db))
+
+	stored, err := GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	require.Len(t, stored.Data.Lengths, 5)
+	assert.InDelta(t, 25, stored.Data.PoolLength, 0.001)
+	assert.Equal(t, "breaststroke", stored.Data.Lengths[4].Stroke)
+
+	// Saving again replaces the lengths
+	stored.Data.Lengths = stored.Data.Lengths[:2]
+	require.NoError(t, stored.Data.Save(db))
+
+	stored, err = GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Data.Lengths, 2)
+}
@@ -0,0 +1,332 @@
+package model
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/tkrajina/gpxgo/gpx"
+)
+
+// TrackLimits are the physically plausible limits of movement for a type of
+// workout; points that would need a higher speed or acceleration are GPS
+// errors
+type TrackLimits struct {
+	MaxSpeed        float64 // In m/s
+	MaxAcceleration float64 // In m/s²
+}
+
+// DefaultTrackLimits are the limits for workout types without their own
+var DefaultTrackLimits = TrackLimits{MaxSpeed: 70, MaxAcceleration: 10}
+
+// TrackLimitsPerType are the limits per workout type; they can be overridden
+// with SetTrackLimits
+var TrackLimitsPerType = map[WorkoutType]TrackLimits{
+	WorkoutTypeWalking:       {MaxSpeed: 4, MaxAcceleration: 2},
+	WorkoutTypeHiking:        {MaxSpeed: 4, MaxAcceleration: 2},
+	WorkoutTypeGolfing:       {MaxSpeed: 10, MaxAcceleration: 3},
+	WorkoutTypeRunning:       {MaxSpeed: 11, MaxAcceleration: 4},
+	WorkoutTypeSwimming:      {MaxSpeed: 3, MaxAcceleration: 2},
+	WorkoutTypeKayaking:      {MaxSpeed: 7, MaxAcceleration: 2},
+	WorkoutTypeRowing:        {MaxSpeed: 7, MaxAcceleration: 2},
+	WorkoutTypeInlineSkating: {MaxSpeed: 18, MaxAcceleration: 4},
+	WorkoutTypeHorseRiding:   {MaxSpeed: 20, MaxAcceleration: 6},
+	WorkoutTypeCycling:       {MaxSpeed: 30, MaxAcceleration: 6},
+	WorkoutTypeECycling:      {MaxSpeed: 30, MaxAcceleration: 6},
+	WorkoutTypeSkiing:        {MaxSpeed: 45, MaxAcceleration: 8},
+	WorkoutTypeSnowboarding:  {MaxSpeed: 40, MaxAcceleration: 8},
+}
+
+// ErrInvalidTrackLimits is returned for configured track limits that can not
+// be parsed
+var ErrInvalidTrackLimits = errors.New("invalid track limits")
+
+// SetTrackLimits overrides the track limits per workout type, and the default
+// limits, from the configuration; the configuration is a comma-separated list
+// of type=max speed:max acceleration, in m/s and m/s², e.g.
+// "running=12:5,default=60:10"
+func SetTrackLimits(config string) error {
+	limits, err := ParseTrackLimits(config)
+	if err != nil {
+		return err
+	}
+
+	for t, l := range limits {
+		if t == trackLimitsDefault {
+			DefaultTrackLimits = l
+			continue
+		}
+
+		TrackLimitsPerType[t] = l
+	}
+
+	return nil
+}
+
+// trackLimitsDefault is the name of the default limits in the configuration
+const trackLimitsDefault WorkoutType = "default"
+
+// ParseTrackLimits parses configured track limits per workout type
+func ParseTrackLimits(config string) (map[WorkoutType]TrackLimits, error) {
+	limits := map[WorkoutType]TrackLimits{}
+
+	for entry := range strings.SplitSeq(config, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, values, ok := strings.Cut(entry, "=")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTrackLimits, entry)
+		}
+
+		t := AsWorkoutType(strings.TrimSpace(name))
+		if _, known := workoutTypeConfigs[t]; !known && t != trackLimitsDefault {
+			return nil, fmt.Errorf("%w: unknown workout type %q", ErrInvalidTrackLimits, name)
+		}
+
+		speed, acceleration, ok := strings.Cut(values, ":")
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTrackLimits, entry)
+		}
+
+		l := TrackLimits{}
+
+		var err error
+
+		if l.MaxSpeed, err = strconv.ParseFloat(strings.TrimSpace(speed), 64); err != nil || l.MaxSpeed <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTrackLimits, entry)
+		}
+
+		if l.MaxAcceleration, err = strconv.ParseFloat(strings.TrimSpace(acceleration), 64); err != nil || l.MaxAcceleration <= 0 {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidTrackLimits, entry)
+		}
+
+		limits[t] = l
+	}
+
+	return limits, nil
+}
+
+// LimitsForType returns the track limits for the workout type
+func LimitsForType(t WorkoutType) TrackLimits {
+	if l, ok := TrackLimitsPerType[t]; ok {
+		return l
+	}
+
+	return DefaultTrackLimits
+}
+
+const (
+	// kalmanMeasurementNoise is the assumed accuracy of a GPS fix, in meters
+	kalmanMeasurementNoise = 5.0
+	// kalmanProcessNoise is how much the position may change unpredictably,
+	// in meters per second
+	kalmanProcessNoise = 3.0
+)
+
+// TrackCleaningResult is what the cleaning changed
+type TrackCleaningResult struct {
+	Spikes    int // Points that jumped away and back, moved between their neighbours
+	Teleports int // Jumps to a new position the track continued from, not counted as distance
+}
+
+// Corrected returns the number of corrected points
+func (r TrackCleaningResult) Corrected() int {
+	return r.Spikes + r.Teleports
+}
+
+// CleanTrack corrects the GPS errors of the points: single points that jump
+// away and back (spikes) are moved between their neighbours, and jumps the
+// track continues from (teleports) are not counted as distance. Optionally,
+// the positions are smoothed with a Kalman filter. The distances and totals
+// are recalculated; points are never removed, so the indexes of
+// removed point ranges stay valid.
+func (m *MapData) CleanTrack(limits TrackLimits, smooth bool) TrackCleaningResult {
+	result := TrackCleaningResult{}
+
+	if m.Details == nil || len(m.Details.Points) < 2 {
+		return result
+	}
+
+	points := m.Details.Points
+	teleports := map[int]bool{}
+	last := -1
+	lastSpeed := 0.0
+
+	for i := range points {
+		if !hasPosition(&points[i]) || points[i].Time.IsZero() {
+			continue
+		}
+
+		if last < 0 {
+			last = i
+			continue
+		}
+
+		speed, ok := speedBetween(&points[last], &points[i])
+		if !ok {
+			continue
+		}
+
+		if isPlausible(limits, speed, lastSpeed, points[i].Time.Sub(points[last].Time)) {
+			last, lastSpeed = i, speed
+			continue
+		}
+
+		next := nextPositioned(points, i)
+
+		switch {
+		case next >= 0 && plausibleBetween(limits, &points[last], &points[next]):
+			// The point jumped away and the track came back: a spike
+			interpolatePosition(&points[last], &points[i], &points[next])
+			result.Spikes++
+
+			speed, _ = speedBetween(&points[last], &points[i])
+		case next < 0 || plausibleBetween(limits, &points[i], &points[next]):
+			// The track continues from the new position: a teleport
+			teleports[i] = true
+			result.Teleports++
+
+			speed = 0
+		default:
+			// The next point is off as well; it is judged on its own
+			continue
+		}
+
+		last, lastSpeed = i, speed
+	}
+
+	if smooth {
+		smoothPositions(points, teleports)
+	}
+
+	if result.Corrected() == 0 && !smooth {
+		return result
+	}
+
+	updatePointDistances(points, teleports)
+	m.updateTotalsFromPoints()
+
+	return result
+}
+
+func hasPosition(p *MapPoint) bool {
+	return p.Lat != 0 || p.Lng != 0
+}
+
+func nextPositioned(points []MapPoint, i int) int {
+	for j := i + 1; j < len(points); j++ {
+		if hasPosition(&points[j]) && !points[j].Time.IsZero() {
+			return j
+		}
+	}
+
+	return -1
+}
+
+// speedBetween returns the speed needed to move between both points
+func speedBetween(a, b *MapPoint) (float64, bool) {
+	dt := b.Time.Sub(a.Time).Seconds()
+	if dt <= 0 {
+		return 0, false
+	}
+
+	return a.DistanceTo(b) / dt, true
+}
+
+func isPlausible(limits TrackLimits, speed, previousSpeed float64, dt time.Duration) bool {
+	if speed > limits.MaxSpeed {
+		return false
+	}
+
+	if dt <= 0 {
+		return true
+	}
+
+	return (speed-previousSpeed)/dt.Seconds() <= limits.MaxAcceleration
+}
+
+func plausibleBetween(limits TrackLimits, a, b *MapPoint) bool {
+	speed, ok := speedBetween(a, b)
+
+	return ok && speed <= limits.MaxSpeed
+}
+
+// interpolatePosition moves the point to the line between both neighbours,
+// proportionally to the time
+func interpolatePosition(prev, p, next *MapPoint) {
+	f := p.Time.Sub(prev.Time).Seconds() / next.Time.Sub(prev.Time).Seconds()
+
+	p.Lat = prev.Lat + f*(next.Lat-prev.Lat)
+	p.Lng = prev.Lng + f*(next.Lng-prev.Lng)
+}
+
+// smoothPositions applies a Kalman filter to the positions, assuming a fixed
+// measurement accuracy; the filter starts over at every teleport
+func smoothPositions(points []MapPoint, teleports map[int]bool) {
+	var (
+		lat, lng, variance float64
+		prevTime           time.Time
+		initialized        bool
+	)
+
+	for i := range points {
+		p := &points[i]
+		if !hasPosition(p) {
+			continue
+		}
+
+		if !initialized || teleports[i] {
+			lat, lng, prevTime = p.Lat, p.Lng, p.Time
+			variance = kalmanMeasurementNoise * kalmanMeasurementNoise
+			initialized = true
+
+			continue
+		}
+
+		if dt := p.Time.Sub(prevTime).Seconds(); dt > 0 {
+			variance += dt * kalmanProcessNoise * kalmanProcessNoise
+			prevTime = p.Time
+		}
+
+		gain := variance / (variance + kalmanMeasurementNoise*kalmanMeasurementNoise)
+
+		lat += gain * (p.Lat - lat)
+		lng += gain * (p.Lng - lng)
+		variance *= 1 - gain
+
+		p.Lat, p.Lng = lat, lng
+	}
+}
+
+// updatePointDistances recalculates the distances between the points; the
+// jumps to teleported points are not counted
+func updatePointDistances(points []MapPoint, teleports map[int]bool) {
+	prev := -1
+
+	for i := range points {
+		p := &points[i]
+
+		if prev < 0 || teleports[i] || !hasPosition(p) {
+			p.Distance, p.Distance2D = 0, 0
+		} else {
+			q := &points[prev]
+			p.Distance2D = gpx.Distance2D(q.Lat, q.Lng, p.Lat, p.Lng, false)
+			p.Distance = gpx.Distance3D(q.Lat, q.Lng, *gpx.NewNullableFloat64(q.Elevation),
+				p.Lat, p.Lng, *gpx.NewNullableFloat64(p.Elevation), false)
+		}
+
+		if math.IsNaN(p.Distance) {
+			p.Distance = p.Distance2D
+		}
+
+		if hasPosition(p) {
+			prev = i
+		}
+	}
+}
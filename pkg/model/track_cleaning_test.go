@@ -0,0 +1,81 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// cleaningTestData walks north at about 1.4 m/s, with a spike at point 10
+// and a jump to a new position from point 30 on
+func cleaningTestData() *MapData {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	points := make([]MapPoint, 60)
+
+	for i := range points {
+		points[i] = MapPoint{
+			Time: start.Add(time.Duration(i) * time.Second),
+			Lat:  51 + float64(i)*0.0000126,
+			Lng:  4,
+		}
+
+		if i >= 30 {
+			points[i].Lng += 0.01
+		}
+	}
+
+	points[10].Lat += 0.02
+
+	data := &MapData{Details: &MapDataDetails{Points: points}}
+	updatePointDistances(points, nil)
+	data.updateTotalsFromPoints()
+
+	return data
+}
+
+func TestMapData_CleanTrack(t *testing.T) {
+	data := cleaningTestData()
+	require.Greater(t, data.TotalDistance, 4000.0)
+
+	result := data.CleanTrack(LimitsForType(WorkoutTypeWalking), false)
+	assert.Equal(t, 1, result.Spikes)
+	assert.Equal(t, 1, result.Teleports)
+	assert.Equal(t, 2, result.Corrected())
+
+	assert.InDelta(t, 51+10*0.0000126, data.Details.Points[10].Lat, 0.000001)
+	assert.Zero(t, data.Details.Points[30].Distance)
+	assert.InDelta(t, 58*1.4, data.TotalDistance, 2)
+	assert.Len(t, data.Details.Points, 60)
+}
+
+func TestMapData_CleanTrackSmooth(t *testing.T) {
+	data := cleaningTestData()
+
+	result := data.CleanTrack(LimitsForType(WorkoutTypeRunning), true)
+	assert.Equal(t, 2, result.Corrected())
+	assert.InDelta(t, 58*1.4, data.TotalDistance, 5)
+
+	// A clean track is left alone
+	data = &MapData{Details: &MapDataDetails{Points: cleaningTestData().Details.Points[:10]}}
+	data.updateTotalsFromPoints()
+	distance := data.TotalDistance
+
+	assert.Zero(t, data.CleanTrack(LimitsForType(WorkoutTypeRunning), false).Corrected())
+	assert.InDelta(t, distance, data.TotalDistance, 0.001)
+}
+
+func TestParseTrackLimits(t *testing.T) {
+	limits, err := ParseTrackLimits(" running=12:5, default=60:9 ,")
+	require.NoError(t, err)
+	assert.Equal(t, map[WorkoutType]TrackLimits{
+		WorkoutTypeRunning: {MaxSpeed: 12, MaxAcceleration: 5},
+		"default":          {MaxSpeed: 60, MaxAcceleration: 9},
+	}, limits)
+
+	for _, config := range []string{"running", "running=12", "running=fast:5", "running=0:5", "flying=12:5"} {
+		_, err := ParseTrackLimits(config)
+		require.ErrorIs(t, err, ErrInvalidTrackLimits, config)
+	}
+}
@@ -0,0 +1,234 @@
+package model
+
+import (
+	"errors"
+	"math"
+	"time"
+
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// TrainingLoadMethod is how the training load of a workout is derived
+type TrainingLoadMethod string
+
+const (
+	TrainingLoadMethodTSS   TrainingLoadMethod = "tss"   // The training stress score, from the power
+	TrainingLoadMethodTRIMP TrainingLoadMethod = "trimp" // The training impulse (Banister), from the heart rate
+	TrainingLoadMethodMET   TrainingLoadMethod = "met"   // The duration times the metabolic equivalent of the workout
+)
+
+const (
+	// FitnessTimeConstant is the number of days the chronic training load
+	// (fitness) is averaged over
+	FitnessTimeConstant = 42
+	// FatigueTimeConstant is the number of days the acute training load
+	// (fatigue) is averaged over
+	FatigueTimeConstant = 7
+	// METLoadFactor scales an hour of MET to training load, so an hour at 10
+	// MET (running at 10 km/h) is about an hour at threshold
+	METLoadFactor = 10
+)
+
+// TrainingLoad is the training load of a user on a day, with their fitness,
+// fatigue and form
+type TrainingLoad struct {
+	Model
+	Date    datatypes.Date `gorm:"not null;uniqueIndex:idx_training_load_user_date" json:"date"`         // The day
+	Load    float64        `json:"load"`                                                                 // The training load of the workouts of the day
+	Fitness float64        `json:"fitness"`                                                              // The chronic training load (CTL) at the end of the day
+	Fatigue float64        `json:"fatigue"`                                                              // The acute training load (ATL) at the end of the day
+	Form    float64        `json:"form"`                                                                 // The training stress balance (TSB): fitness minus fatigue at the start of the day
+	UserID  uint64         `gorm:"not null;index;uniqueIndex:idx_training_load_user_date" json:"userID"` // The ID of the user
+}
+
+// TrainingLoad returns the training load of the workout, and how it is
+// derived: the training stress score when it has power, the training impulse
+// when it has a heart rate, or else the duration times the metabolic
+// equivalent
+func (w *Workout) TrainingLoad() (float64, TrainingLoadMethod) {
+	if w.Data == nil {
+		return 0, ""
+	}
+
+	if w.Data.TrainingStressScore > 0 {
+		return w.Data.TrainingStressScore, TrainingLoadMethodTSS
+	}
+
+	if w.Data.TRIMP > 0 {
+		return w.Data.TRIMP, TrainingLoadMethodTRIMP
+	}
+
+	d := w.Data.TotalDuration - w.Data.PauseDuration
+	if d <= 0 {
+		d = w.Data.TotalDuration
+	}
+
+	return d.Hours() * w.MET() * METLoadFactor, TrainingLoadMethodMET
+}
+
+// TRIMP returns the training impulse of Banister of the points, for the max
+// and resting heart rate: the minutes weighted by the fraction of the heart
+// rate reserve and its exponential
+func (d *MapDataDetails) TRIMP(maxHR, restHR float64) float64 {
+	reserve := maxHR - restHR
+	if reserve <= 0 {
+		return 0
+	}
+
+	trimp := 0.0
+
+	for i := range d.Points {
+		p := &d.Points[i]
+
+		hr, ok := p.ExtraMetrics["heart-rate"]
+		if !ok || math.IsNaN(hr) || hr <= 0 || p.Duration <= 0 || p.Duration > ZoneMaxGap {
+			continue
+		}
+
+		hrr := min(max((hr-restHR)/reserve, 0), 1)
+		trimp += p.Duration.Minutes() * hrr * 0.64 * math.Exp(1.92*hrr)
+	}
+
+	return trimp
+}
+
+// UpdateTRIMP calculates the training impulse of the workout, with the heart
+// rates of its user valid at the date of the workout
+func (w *Workout) UpdateTRIMP(db *gorm.DB) error {
+	if w.Data == nil {
+		return nil
+	}
+
+	w.Data.TRIMP = 0
+
+	if w.Data.Details == nil || !w.HasHeartRate() {
+		return nil
+	}
+
+	u, err := w.userWithMeasurements(db)
+	if err != nil {
+		return err
+	}
+
+	w.Data.TRIMP = w.Data.Details.TRIMP(u.MaxHeartRateAt(w.Date), u.RestingHeartRateAt(w.Date))
+
+	return nil
+}
+
+// invalidateTrainingLoads removes the training load of the user from the day
+// before the date on, so it is calculated again with the changed workouts.
+// The day before is included, since the day of the workout depends on the
+// timezone of the user.
+func invalidateTrainingLoads(db *gorm.DB, userID uint64, d time.Time) error {
+	return db.
+		Where("user_id = ?", userID).
+		Where("date >= ?", trainingLoadDay(d.UTC().AddDate(0, 0, -1))).
+		Delete(&TrainingLoad{}).Error
+}
+
+// trainingLoadDay returns the day of the time, as a date in UTC
+func trainingLoadDay(t time.Time) datatypes.Date {
+	return datatypes.Date(time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC))
+}
+
+// UpdateTrainingLoads calculates the training load of the user for all days
+// since the last day that was calculated, up to today
+func (u *User) UpdateTrainingLoads() error {
+	var last TrainingLoad
+
+	err := u.db.Where("user_id = ?", u.ID).Order("date DESC").First(&last).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	loc := u.Timezone()
+
+	var from time.Time
+
+	if err == nil {
+		from = time.Time(last.Date).AddDate(0, 0, 1)
+	} else {
+		var first Workout
+		if err := u.db.Where("user_id = ?", u.ID).Order("date ASC").Limit(1).Find(&first).Error; err != nil {
+			return err
+		}
+
+		if first.ID == 0 {
+			return nil
+		}
+
+		from = time.Time(trainingLoadDay(first.Date.In(loc)))
+	}
+
+	today := time.Time(trainingLoadDay(time.Now().In(loc)))
+	if from.After(today) {
+		return nil
+	}
+
+	var workouts []*Workout
+	if err := u.db.Preload("Data").
+		Where("user_id = ?", u.ID).
+		Where("date >= ?", from.AddDate(0, 0, -1)).
+		Find(&workouts).Error; err != nil {
+		return err
+	}
+
+	daily := map[time.Time]float64{}
+
+	for _, w := range workouts {
+		day := time.Time(trainingLoadDay(w.Date.In(loc)))
+		if day.Before(from) {
+			continue
+		}
+
+		load, _ := w.TrainingLoad()
+		daily[day] += load
+	}
+
+	fitness, fatigue := last.Fitness, last.Fatigue
+	loads := []TrainingLoad{}
+
+	for day := from; !day.After(today); day = day.AddDate(0, 0, 1) {
+		load := daily[day]
+		form := fitness - fatigue
+
+		fitness += (load - fitness) / FitnessTimeConstant
+		fatigue += (load - fatigue) / FatigueTimeConstant
+
+		loads = append(loads, TrainingLoad{
+			UserID:  u.ID,
+			Date:    datatypes.Date(day),
+			Load:    load,
+			Fitness: fitness,
+			Fatigue: fatigue,
+			Form:    form,
+		})
+	}
+
+	return u.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(loads, 500).Error
+}
+
+// GetTrainingLoads returns the training load of the user per day, for the
+// period of the statistics; the days that were not calculated yet are
+// calculated first
+func (u *User) GetTrainingLoads(statConfig StatConfig) ([]TrainingLoad, error) {
+	if err := u.UpdateTrainingLoads(); err != nil {
+		return nil, err
+	}
+
+	var loads []TrainingLoad
+
+	q := u.db.Where("user_id = ?", u.ID).Order("date ASC")
+
+	if statConfig.Since != "" && statConfig.Since != "forever" {
+		q = q.Where(GetColumnDateLimitExpression(u.db.Dialector.Name(), "training_loads.date"), "-"+statConfig.GetSince())
+	}
+
+	if err := q.Find(&loads).Error; err != nil {
+		return nil, err
+	}
+
+	return loads, nil
+}
@@ -0,0 +1,129 @@
+package model
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkout_TrainingLoad(t *testing.T) {
+	w := &Workout{Type: WorkoutTypeRunning, Data: &MapData{}}
+	w.Data.TotalDistance = 10000
+	w.Data.TotalDuration = time.Hour
+	w.Data.AverageSpeed = 10000 / time.Hour.Seconds()
+	w.Data.AverageSpeedNoPause = w.Data.AverageSpeed
+
+	load, method := w.TrainingLoad()
+	assert.Equal(t, TrainingLoadMethodMET, method)
+	assert.InDelta(t, w.MET()*METLoadFactor, load, 0.001)
+
+	w.Data.TRIMP = 80
+	load, method = w.TrainingLoad()
+	assert.Equal(t, TrainingLoadMethodTRIMP, method)
+	assert.InDelta(t, 80, load, 0.001)
+
+	w.Data.TrainingStressScore = 70
+	load, method = w.TrainingLoad()
+	assert.Equal(t, TrainingLoadMethodTSS, method)
+	assert.InDelta(t, 70, load, 0.001)
+}
+
+func TestMapDataDetails_TRIMP(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	d := &MapDataDetails{}
+	for i := range 60 {
+		d.Points = append(d.Points, MapPoint{
+			Time:         start.Add(time.Duration(i) * time.Minute),
+			Duration:     time.Minute,
+			ExtraMetrics: ExtraMetrics{"heart-rate": 200},
+		})
+	}
+
+	// An hour at the max heart rate
+	assert.InDelta(t, 60*0.64*math.Exp(1.92), d.TRIMP(200, 60), 0.001)
+	// Without a heart rate reserve
+	assert.Zero(t, d.TRIMP(60, 60))
+
+	for i := range d.Points {
+		d.Points[i].ExtraMetrics["heart-rate"] = 60
+	}
+
+	assert.Zero(t, d.TRIMP(200, 60))
+}
+
+func TestUser_UpdateTrainingLoads(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+	u.SetDB(db)
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	hard := duplicateTestWorkout(u, "hard", today.AddDate(0, 0, -10).Add(12*time.Hour), false, nil)
+	hard.Data.TrainingStressScore = 100
+	require.NoError(t, hard.Save(db))
+
+	easy := duplicateTestWorkout(u, "easy", today.AddDate(0, 0, -5).Add(12*time.Hour), false, nil)
+	easy.Data.TrainingStressScore = 50
+	require.NoError(t, easy.Save(db))
+
+	loads, err := u.GetTrainingLoads(StatConfig{Since: "1 month"})
+	require.NoError(t, err)
+	require.Len(t, loads, 11)
+
+	assert.InDelta(t, 100, loads[0].Load, 0.001)
+	assert.InDelta(t, 100.0/FitnessTimeConstant, loads[0].Fitness, 0.001)
+	assert.InDelta(t, 100.0/FatigueTimeConstant, loads[0].Fatigue, 0.001)
+	assert.Zero(t, loads[0].Form)
+	assert.InDelta(t, loads[0].Fitness-loads[0].Fatigue, loads[1].Form, 0.001)
+	assert.InDelta(t, 50, loads[5].Load, 0.001)
+
+	// The fitness decays without training
+	assert.Less(t, loads[10].Fitness, loads[5].Fitness)
+
+	// Deleting a workout calculates the days from the workout on again
+	require.NoError(t, easy.Delete(db))
+
+	var count int64
+	require.NoError(t, db.Model(&TrainingLoad{}).Where("user_id = ?", u.ID).Count(&count).Error)
+	assert.Equal(t, int64(4), count)
+
+	updated, err := u.GetTrainingLoads(StatConfig{Since: "1 month"})
+	require.NoError(t, err)
+	require.Len(t, updated, 11)
+
+	assert.Zero(t, updated[5].Load)
+	assert.InDelta(t, loads[0].Fitness, updated[0].Fitness, 0.001)
+	assert.Less(t, updated[10].Fitness, loads[10].Fitness)
+
+	// Moving a workout to an earlier day calculates the days from there on
+	hard.Date = hard.Date.AddDate(0, 0, -2)
+	require.NoError(t, hard.Save(db))
+
+	updated, err = u.GetTrainingLoads(StatConfig{Since: "1 month"})
+	require.NoError(t, err)
+	require.Len(t, updated, 13)
+	assert.InDelta(t, 100, updated[0].Load, 0.001)
+	assert.Zero(t, updated[2].Load)
+
+	// Changing a measurement calculates the days from the measurement on again
+	m := u.NewMeasurement(today.AddDate(0, 0, -3))
+	m.RestingHeartRate = 50
+	require.NoError(t, m.Save(db))
+
+	require.NoError(t, db.Model(&TrainingLoad{}).Where("user_id = ?", u.ID).Count(&count).Error)
+	assert.Equal(t, int64(8), count)
+
+	_, err = u.GetTrainingLoads(StatConfig{Since: "1 month"})
+	require.NoError(t, err)
+
+	require.NoError(t, m.Delete(db))
+
+	require.NoError(t, db.Model(&TrainingLoad{}).Where("user_id = ?", u.ID).Count(&count).Error)
+	assert.Equal(t, int64(8), count)
+}
@@ -9,6 +9,7 @@ import (
 	"errors"
 	"fmt"
 	"net/mail"
+	"strings"
 	"time"
 
 	gorand "github.com/cat-dealer/go-rand/v2"
@@ -65,6 +66,9 @@ type User struct {
 	Workouts     []Workout     `gorm:"constraint:OnDelete:CASCADE" json:"-"` // The user's workouts
 	Equipment    []Equipment   `gorm:"constraint:OnDelete:CASCADE" json:"-"` // The user's equipment
 	Measurements []Measurement `gorm:"constraint:OnDelete:CASCADE" json:"-"` // The user's measurements
+	Multisports  []Multisport  `gorm:"constraint:OnDelete:CASCADE" json:"-"` // The user's multisport activities
+
+	TrainingLoads []TrainingLoad `gorm:"constraint:OnDelete:CASCADE" json:"-"` // The user's training load per day
 
 	Profile Profile `gorm:"constraint:OnDelete:CASCADE" json:"profile"` // The user's profile settings
 
@@ -288,6 +292,32 @@ func (u *User) MarkWorkoutsDirty(db *gorm.DB) error {
 	return db.Model(&Workout{}).Where(&Workout{UserID: u.ID}).Update("dirty", true).Error
 }
 
+// UpdateTrackCleaning applies the GPS track cleaning settings of the profile
+// to all workouts of the user, and marks the changed workouts for refresh; it
+// returns the IDs of the changed workouts
+func (u *User) UpdateTrackCleaning(db *gorm.DB) ([]uint64, error) {
+	var ids []uint64
+
+	if err := db.Model(&Workout{}).
+		Where("user_id = ?", u.ID).
+		Where("clean_track != ? OR smooth_track != ?", u.Profile.CleanGPSTracks, u.Profile.SmoothGPSTracks).
+		Pluck("id", &ids).Error; err != nil {
+		return nil, err
+	}
+
+	if len(ids) == 0 {
+		return ids, nil
+	}
+
+	return ids, db.Model(&Workout{}).
+		Where("id IN ?", ids).
+		Updates(map[string]any{
+			"clean_track":  u.Profile.CleanGPSTracks,
+			"smooth_track": u.Profile.SmoothGPSTracks,
+			"dirty":        true,
+		}).Error
+}
+
 func (u *User) AddWorkout(db *gorm.DB, workoutType WorkoutType, notes string, filename string, content []byte) ([]*Workout, []error) {
 	if u == nil {
 		return nil, []error{ErrNoUser}
@@ -299,30 +329,45 @@ func (u *User) AddWorkout(db *gorm.DB, workoutType WorkoutType, notes string, fi
 	}
 
 	errs := []error{}
-	defaultVisibility := u.Profile.EffectiveDefaultWorkoutVisibility()
 
 	for _, w := range ws {
-		w.Visibility = defaultVisibility
-
-		if err := w.Create(db); err != nil {
+		if err := u.CreateWorkout(db, w, nil); err != nil {
 			errs = append(errs, err)
-			continue
 		}
+	}
 
-		var equipment []*Equipment
+	return ws, errs
+}
+
+// DefaultEquipmentFor returns the user's equipment that should be added to
+// new workouts of the given type
+func (u *User) DefaultEquipmentFor(t *WorkoutType) []*Equipment {
+	var equipment []*Equipment
 
-		for i, e := range u.Equipment {
-			if e.ValidFor(&w.Type) {
-				equipment = append(equipment, &u.Equipment[i])
-			}
+	for i, e := range u.Equipment {
+		if e.ValidFor(t) {
+			equipment = append(equipment, &u.Equipment[i])
 		}
+	}
 
-		if err := db.Model(&w).Association("Equipment").Replace(equipment); err != nil {
-			errs = append(errs, err)
+	return equipment
+}
+
+// EquipmentByName returns the user's equipment with the given name, ignoring
+// case, or nil if there is none
+func (u *User) EquipmentByName(name string) *Equipment {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil
+	}
+
+	for i, e := range u.Equipment {
+		if strings.EqualFold(e.Name, name) {
+			return &u.Equipment[i]
 		}
 	}
 
-	return ws, errs
+	return nil
 }
 
 func (u *User) GetAllEquipment(db *gorm.DB) ([]*Equipment, error) {
@@ -357,13 +402,10 @@ func (u *User) WeightAt(d time.Time) float64 {
 	return w
 }
 
+// FTPAt returns the FTP of the last measurement at or before the date, or 0
+// if the user never measured it
 func (u *User) FTPAt(d time.Time) float64 {
-	val := u.measurementAt("ftp", d)
-	if val == 0 {
-		return 200
-	}
-
-	return val
+	return u.measurementAt("ftp", d)
 }
 
 func (u *User) RestingHeartRateAt(d time.Time) float64 {
@@ -388,6 +430,12 @@ func (u *User) MaxHeartRateAt(d time.Time) float64 {
 	return val
 }
 
+// LTHRAt returns the lactate threshold heart rate of the user at the date, or
+// 0 when it was never measured
+func (u *User) LTHRAt(d time.Time) float64 {
+	return u.measurementAt("lthr", d)
+}
+
 func calculateMaxHeartRate(birthdate time.Time, at time.Time) float64 {
 	age := at.Year() - birthdate.Year()
 	if at.Month() < birthdate.Month() || (at.Month() == birthdate.Month() && at.Day() < birthdate.Day()) {
@@ -12,6 +12,8 @@ type (
 		Buckets      map[WorkoutType]Buckets `json:"buckets"`      // The statistics buckets
 		BucketFormat string                  `json:"bucketFormat"` // The bucket format in strftime format
 		UserID       uint64                  `json:"userID"`       // The user ID
+
+		HeartRateZones []WeeklyHeartRateZones `json:"heartRateZones,omitempty"` // The time spent in each heart rate zone per week
 	}
 
 	Buckets struct {
@@ -76,6 +78,15 @@ type (
 		Active        bool      `json:"active"`        // Whether the record exists
 	}
 
+	// ExerciseRecord captures the best efforts for a single strength exercise
+	ExerciseRecord struct {
+		Exercise  string        `json:"exercise"`  // The name of the exercise
+		Category  string        `json:"category"`  // The category of the exercise
+		OneRepMax Float64Record `json:"oneRepMax"` // The best estimated one-rep max, in kg
+		MaxWeight Float64Record `json:"maxWeight"` // The heaviest weight of a set, in kg
+		Volume    Float64Record `json:"volume"`    // The biggest volume in a single workout, in kg
+	}
+
 	// DurationRecord is a single record if the value is a time.Duration
 	DurationRecord struct {
 		Date  time.Time     `json:"date"`  // The timestamp of the record
@@ -93,8 +104,9 @@ type (
 		TotalUp             Float64Record  `json:"totalUp"`             // The record with the maximum up elevation
 		Duration            DurationRecord `json:"duration"`            // The record with the maximum duration
 		DistanceRecords     []DistanceRecord
-		BiggestClimb        *ClimbRecord `json:"biggestClimb"`
-		Active              bool         `json:"active"` // Whether there is any data in the record
+		BiggestClimb        *ClimbRecord     `json:"biggestClimb"`
+		ExerciseRecords     []ExerciseRecord `json:"exerciseRecords"` // The records per strength exercise
+		Active              bool             `json:"active"`          // Whether there is any data in the record
 	}
 )
 
@@ -16,6 +16,7 @@ type (
 		PauseDuration    time.Duration `json:"pauseDuration"`                       // The total pause duration of the workout
 		TotalRepetitions int           `json:"totalRepetitions"`                    // The number of repetitions of the workout
 		TotalWeight      float64       `json:"totalWeight"`                         // The weight of the workout
+		PoolLength       float64       `json:"poolLength"`                          // The length of the pool of a pool swim, in meters
 		Laps             []WorkoutLap  `gorm:"serializer:json" json:"laps"`         // The laps of the workout
 		ExtraMetrics     []string      `gorm:"serializer:json" json:"extraMetrics"` // Extra metrics available
 	}
@@ -65,6 +66,17 @@ type (
 		MinPower     float64 `json:"minPower"`     // The minimum power of the workout
 		MaxPower     float64 `json:"maxPower"`     // The maximum power of the workout
 
+		// Power analytics
+		NormalizedPower     float64 `json:"normalizedPower"`     // The normalized power of the workout (30 s rolling average)
+		VariabilityIndex    float64 `json:"variabilityIndex"`    // The normalized power divided by the average power
+		IntensityFactor     float64 `json:"intensityFactor"`     // The normalized power divided by the FTP of the user
+		TrainingStressScore float64 `json:"trainingStressScore"` // The training stress score (TSS) of the workout
+
+		// Running analytics
+		GradeAdjustedSpeed float64 `json:"gradeAdjustedSpeed"` // The average speed while moving, adjusted for the slope (grade-adjusted pace)
+		PaceDecoupling     float64 `json:"paceDecoupling"`     // The drop of grade-adjusted speed per heart beat from the first to the second half, in percent
+		PowerDecoupling    float64 `json:"powerDecoupling"`    // The drop of power per heart beat from the first to the second half, in percent
+
 		// Temperature stats
 		AverageTemperature float64 `json:"averageTemperature"` // The average temperature of the workout
 		MinTemperature     float64 `json:"minTemperature"`     // The minimum temperature of the workout
@@ -113,6 +125,10 @@ func (d *WorkoutData) MergeNonZero(from WorkoutData) {
 		d.TotalWeight = from.TotalWeight
 	}
 
+	if from.PoolLength != 0 {
+		d.PoolLength = from.PoolLength
+	}
+
 	if from.MinElevation != 0 {
 		d.MinElevation = from.MinElevation
 	}
@@ -0,0 +1,382 @@
+package model
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"slices"
+
+	"gorm.io/gorm"
+)
+
+// minEditedPoints is the minimal number of points a workout keeps after an edit
+const minEditedPoints = 2
+
+var (
+	ErrWorkoutHasNoFile   = errors.New("workout has no file")
+	ErrInvalidPointRange  = errors.New("invalid point range")
+	ErrTooFewPointsLeft   = errors.New("too few points would be left")
+	ErrWorkoutHasNoPoints = errors.New("workout has no points")
+	ErrInvalidSplitPoint  = errors.New("invalid split point")
+	ErrWorkoutNotEdited   = errors.New("workout has no edits")
+	ErrWorkoutIsSplit     = errors.New("workout was split, its edits can not be reverted")
+)
+
+// PointRange is an inclusive range of point indexes
+type PointRange struct {
+	Start int `json:"start"`
+	End   int `json:"end"`
+}
+
+// Len returns the number of points in the range
+func (r PointRange) Len() int {
+	return r.End - r.Start + 1
+}
+
+// normalizePointRanges sorts the ranges and merges overlapping and adjacent
+// ranges
+func normalizePointRanges(ranges []PointRange) []PointRange {
+	sorted := slices.Clone(ranges)
+	slices.SortFunc(sorted, func(a, b PointRange) int { return a.Start - b.Start })
+
+	result := []PointRange{}
+
+	for _, r := range sorted {
+		if n := len(result); n > 0 && r.Start <= result[n-1].End+1 {
+			result[n-1].End = max(result[n-1].End, r.End)
+			continue
+		}
+
+		result = append(result, r)
+	}
+
+	return result
+}
+
+// originalPointIndex returns the index in the parsed file of the point at
+// the index after removing the (normalized) ranges
+func originalPointIndex(removed []PointRange, i int) int {
+	for _, r := range removed {
+		if r.Start > i {
+			break
+		}
+
+		i += r.Len()
+	}
+
+	return i
+}
+
+// IsEdited returns whether points of the file are removed from the workout
+func (w *Workout) IsEdited() bool {
+	return w.GPX != nil && len(w.GPX.RemovedPoints) > 0
+}
+
+// RemovePoints removes the points between both indexes, inclusive
+func (w *Workout) RemovePoints(db *gorm.DB, start, end int) error {
+	if err := w.checkEditable(); err != nil {
+		return err
+	}
+
+	count := len(w.Data.Details.Points)
+	if start < 0 || end >= count || start > end {
+		return ErrInvalidPointRange
+	}
+
+	if count-(end-start+1) < minEditedPoints {
+		return ErrTooFewPointsLeft
+	}
+
+	w.removeCurrentPoints(PointRange{Start: start, End: end})
+
+	return w.updateEditedData(db)
+}
+
+// TrimPoints keeps only the points between both indexes, inclusive, and
+// removes the points before and after them
+func (w *Workout) TrimPoints(db *gorm.DB, start, end int) error {
+	if err := w.checkEditable(); err != nil {
+		return err
+	}
+
+	count := len(w.Data.Details.Points)
+	if start < 0 || end >= count || start > end {
+		return ErrInvalidPointRange
+	}
+
+	if end-start+1 < minEditedPoints {
+		return ErrTooFewPointsLeft
+	}
+
+	if end < count-1 {
+		w.removeCurrentPoints(PointRange{Start: end + 1, End: count - 1})
+	}
+
+	if start > 0 {
+		w.removeCurrentPoints(PointRange{Start: 0, End: start - 1})
+	}
+
+	return w.updateEditedData(db)
+}
+
+// SplitAt splits the workout in two: the workout keeps the points before the
+// index, and a new workout is created with the point at the index and all
+// points after it. The new workout shares the file of the workout, and it is
+// returned.
+func (w *Workout) SplitAt(db *gorm.DB, index int) (*Workout, error) {
+	if err := w.checkEditable(); err != nil {
+		return nil, err
+	}
+
+	count := len(w.Data.Details.Points)
+	if index < minEditedPoints || index > count-minEditedPoints {
+		return nil, ErrInvalidSplitPoint
+	}
+
+	second := &Workout{
+		UserID:     w.UserID,
+		User:       w.User,
+		Name:       w.Name,
+		Notes:      w.Notes,
+		Type:       w.Type,
+		CustomType: w.CustomType,
+		Visibility: w.Visibility,
+		Date:       w.Data.Details.Points[index].Time,
+		Data:       &MapData{},
+		GPX: &GPXData{
+			Filename:      w.GPX.Filename,
+			Content:       w.GPX.Content,
+			Part:          w.GPX.Part,
+			RemovedPoints: slices.Clone(w.GPX.RemovedPoints),
+			Split:         true,
+		},
+	}
+
+	if second.Date.IsZero() {
+		second.Date = w.Date.Add(w.Data.Details.Points[index].TotalDuration)
+	}
+
+	second.removeCurrentPoints(PointRange{Start: 0, End: index - 1})
+	w.removeCurrentPoints(PointRange{Start: index, End: count - 1})
+	w.GPX.Split = true
+
+	// Every part of the file needs its own checksum, so it can be stored
+	h := sha256.New()
+	h.Write(second.GPX.Content)
+	fmt.Fprintf(h, "#%d#split:%d", second.GPX.Part, originalPointIndex(second.GPX.RemovedPoints, 0))
+
+	second.GPX.Checksum = h.Sum(nil)
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		if err := w.updateEditedData(tx); err != nil {
+			return err
+		}
+
+		if err := second.Create(tx); err != nil {
+			return err
+		}
+
+		if err := tx.Model(second).Association("Equipment").Replace(w.Equipment); err != nil {
+			return err
+		}
+
+		return second.updateEditedData(tx)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return second, nil
+}
+
+// RevertEdits restores all points of the file; the edits of a split workout
+// can not be reverted, since the other part has the rest of the points
+func (w *Workout) RevertEdits(db *gorm.DB) error {
+	if !w.HasFile() {
+		return ErrWorkoutHasNoFile
+	}
+
+	if w.GPX.Split {
+		return ErrWorkoutIsSplit
+	}
+
+	if !w.IsEdited() {
+		return ErrWorkoutNotEdited
+	}
+
+	w.GPX.RemovedPoints = nil
+
+	return w.updateEditedData(db)
+}
+
+func (w *Workout) checkEditable() error {
+	if !w.HasFile() {
+		return ErrWorkoutHasNoFile
+	}
+
+	if w.Data == nil || w.Data.Details == nil || len(w.Data.Details.Points) == 0 {
+		return ErrWorkoutHasNoPoints
+	}
+
+	return nil
+}
+
+// removeCurrentPoints adds a range of the current points to the removed
+// points of the file
+func (w *Workout) removeCurrentPoints(r PointRange) {
+	removed := w.GPX.RemovedPoints
+
+	w.GPX.RemovedPoints = normalizePointRanges(append(slices.Clone(removed), PointRange{
+		Start: originalPointIndex(removed, r.Start),
+		End:   originalPointIndex(removed, r.End),
+	}))
+}
+
+// updateEditedData parses the file again, without the removed points, and
+// moves the start of the workout to its first point
+func (w *Workout) updateEditedData(db *gorm.DB) error {
+	w.Dirty = true
+
+	if err := w.UpdateData(db); err != nil {
+		return err
+	}
+
+	if w.Data.Start.IsZero() || w.Data.Start.Equal(w.Date) {
+		return nil
+	}
+
+	w.Date = w.Data.Start
+
+	return w.Save(db)
+}
+
+// removePoints removes the ranges of points, as parsed from the file, and
+// updates the totals; the distance and duration between the points before
+// and after a removed range are not counted
+func (m *MapData) removePoints(removed []PointRange) {
+	if len(removed) == 0 || m.Details == nil {
+		return
+	}
+
+	edited := m.lapsWithRemovedPoints(removed)
+	points := make([]MapPoint, 0, len(m.Details.Points))
+	r := 0
+
+	for i := range m.Details.Points {
+		for r < len(removed) && removed[r].End < i {
+			r++
+		}
+
+		if r < len(removed) && removed[r].Start <= i {
+			continue
+		}
+
+		p := m.Details.Points[i]
+
+		if len(points) == 0 || (r > 0 && removed[r-1].End == i-1) {
+			p.Distance, p.Distance2D, p.Duration = 0, 0, 0
+		}
+
+		points = append(points, p)
+	}
+
+	m.Details.Points = points
+	m.updateTotalsFromPoints()
+	m.updateEditedLaps(edited)
+}
+
+// lapsWithRemovedPoints returns for every lap whether any of its points, as
+// parsed from the file, are in the removed ranges
+func (m *MapData) lapsWithRemovedPoints(removed []PointRange) []bool {
+	edited := make([]bool, len(m.Laps))
+
+	for i := range m.Laps {
+		if m.Laps[i].Stop.IsZero() {
+			continue
+		}
+
+		start, end := m.lapRange(&m.Laps[i])
+
+		edited[i] = slices.ContainsFunc(removed, func(r PointRange) bool {
+			return r.Start <= end && r.End >= start
+		})
+	}
+
+	return edited
+}
+
+// updateEditedLaps clamps the edited laps to their remaining points and
+// calculates their totals and statistics from these points; laps without
+// remaining points are dropped
+func (m *MapData) updateEditedLaps(edited []bool) {
+	points := m.Details.Points
+	laps := make([]WorkoutLap, 0, len(m.Laps))
+
+	for i, l := range m.Laps {
+		if !edited[i] {
+			laps = append(laps, l)
+			continue
+		}
+
+		start, end := m.lapRange(&l)
+
+		stats, ok := m.Details.StatsForRange(start, end)
+		if !ok {
+			continue
+		}
+
+		l.Start, l.Stop = points[start].Time, points[end].Time
+		l.TotalDistance = stats.Distance
+		l.TotalDuration = stats.Duration
+		l.PauseDuration = stats.PauseDuration
+		l.WorkoutStats = stats.WorkoutStats
+
+		laps = append(laps, l)
+	}
+
+	m.Laps = laps
+}
+
+// updateTotalsFromPoints sets the totals, start, stop and center of the map
+// data from its points
+func (m *MapData) updateTotalsFromPoints() {
+	points := m.Details.Points
+
+	m.TotalDistance, m.TotalDistance2D, m.TotalDuration = 0, 0, 0
+
+	var lat, lng float64
+
+	located := 0
+
+	for i := range points {
+		m.TotalDistance += points[i].Distance
+		m.TotalDistance2D += points[i].Distance2D
+		m.TotalDuration += points[i].Duration
+
+		points[i].TotalDistance = m.TotalDistance
+		points[i].TotalDistance2D = m.TotalDistance2D
+		points[i].TotalDuration = m.TotalDuration
+
+		if points[i].Lat != 0 || points[i].Lng != 0 {
+			lat += points[i].Lat
+			lng += points[i].Lng
+			located++
+		}
+	}
+
+	m.PauseDuration = 0
+
+	if stats, ok := m.Details.StatsForRange(0, len(points)-1); ok {
+		m.PauseDuration = stats.PauseDuration
+	}
+
+	if len(points) > 0 {
+		m.Start = points[0].Time
+		m.Stop = points[len(points)-1].Time
+	}
+
+	if located > 0 {
+		m.Center = MapCenter{Lat: lat / float64(located), Lng: lng / float64(located)}
+		m.Center.updateTimezone()
+	}
+}
@@ -0,0 +1,145 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm"
+)
+
+func TestNormalizePointRanges(t *testing.T) {
+	ranges := normalizePointRanges([]PointRange{{10, 12}, {0, 2}, {3, 4}, {11, 15}})
+
+	assert.Equal(t, []PointRange{{0, 4}, {10, 15}}, ranges)
+	assert.Equal(t, 5, originalPointIndex(ranges, 0))
+	assert.Equal(t, 9, originalPointIndex(ranges, 4))
+	assert.Equal(t, 16, originalPointIndex(ranges, 5))
+}
+
+func TestMapData_RemovePoints_Laps(t *testing.T) {
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+	data := func() *MapData {
+		points := make([]MapPoint, 12)
+		for i := range points {
+			points[i] = MapPoint{Time: start.Add(time.Duration(i) * time.Second), Distance: 10, Duration: time.Second}
+		}
+
+		return &MapData{
+			Details: &MapDataDetails{Points: points},
+			WorkoutData: WorkoutData{Laps: []WorkoutLap{
+				{Start: start, Stop: start.Add(3 * time.Second), TotalDistance: 40},
+				{Start: start.Add(4 * time.Second), Stop: start.Add(7 * time.Second), TotalDistance: 40},
+				{Start: start.Add(8 * time.Second), Stop: start.Add(11 * time.Second), TotalDistance: 40},
+			}},
+		}
+	}
+
+	// Trimming halfway through the second lap drops the third lap
+	m := data()
+	m.removePoints([]PointRange{{6, 11}})
+
+	require.Len(t, m.Laps, 2)
+	assert.Equal(t, start.Add(3*time.Second), m.Laps[0].Stop)
+	assert.InDelta(t, 40, m.Laps[0].TotalDistance, 0.001)
+	assert.Equal(t, start.Add(4*time.Second), m.Laps[1].Start)
+	assert.Equal(t, start.Add(5*time.Second), m.Laps[1].Stop)
+	assert.InDelta(t, 20, m.Laps[1].TotalDistance, 0.001)
+	assert.Equal(t, 2*time.Second, m.Laps[1].TotalDuration)
+
+	// A lap in a removed range is dropped, the lap after it starts at its
+	// first remaining point
+	m = data()
+	m.removePoints([]PointRange{{4, 8}})
+
+	require.Len(t, m.Laps, 2)
+	assert.Equal(t, start.Add(9*time.Second), m.Laps[1].Start)
+	assert.InDelta(t, 20, m.Laps[1].TotalDistance, 0.001)
+}
+
+func createEditTestWorkout(t *testing.T) (*gorm.DB, *Workout) {
+	t.Helper()
+
+	populateGPXFS()
+
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+
+	f1, err := gpxFS.ReadFile("sample1.gpx")
+	require.NoError(t, err)
+
+	ws, err := NewWorkout(u, WorkoutTypeAutoDetect, "", "file.gpx", f1)
+	require.NoError(t, err)
+	require.Len(t, ws, 1)
+
+	require.NoError(t, u.CreateWorkout(db, ws[0], nil))
+	require.NoError(t, ws[0].UpdateData(db))
+
+	return db, ws[0]
+}
+
+func TestWorkout_TrimAndRevert(t *testing.T) {
+	db, w := createEditTestWorkout(t)
+
+	original := len(w.Data.Details.Points)
+	originalDistance := w.Data.TotalDistance
+	secondPoint := w.Data.Details.Points[2]
+	require.Greater(t, original, 10)
+
+	require.NoError(t, w.TrimPoints(db, 2, original-3))
+
+	stored, err := GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Data.Details.Points, original-4)
+	assert.Equal(t, secondPoint.Time, stored.Date)
+	assert.Equal(t, []PointRange{{0, 1}, {original - 2, original - 1}}, stored.GPX.RemovedPoints)
+	assert.Less(t, stored.Data.TotalDistance, originalDistance)
+	assert.Zero(t, stored.Data.Details.Points[0].TotalDistance)
+	assert.InDelta(t, stored.Data.TotalDistance, stored.Data.Details.Points[original-5].TotalDistance, 0.01)
+
+	// Indexes of later edits are relative to the remaining points
+	require.NoError(t, stored.RemovePoints(db, 1, 2))
+	assert.Equal(t, []PointRange{{0, 1}, {3, 4}, {original - 2, original - 1}}, stored.GPX.RemovedPoints)
+	assert.Len(t, stored.Data.Details.Points, original-6)
+
+	require.ErrorIs(t, stored.RemovePoints(db, 0, original-7), ErrTooFewPointsLeft)
+
+	require.NoError(t, stored.RevertEdits(db))
+
+	stored, err = GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Data.Details.Points, original)
+	assert.Empty(t, stored.GPX.RemovedPoints)
+	assert.InDelta(t, originalDistance, stored.Data.TotalDistance, 0.01)
+}
+
+func TestWorkout_SplitAt(t *testing.T) {
+	db, w := createEditTestWorkout(t)
+
+	original := len(w.Data.Details.Points)
+	index := original / 2
+	splitTime := w.Data.Details.Points[index].Time
+
+	require.ErrorIs(t, func() error { _, err := w.SplitAt(db, 1); return err }(), ErrInvalidSplitPoint)
+
+	second, err := w.SplitAt(db, index)
+	require.NoError(t, err)
+
+	first, err := GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	assert.Len(t, first.Data.Details.Points, index)
+
+	stored, err := GetWorkoutDetails(db, second.ID)
+	require.NoError(t, err)
+	assert.Len(t, stored.Data.Details.Points, original-index)
+	assert.Equal(t, splitTime, stored.Date)
+	assert.Equal(t, w.GPX.Content, stored.GPX.Content)
+	assert.NotEqual(t, w.GPX.Checksum, stored.GPX.Checksum)
+
+	// Both parts share the file, so their edits can not be reverted
+	require.ErrorIs(t, first.RevertEdits(db), ErrWorkoutIsSplit)
+	require.ErrorIs(t, stored.RevertEdits(db), ErrWorkoutIsSplit)
+}
@@ -0,0 +1,79 @@
+package model
+
+import (
+	"gorm.io/gorm"
+)
+
+// WorkoutOverrides are the values the user chose for a parsed workout before
+// it is created; empty values keep the parsed or default value
+type WorkoutOverrides struct {
+	Name       string             // The name of the workout
+	Type       WorkoutType        // The type of the workout
+	Visibility *WorkoutVisibility // The visibility of the workout
+	Equipment  []*Equipment       // The equipment of the workout; nil keeps the default equipment for the type
+}
+
+// CreateWorkout creates a parsed workout for the user, with the default
+// visibility and equipment unless they are overridden; the track is cleaned
+// according to the user's profile
+func (u *User) CreateWorkout(db *gorm.DB, w *Workout, o *WorkoutOverrides) error {
+	if u == nil {
+		return ErrNoUser
+	}
+
+	w.Visibility = u.Profile.EffectiveDefaultWorkoutVisibility()
+	w.CleanTrack = u.Profile.CleanGPSTracks
+	w.SmoothTrack = u.Profile.SmoothGPSTracks
+
+	var equipment []*Equipment
+
+	if o != nil {
+		if o.Name != "" {
+			w.Name = o.Name
+		}
+
+		if o.Type != "" {
+			w.Type = o.Type
+		}
+
+		if o.Visibility != nil {
+			w.Visibility = *o.Visibility
+		}
+
+		equipment = o.Equipment
+	}
+
+	if equipment == nil {
+		equipment = u.DefaultEquipmentFor(&w.Type)
+	}
+
+	if err := w.Create(db); err != nil {
+		return err
+	}
+
+	return db.Model(w).Association("Equipment").Replace(equipment)
+}
+
+// IsDuplicate returns whether creating the workout would fail with
+// ErrWorkoutAlreadyExists: the user already has a workout with the same
+// start, or the same file was imported before
+func (w *Workout) IsDuplicate(db *gorm.DB) (bool, error) {
+	var count int64
+
+	if err := db.Model(&Workout{}).
+		Where("user_id = ?", w.UserID).
+		Where("date = ?", w.Date).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	if count > 0 || w.GPX == nil {
+		return count > 0, nil
+	}
+
+	if err := db.Model(&GPXData{}).Where("checksum = ?", w.GPX.Checksum).Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
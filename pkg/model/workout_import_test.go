@@ -0,0 +1,51 @@
+package model
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestUser_CreateWorkoutWithOverrides(t *testing.T) {
+	populateGPXFS()
+
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+
+	f1, err := gpxFS.ReadFile("sample1.gpx")
+	require.NoError(t, err)
+
+	ws, err := NewWorkout(u, WorkoutTypeAutoDetect, "", "file.gpx", f1)
+	require.NoError(t, err)
+	require.Len(t, ws, 1)
+
+	duplicate, err := ws[0].IsDuplicate(db)
+	require.NoError(t, err)
+	assert.False(t, duplicate)
+
+	visibility := WorkoutVisibilityPublic
+
+	require.NoError(t, u.CreateWorkout(db, ws[0], &WorkoutOverrides{
+		Name:       "Morning walk",
+		Type:       WorkoutTypeWalking,
+		Visibility: &visibility,
+	}))
+
+	stored, err := GetWorkout(db, ws[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, "Morning walk", stored.Name)
+	assert.Equal(t, WorkoutTypeWalking, stored.Type)
+	assert.Equal(t, WorkoutVisibilityPublic, stored.Visibility)
+
+	// Parsing the same file again collides with the stored workout
+	ws, err = NewWorkout(u, WorkoutTypeAutoDetect, "", "file.gpx", f1)
+	require.NoError(t, err)
+
+	duplicate, err = ws[0].IsDuplicate(db)
+	require.NoError(t, err)
+	assert.True(t, duplicate)
+	require.ErrorIs(t, u.CreateWorkout(db, ws[0], nil), ErrWorkoutAlreadyExists)
+}
@@ -0,0 +1,157 @@
+package model
+
+import (
+	"errors"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+var ErrInvalidTimezone = errors.New("invalid timezone")
+
+// TimeShift is how the timestamps of workouts are repaired: they are shifted
+// by the offset, or, when the zone is set, re-interpreted as the local time
+// of the zone that was wrongly labelled as UTC
+type TimeShift struct {
+	Offset time.Duration
+	Zone   *time.Location
+}
+
+// NewTimeShift returns the time shift for the offset, or for the zone when it
+// is not empty
+func NewTimeShift(offset time.Duration, zone string) (TimeShift, error) {
+	if zone == "" {
+		return TimeShift{Offset: offset}, nil
+	}
+
+	loc, err := time.LoadLocation(zone)
+	if err != nil {
+		return TimeShift{}, ErrInvalidTimezone
+	}
+
+	return TimeShift{Zone: loc}, nil
+}
+
+// OffsetFor returns the offset to shift the timestamps of the workout by
+func (s TimeShift) OffsetFor(w *Workout) time.Duration {
+	if s.Zone == nil {
+		return s.Offset
+	}
+
+	u := w.Date.UTC()
+	local := time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), u.Nanosecond(), s.Zone)
+
+	return local.Sub(u)
+}
+
+// ShiftTime shifts all timestamps of the workout, its points and laps by the
+// time shift. The total offset is stored with the workout, so it is applied
+// again when the file is parsed again.
+func (w *Workout) ShiftTime(db *gorm.DB, s TimeShift) error {
+	offset := s.OffsetFor(w)
+	if offset == 0 {
+		return nil
+	}
+
+	return db.Transaction(func(tx *gorm.DB) error {
+		w.Date = w.Date.Add(offset)
+		w.TimeOffset += offset
+
+		if w.Data != nil {
+			w.Data.shiftTime(offset)
+
+			if w.Data.Details != nil {
+				for i := range w.Data.Details.Streams {
+					w.Data.Details.Streams[i].shiftTime(offset)
+				}
+			}
+		}
+
+		if !w.HasFile() {
+			return w.Save(tx)
+		}
+
+		w.Dirty = true
+
+		return w.UpdateData(tx)
+	})
+}
+
+// ShiftWorkoutsTime shifts the timestamps of all workouts by the time shift.
+// The workouts are shifted in the direction of the shift first, so they do
+// not collide with the workouts that are not shifted yet. It returns the
+// error of every workout that could not be shifted.
+func ShiftWorkoutsTime(db *gorm.DB, workouts []*Workout, s TimeShift) map[uint64]error {
+	later := len(workouts) > 0 && s.OffsetFor(workouts[0]) > 0
+	workouts = slices.Clone(workouts)
+
+	sort.SliceStable(workouts, func(i, j int) bool {
+		if later {
+			return workouts[i].Date.After(workouts[j].Date)
+		}
+
+		return workouts[i].Date.Before(workouts[j].Date)
+	})
+
+	errs := map[uint64]error{}
+
+	for _, w := range workouts {
+		if err := w.ShiftTime(db, s); err != nil {
+			errs[w.ID] = err
+		}
+	}
+
+	return errs
+}
+
+// SetTimezone overrides the timezone derived from the location of the
+// workout; an empty zone restores the derived timezone
+func (w *Workout) SetTimezone(db *gorm.DB, zone string) error {
+	if zone != "" {
+		if _, err := time.LoadLocation(zone); err != nil {
+			return ErrInvalidTimezone
+		}
+	}
+
+	w.TimezoneOverride = zone
+
+	if w.Data != nil {
+		w.Data.Center.TZ = zone
+
+		if zone == "" && !w.Data.Center.IsZero() {
+			w.Data.Center.updateTimezone()
+		}
+	}
+
+	return w.Save(db)
+}
+
+// shiftTime shifts the start, stop, points and laps of the map data by the
+// offset
+func (m *MapData) shiftTime(offset time.Duration) {
+	m.Start = shiftNonZero(m.Start, offset)
+	m.Stop = shiftNonZero(m.Stop, offset)
+
+	for i := range m.Laps {
+		m.Laps[i].Start = shiftNonZero(m.Laps[i].Start, offset)
+		m.Laps[i].Stop = shiftNonZero(m.Laps[i].Stop, offset)
+	}
+
+	if m.Details == nil {
+		return
+	}
+
+	for i := range m.Details.Points {
+		m.Details.Points[i].Time = shiftNonZero(m.Details.Points[i].Time, offset)
+	}
+}
+
+func shiftNonZero(t time.Time, offset time.Duration) time.Time {
+	if t.IsZero() {
+		return t
+	}
+
+	return t.Add(offset)
+}
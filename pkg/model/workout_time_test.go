@@ -0,0 +1,107 @@
+package model
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewTimeShift(t *testing.T) {
+	s, err := NewTimeShift(time.Hour, "")
+	require.NoError(t, err)
+	assert.Equal(t, time.Hour, s.OffsetFor(&Workout{}))
+
+	_, err = NewTimeShift(0, "Nowhere/Special")
+	require.ErrorIs(t, err, ErrInvalidTimezone)
+
+	s, err = NewTimeShift(time.Hour, "Europe/Brussels")
+	require.NoError(t, err)
+
+	// Local time labelled as UTC is 2 hours late in summer, 1 hour in winter
+	assert.Equal(t, -2*time.Hour, s.OffsetFor(&Workout{Date: time.Date(2024, 7, 1, 10, 0, 0, 0, time.UTC)}))
+	assert.Equal(t, -time.Hour, s.OffsetFor(&Workout{Date: time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC)}))
+}
+
+func TestWorkout_ShiftTime(t *testing.T) {
+	db, w := createEditTestWorkout(t)
+
+	date := w.Date
+	first := w.Data.Details.Points[0].Time
+	start := w.Data.Start
+
+	recording := sensorRecording(w.Data.Details.Points, 0, ExtraMetrics{"heart-rate": 100})
+	_, err := w.MergeSensorStreams(db, "strap.fit", []*Workout{recording}, []string{"heart-rate"}, 0)
+	require.NoError(t, err)
+
+	require.NoError(t, w.ShiftTime(db, TimeShift{Offset: 3 * time.Hour}))
+
+	assert.Equal(t, 3*time.Hour, w.TimeOffset)
+	assert.False(t, w.Dirty)
+	assert.True(t, w.Date.Equal(date.Add(3*time.Hour)))
+	assert.True(t, w.Data.Start.Equal(start.Add(3*time.Hour)))
+	assert.True(t, w.Data.Details.Points[0].Time.Equal(first.Add(3*time.Hour)))
+	// The merged streams are shifted with the points
+	assert.InDelta(t, 100, w.Data.AverageHeartRate, 0.001)
+
+	// The offset is applied again when the file is parsed again
+	w, err = GetWorkoutDetails(db, w.ID)
+	require.NoError(t, err)
+	require.NoError(t, w.UpdateData(db))
+
+	assert.True(t, w.Date.Equal(date.Add(3*time.Hour)))
+	assert.True(t, w.Data.Details.Points[0].Time.Equal(first.Add(3*time.Hour)))
+	assert.InDelta(t, 100, w.Data.AverageHeartRate, 0.001)
+
+	require.NoError(t, w.ShiftTime(db, TimeShift{Offset: -3 * time.Hour}))
+	assert.Zero(t, w.TimeOffset)
+	assert.True(t, w.Data.Details.Points[0].Time.Equal(first))
+}
+
+func TestShiftWorkoutsTime(t *testing.T) {
+	db := createMemoryDB(t)
+
+	u := defaultUser()
+	require.NoError(t, u.Create(db))
+
+	start := time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC)
+
+	var workouts []*Workout
+
+	// Workouts exactly an hour apart collide while they are being shifted,
+	// unless the latest is shifted first
+	for i := range 3 {
+		w := duplicateTestWorkout(u, "shift", start.Add(time.Duration(i)*time.Hour), false, nil)
+		require.NoError(t, w.Save(db))
+
+		workouts = append(workouts, w)
+	}
+
+	errs := ShiftWorkoutsTime(db, workouts, TimeShift{Offset: time.Hour})
+	assert.Empty(t, errs)
+
+	for i, w := range workouts {
+		assert.True(t, w.Date.Equal(start.Add(time.Duration(i+1)*time.Hour)))
+	}
+
+	// Shifting onto another workout fails
+	errs = ShiftWorkoutsTime(db, workouts[2:], TimeShift{Offset: -time.Hour})
+	require.ErrorIs(t, errs[workouts[2].ID], ErrWorkoutAlreadyExists)
+}
+
+func TestWorkout_SetTimezone(t *testing.T) {
+	db, w := createEditTestWorkout(t)
+
+	derived := w.Timezone()
+
+	require.ErrorIs(t, w.SetTimezone(db, "Nowhere/Special"), ErrInvalidTimezone)
+	require.NoError(t, w.SetTimezone(db, "Asia/Tokyo"))
+	assert.Equal(t, "Asia/Tokyo", w.Timezone())
+
+	require.NoError(t, w.UpdateData(db))
+	assert.Equal(t, "Asia/Tokyo", w.Data.Center.TZ)
+
+	require.NoError(t, w.SetTimezone(db, ""))
+	assert.Equal(t, derived, w.Timezone())
+}
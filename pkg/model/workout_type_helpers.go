@@ -132,6 +132,16 @@ func (wt WorkoutType) IsLocation() bool {
 	return workoutTypeConfigs[wt].Location
 }
 
+// IsOnFoot returns whether the workout type is running, walking or hiking
+func (wt WorkoutType) IsOnFoot() bool {
+	switch wt { //nolint:exhaustive
+	case WorkoutTypeRunning, WorkoutTypeWalking, WorkoutTypeHiking:
+		return true
+	default:
+		return false
+	}
+}
+
 func AsWorkoutType(s string) WorkoutType {
 	return WorkoutType(s)
 }
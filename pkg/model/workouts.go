@@ -62,21 +62,30 @@ func ScopeVisibleWorkouts(query *gorm.DB, ownerID uint64, viewerID uint64, viewe
 
 type Workout struct {
 	Model
-	Date                time.Time            `gorm:"not null;uniqueIndex:idx_start_user" json:"date"`                                    // The timestamp the workout was recorded
-	Visibility          WorkoutVisibility    `json:"visibility"`                                                                         // The visibility of the workout (private, followers, public)
-	User                *User                `gorm:"foreignKey:UserID" json:"user"`                                                      // The user who owns the workout
-	Data                *MapData             `gorm:"foreignKey:WorkoutID;constraint:OnDelete:CASCADE" json:"data,omitempty"`             // The map data associated with the workout
-	GPX                 *GPXData             `gorm:"foreignKey:WorkoutID;constraint:OnDelete:CASCADE" json:"gpx,omitempty"`              // The file data associated with the workout
-	Name                string               `gorm:"not null" json:"name"`                                                               // The name of the workout
-	Notes               string               `json:"notes"`                                                                              // The notes associated with the workout, in markdown
-	Type                WorkoutType          `json:"type"`                                                                               // The type of the workout
-	CustomType          string               `json:"custom_type"`                                                                        // The type of the workout, custom
-	Equipment           []Equipment          `json:"equipment,omitempty" gorm:"constraint:OnDelete:CASCADE;many2many:workout_equipment"` // Which equipment is used for this workout
-	RouteSegmentMatches []*RouteSegmentMatch `gorm:"constraint:OnDelete:CASCADE" json:"routeSegmentMatches,omitempty"`                   // Which route segments match
-	Attachments         []WorkoutAttachment  `gorm:"constraint:OnDelete:CASCADE" json:"attachments,omitempty"`
-	UserID              uint64               `gorm:"not null;index;uniqueIndex:idx_start_user" json:"userID"` // The ID of the user who owns the workout
-	Locked              bool                 `json:"locked"`                                                  // Whether the workout's main attributes should be auto-updated
-	Dirty               bool                 `json:"dirty"`                                                   // Whether the workout has been modified and the details should be re-rendered
+	Date                time.Time             `gorm:"not null;uniqueIndex:idx_start_user" json:"date"`                                    // The timestamp the workout was recorded
+	Visibility          WorkoutVisibility     `json:"visibility"`                                                                         // The visibility of the workout (private, followers, public)
+	User                *User                 `gorm:"foreignKey:UserID" json:"user"`                                                      // The user who owns the workout
+	Data                *MapData              `gorm:"foreignKey:WorkoutID;constraint:OnDelete:CASCADE" json:"data,omitempty"`             // The map data associated with the workout
+	GPX                 *GPXData              `gorm:"foreignKey:WorkoutID;constraint:OnDelete:CASCADE" json:"gpx,omitempty"`              // The file data associated with the workout
+	Name                string                `gorm:"not null" json:"name"`                                                               // The name of the workout
+	Notes               string                `json:"notes"`                                                                              // The notes associated with the workout, in markdown
+	Type                WorkoutType           `json:"type"`                                                                               // The type of the workout
+	CustomType          string                `json:"custom_type"`                                                                        // The type of the workout, custom
+	Equipment           []Equipment           `json:"equipment,omitempty" gorm:"constraint:OnDelete:CASCADE;many2many:workout_equipment"` // Which equipment is used for this workout
+	RouteSegmentMatches []*RouteSegmentMatch  `gorm:"constraint:OnDelete:CASCADE" json:"routeSegmentMatches,omitempty"`                   // Which route segments match
+	Attachments         []WorkoutAttachment   `gorm:"constraint:OnDelete:CASCADE" json:"attachments,omitempty"`
+	EffortRecords       []WorkoutEffortRecord `gorm:"constraint:OnDelete:CASCADE" json:"-"`                    // The best efforts by duration
+	Multisport          *Multisport           `gorm:"foreignKey:MultisportID" json:"-"`                        // The multisport activity this workout is a leg of
+	MultisportID        *uint64               `gorm:"index" json:"multisportID,omitempty"`                     // The ID of the multisport activity
+	UserID              uint64                `gorm:"not null;index;uniqueIndex:idx_start_user" json:"userID"` // The ID of the user who owns the workout
+	Locked              bool                  `json:"locked"`                                                  // Whether the workout's main attributes should be auto-updated
+	Commute             bool                  `json:"commute"`                                                 // Whether the workout was a commute
+	Dirty               bool                  `json:"dirty"`                                                   // Whether the workout has been modified and the details should be re-rendered
+	CleanTrack          bool                  `json:"cleanTrack"`                                              // Whether GPS errors of the track are corrected
+	SmoothTrack         bool                  `json:"smoothTrack"`                                             // Whether the corrected track is smoothed as well
+	ElevationCorrection ElevationCorrection   `json:"elevationCorrection"`                                     // How the elevation is corrected with the elevation model
+	TimeOffset          time.Duration         `json:"timeOffset"`                                              // How much the timestamps of the file are shifted, e.g. to correct the clock of the device
+	TimezoneOverride    string                `json:"timezoneOverride"`                                        // The timezone of the workout, instead of the timezone of its location
 }
 
 type GPXData struct {
@@ -84,7 +93,11 @@ type GPXData struct {
 	Filename  string `json:"filename"`                              // The filename of the file
 	Content   []byte `gorm:"type:bytes" json:"content"`             // The file content
 	Checksum  []byte `gorm:"not null;uniqueIndex" json:"checksum"`  // The checksum of the content
+	Part      int    `json:"part"`                                  // Which of the workouts in the file this is, for files with multiple workouts
 	WorkoutID uint64 `gorm:"not null;uniqueIndex" json:"workoutID"` // The ID of the workout
+
+	RemovedPoints []PointRange `gorm:"serializer:json" json:"removedPoints,omitempty"` // The ranges of points of the file that were removed from the workout
+	Split         bool         `json:"split,omitempty"`                                // Whether the file is shared with the other part of a split workout
 }
 
 func (w *Workout) HasCustomType() bool {
@@ -333,6 +346,10 @@ func (w *Workout) City() string {
 }
 
 func (w *Workout) Timezone() string {
+	if w.TimezoneOverride != "" {
+		return w.TimezoneOverride
+	}
+
 	if w.Data == nil {
 		return ""
 	}
@@ -388,6 +405,12 @@ func NewWorkout(u *User, workoutType WorkoutType, notes string, filename string,
 		return nil, nil
 	}
 
+	return PrepareParsedWorkouts(u, workoutType, notes, filename, parsed), nil
+}
+
+// PrepareParsedWorkouts assigns parsed workouts to the user and sets the
+// workout type, auto-detecting it when requested.
+func PrepareParsedWorkouts(u *User, workoutType WorkoutType, notes string, filename string, parsed []*Workout) []*Workout {
 	workouts := make([]*Workout, 0, len(parsed))
 
 	for _, parsedWorkout := range parsed {
@@ -405,7 +428,8 @@ func NewWorkout(u *User, workoutType WorkoutType, notes string, filename string,
 			w.Data = &MapData{}
 		}
 
-		if workoutType == WorkoutTypeAutoDetect {
+		// The legs of a multisport activity always get the type of their sport
+		if workoutType == WorkoutTypeAutoDetect || w.Multisport != nil {
 			w.Type = autoDetectWorkoutType(w.Data, w.Data.WorkoutData.Name)
 		} else {
 			w.Type = workoutType
@@ -419,7 +443,7 @@ func NewWorkout(u *User, workoutType WorkoutType, notes string, filename string,
 		workouts = append(workouts, w)
 	}
 
-	return workouts, nil
+	return workouts
 }
 
 // SetContent stores the raw workout file along with its checksum.
@@ -438,6 +462,21 @@ func (w *Workout) SetContent(filename string, content []byte) {
 	}
 }
 
+// SetPart marks the workout as one of multiple workouts stored in the same
+// file; the part is included in the checksum, so every part can be stored
+func (w *Workout) SetPart(part int) {
+	if w.GPX == nil || part <= 0 {
+		return
+	}
+
+	h := sha256.New()
+	h.Write(w.GPX.Content)
+	fmt.Fprintf(h, "#%d", part)
+
+	w.GPX.Part = part
+	w.GPX.Checksum = h.Sum(nil)
+}
+
 func workoutTypeFromData(gpxType string) (WorkoutType, bool) {
 	switch strings.ToLower(gpxType) {
 	case "running", "run":
@@ -466,6 +505,8 @@ func workoutTypeFromData(gpxType string) (WorkoutType, bool) {
 		return WorkoutTypePushups, true
 	case "rowing":
 		return WorkoutTypeRowing, true
+	case "weight-lifting", "strength_training", "strength-training":
+		return WorkoutTypeWeightLifting, true
 	default:
 		return WorkoutTypeAutoDetect, false
 	}
@@ -533,7 +574,11 @@ func GetMapData(db *gorm.DB, id uint64) (*MapData, error) {
 
 	if err := db.Preload("Climbs", func(tx *gorm.DB) *gorm.DB {
 		return tx.Order("sort_order ASC")
-	}).Preload("Details").Preload("Details.Points", func(tx *gorm.DB) *gorm.DB {
+	}).Preload("Lengths", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("sort_order ASC")
+	}).Preload("Sets", func(tx *gorm.DB) *gorm.DB {
+		return tx.Order("sort_order ASC")
+	}).Preload("Sets.Exercise").Preload("Details").Preload("Details.Points", func(tx *gorm.DB) *gorm.DB {
 		return tx.Order("sort_order ASC")
 	}).First(&md, id).Error; err != nil {
 		return nil, err
@@ -551,6 +596,13 @@ func GetWorkout(db *gorm.DB, id uint64) (*Workout, error) {
 		Preload("Data.Climbs", func(tx *gorm.DB) *gorm.DB {
 			return tx.Order("sort_order ASC")
 		}).
+		Preload("Data.Lengths", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("sort_order ASC")
+		}).
+		Preload("Data.Sets", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("sort_order ASC")
+		}).
+		Preload("Data.Sets.Exercise").
 		Preload("Data.Details").
 		Preload("Data.Details.Points", func(tx *gorm.DB) *gorm.DB {
 			return tx.Order("sort_order ASC")
@@ -570,7 +622,21 @@ func GetWorkout(db *gorm.DB, id uint64) (*Workout, error) {
 }
 
 func (w *Workout) Delete(db *gorm.DB) error {
-	return db.Select(clause.Associations).Delete(w).Error
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Select(clause.Associations).Delete(w).Error; err != nil {
+			return err
+		}
+
+		if err := invalidateTrainingLoads(tx, w.UserID, w.Date); err != nil {
+			return err
+		}
+
+		if w.MultisportID == nil {
+			return nil
+		}
+
+		return deleteEmptyMultisport(tx, *w.MultisportID)
+	})
 }
 
 func (w *Workout) Create(db *gorm.DB) error {
@@ -587,8 +653,19 @@ func (w *Workout) create(db *gorm.DB) error {
 		return ErrInvalidData
 	}
 
-	return db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Omit("Data", "GPX", "Equipment", "RouteSegmentMatches").Create(w).Error; err != nil {
+	var createdMultisport bool
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		var err error
+		if createdMultisport, err = w.linkMultisport(tx); err != nil {
+			return err
+		}
+
+		if err := tx.Omit("Data", "GPX", "Equipment", "RouteSegmentMatches", "Multisport").Create(w).Error; err != nil {
+			return err
+		}
+
+		if err := invalidateTrainingLoads(tx, w.UserID, w.Date); err != nil {
 			return err
 		}
 
@@ -613,6 +690,13 @@ func (w *Workout) create(db *gorm.DB) error {
 
 		return nil
 	})
+	if err != nil && createdMultisport {
+		// The multisport activity was rolled back as well
+		w.Multisport.ID = 0
+		w.MultisportID = nil
+	}
+
+	return err
 }
 
 func (w *Workout) Save(db *gorm.DB) error {
@@ -630,16 +714,31 @@ func (w *Workout) save(db *gorm.DB) error {
 	}
 
 	return db.Transaction(func(tx *gorm.DB) error {
+		changed := w.Date
+
 		if w.ID == 0 {
-			if err := tx.Omit("Data", "GPX", "Equipment", "RouteSegmentMatches").Create(w).Error; err != nil {
+			if err := tx.Omit("Data", "GPX", "Equipment", "RouteSegmentMatches", "Multisport").Create(w).Error; err != nil {
 				return err
 			}
 		} else {
-			if err := tx.Omit("Data", "GPX", "Equipment", "RouteSegmentMatches").Save(w).Error; err != nil {
+			var previous Workout
+			if err := tx.Select("date").Where("id = ?", w.ID).Limit(1).Find(&previous).Error; err != nil {
+				return err
+			}
+
+			if !previous.Date.IsZero() && previous.Date.Before(changed) {
+				changed = previous.Date
+			}
+
+			if err := tx.Omit("Data", "GPX", "Equipment", "RouteSegmentMatches", "Multisport").Save(w).Error; err != nil {
 				return err
 			}
 		}
 
+		if err := invalidateTrainingLoads(tx, w.UserID, changed); err != nil {
+			return err
+		}
+
 		w.Data.WorkoutID = w.ID
 		if err := w.Data.Save(tx); err != nil {
 			return err
@@ -687,10 +786,22 @@ func (w *Workout) ReparseFile() (*Workout, error) {
 		return nil, nil
 	}
 
+	if w.GPX.Part > 0 && w.GPX.Part < len(workouts) {
+		return workouts[w.GPX.Part], nil
+	}
+
 	return workouts[0], nil
 }
 
 func (w *Workout) setData(data *MapData) {
+	if w.TimeOffset != 0 {
+		data.shiftTime(w.TimeOffset)
+	}
+
+	if w.TimezoneOverride != "" {
+		data.Center.TZ = w.TimezoneOverride
+	}
+
 	if w.Data == nil {
 		w.Data = data
 		w.Data.WorkoutID = w.ID
@@ -709,6 +820,22 @@ func (w *Workout) setData(data *MapData) {
 	if w.Data.Details != nil {
 		data.Details.ID = w.Data.Details.ID
 		data.Details.MapDataID = w.Data.Details.MapDataID
+		data.Details.Streams = w.Data.Details.Streams
+		data.Details.ApplyStreams()
+	}
+
+	if w.CleanTrack {
+		data.CorrectedPoints = data.CleanTrack(LimitsForType(w.Type), w.SmoothTrack).Corrected()
+	}
+
+	data.DEMPoints = data.CorrectElevation(w.ElevationCorrection)
+
+	if w.GPX != nil {
+		data.removePoints(w.GPX.RemovedPoints)
+	}
+
+	if len(data.Details.Streams) > 0 {
+		data.updateLapStats()
 	}
 
 	if w.Locked {
@@ -718,6 +845,10 @@ func (w *Workout) setData(data *MapData) {
 		data.Address = w.Data.Address
 	}
 
+	if w.TimezoneOverride != "" {
+		data.Center.TZ = w.TimezoneOverride
+	}
+
 	w.Data = data
 }
 
@@ -728,6 +859,8 @@ func (w *Workout) UpdateAverages() {
 
 	if stats, ok := w.aggregateDetailsStats(); ok {
 		w.applyRangeStats(stats)
+		w.Data.updateLapRunningAnalytics(w.Type.IsOnFoot())
+
 		return
 	}
 
@@ -743,7 +876,7 @@ func (w *Workout) aggregateDetailsStats() (MapDataRangeStats, bool) {
 		return MapDataRangeStats{}, false
 	}
 
-	return w.Data.Details.StatsForRange(0, len(w.Data.Details.Points)-1)
+	return w.StatsForRange(0, len(w.Data.Details.Points)-1)
 }
 
 func (w *Workout) applyRangeStats(stats MapDataRangeStats) {
@@ -761,6 +894,11 @@ func (w *Workout) applyRangeStats(stats MapDataRangeStats) {
 	w.Data.AveragePower = stats.AveragePower
 	w.Data.MinPower = stats.MinPower
 	w.Data.MaxPower = stats.MaxPower
+	w.Data.NormalizedPower = stats.NormalizedPower
+	w.Data.VariabilityIndex = stats.VariabilityIndex
+	w.Data.GradeAdjustedSpeed = stats.GradeAdjustedSpeed
+	w.Data.PaceDecoupling = stats.PaceDecoupling
+	w.Data.PowerDecoupling = stats.PowerDecoupling
 
 	w.Data.AverageTemperature = stats.AverageTemperature
 	w.Data.MinTemperature = stats.MinTemperature
@@ -812,6 +950,7 @@ func (w *Workout) UpdateData(db *gorm.DB) error {
 	}
 
 	w.setData(updatedWorkout.Data)
+	w.Data.CalculateSlopes()
 
 	if err := w.Data.Save(db); err != nil {
 		return err
@@ -823,11 +962,19 @@ func (w *Workout) UpdateData(db *gorm.DB) error {
 
 	w.UpdateAverages()
 	w.UpdateExtraMetrics()
+	if err := w.UpdateHeartRateZones(db); err != nil {
+		return err
+	}
+	if err := w.UpdatePowerAnalytics(db); err != nil {
+		return err
+	}
+	if err := w.UpdateTRIMP(db); err != nil {
+		return err
+	}
 	if err := w.UpdateRecords(db); err != nil {
 		return err
 	}
 	w.Data.UpdateAddress()
-	w.Data.CalculateSlopes()
 
 	w.Dirty = false
 
@@ -905,7 +1052,8 @@ func (w *Workout) UpdateExtraMetrics() {
 	w.Data.UpdateExtraMetrics()
 }
 
-// UpdateRecords recalculates and persists best distance intervals for this workout.
+// UpdateRecords recalculates and persists best distance intervals and best
+// efforts by duration for this workout.
 func (w *Workout) UpdateRecords(db *gorm.DB) error {
 	if db == nil {
 		return errors.New("nil db")
@@ -914,6 +1062,10 @@ func (w *Workout) UpdateRecords(db *gorm.DB) error {
 	targets := distanceRecordTargetsFor(w.Type)
 
 	return db.Transaction(func(tx *gorm.DB) error {
+		if err := w.updateEffortRecords(tx); err != nil {
+			return err
+		}
+
 		if err := tx.Where("workout_id = ?", w.ID).Delete(&WorkoutIntervalRecord{}).Error; err != nil {
 			return err
 		}
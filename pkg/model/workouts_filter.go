@@ -12,6 +12,14 @@ type WorkoutFilters struct {
 	Since    string      `query:"since"`
 	OrderBy  string      `query:"order_by"`
 	OrderDir string      `query:"order_dir"`
+
+	MinNormalizedPower     float64 `query:"min_normalized_power"`
+	MinIntensityFactor     float64 `query:"min_intensity_factor"`
+	MaxIntensityFactor     float64 `query:"max_intensity_factor"`
+	MinTrainingStressScore float64 `query:"min_training_stress_score"`
+	MaxTrainingStressScore float64 `query:"max_training_stress_score"`
+
+	joinedMapData bool
 }
 
 func GetWorkoutsFilters(c echo.Context) (*WorkoutFilters, error) {
@@ -42,9 +50,11 @@ func (wf *WorkoutFilters) setDefaults() {
 
 func (wf *WorkoutFilters) ToQuery(db *gorm.DB) *gorm.DB {
 	wf.db = db
+	wf.joinedMapData = false
 
 	wf.setTypeFilter()
 	wf.setSinceFilter()
+	wf.setPowerFilter()
 	wf.setOrderFilter()
 
 	return wf.db
@@ -67,12 +77,43 @@ func (wf *WorkoutFilters) setSinceFilter() {
 	wf.db = wf.db.Where(GetDateLimitExpression(sqlDialect), "-"+wf.Since)
 }
 
+func (wf *WorkoutFilters) setPowerFilter() {
+	filters := []struct {
+		query string
+		value float64
+	}{
+		{"map_data.normalized_power >= ?", wf.MinNormalizedPower},
+		{"map_data.intensity_factor >= ?", wf.MinIntensityFactor},
+		{"map_data.intensity_factor <= ?", wf.MaxIntensityFactor},
+		{"map_data.training_stress_score >= ?", wf.MinTrainingStressScore},
+		{"map_data.training_stress_score <= ?", wf.MaxTrainingStressScore},
+	}
+
+	for _, f := range filters {
+		if f.value <= 0 {
+			continue
+		}
+
+		wf.joinMapData()
+		wf.db = wf.db.Where(f.query, f.value)
+	}
+}
+
+func (wf *WorkoutFilters) joinMapData() {
+	if wf.joinedMapData {
+		return
+	}
+
+	wf.db = wf.db.Select("workouts.*").Joins("left join map_data on workouts.id = map_data.workout_id")
+	wf.joinedMapData = true
+}
+
 func (wf *WorkoutFilters) setOrderFilter() {
 	if wf.OrderBy == "" {
 		return
 	}
 
-	wf.db = wf.db.Select("workouts.*").Joins("left join map_data on workouts.id = map_data.workout_id")
+	wf.joinMapData()
 
 	dir := wf.OrderDir
 	if dir == "" {
@@ -83,7 +124,8 @@ func (wf *WorkoutFilters) setOrderFilter() {
 	case "date":
 		wf.db = wf.db.Order("workouts." + wf.OrderBy + " " + dir)
 	case "total_distance", "total_duration", "total_weight", "total_repetitions", "total_up", "total_down",
-		"average_speed_no_pause", "max_speed":
+		"average_speed_no_pause", "max_speed", "average_power", "normalized_power", "variability_index",
+		"intensity_factor", "training_stress_score":
 		wf.db = wf.db.Order("map_data." + wf.OrderBy + " " + dir)
 	}
 }
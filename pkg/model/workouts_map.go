@@ -27,6 +27,7 @@ var correctAltitudeCreators = []string{
 	"Apple Watch", "Open GPX Tracker for iOS",
 	"StravaGPX iPhone", "StravaGPX",
 	"Workout Tracker",
+	"Suunto", "Polar",
 }
 
 func creatorNeedsCorrection(creator string) bool {
@@ -64,12 +65,22 @@ type MapData struct {
 	Address *geo.Address    `gorm:"serializer:json" json:"address"`                       // The address of the workout
 	Details *MapDataDetails `gorm:"constraint:OnDelete:CASCADE" json:"details,omitempty"` // The details of the workout
 
-	Workout       *Workout  `gorm:"foreignKey:WorkoutID" json:"-"`                                  // The user who owns this profile
-	Creator       string    `json:"creator"`                                                        // The tool that created this workout
-	AddressString string    `json:"addressString"`                                                  // The generic location of the workout
-	Center        MapCenter `gorm:"serializer:json" json:"center"`                                  // The center of the workout (in coordinates)
-	WorkoutID     uint64    `gorm:"not null;uniqueIndex" json:"workoutID"`                          // The workout this data belongs to
-	Climbs        []Segment `gorm:"foreignKey:MapDataID;constraint:OnDelete:CASCADE" json:"climbs"` // Auto-detected climbs
+	Workout       *Workout        `gorm:"foreignKey:WorkoutID" json:"-"`                                             // The user who owns this profile
+	Creator       string          `json:"creator"`                                                                   // The tool that created this workout
+	AddressString string          `json:"addressString"`                                                             // The generic location of the workout
+	Center        MapCenter       `gorm:"serializer:json" json:"center"`                                             // The center of the workout (in coordinates)
+	WorkoutID     uint64          `gorm:"not null;uniqueIndex" json:"workoutID"`                                     // The workout this data belongs to
+	Climbs        []Segment       `gorm:"foreignKey:MapDataID;constraint:OnDelete:CASCADE" json:"climbs"`            // Auto-detected climbs
+	Lengths       []WorkoutLength `gorm:"foreignKey:MapDataID;constraint:OnDelete:CASCADE" json:"lengths,omitempty"` // The lengths of a pool swim
+	Sets          []WorkoutSet    `gorm:"foreignKey:MapDataID;constraint:OnDelete:CASCADE" json:"sets,omitempty"`    // The sets of a strength workout
+
+	CorrectedPoints int `json:"correctedPoints"` // The number of points corrected by cleaning the GPS track
+	DEMPoints       int `json:"demPoints"`       // The number of points with the elevation of the elevation model
+
+	HeartRateZones []time.Duration `gorm:"serializer:json" json:"heartRateZones,omitempty"` // The time spent in each of the heart rate zones of the user
+	PowerZones     []time.Duration `gorm:"serializer:json" json:"powerZones,omitempty"`     // The time spent in each of the power zones of the user
+	TRIMP          float64         `json:"trimp"`                                           // The training impulse (Banister) of the heart rate
+
 	WorkoutData
 }
 
@@ -79,6 +90,9 @@ type MapDataDetails struct {
 	MapData *MapData   `gorm:"foreignKey:MapDataID" json:"-"`
 	Points  []MapPoint `gorm:"foreignKey:MapDataDetailsID;constraint:OnDelete:CASCADE" json:"points"` // The GPS points of the workout
 
+	RRIntervals []float64      `gorm:"serializer:json" json:"-"` // The beat-to-beat intervals of the heart rate, in ms
+	Streams     []SensorStream `gorm:"serializer:json" json:"-"` // Sensor streams borrowed from other recordings of the workout
+
 	MapDataID uint64 `gorm:"not null;uniqueIndex" json:"mapDataID"` // The ID of the map data these details belong to
 }
 
@@ -91,6 +105,29 @@ type MapDataRangeStats struct {
 	Duration       time.Duration // Total duration in this range (including pauses)
 	MovingDuration time.Duration // Duration while moving (based on speed threshold)
 	PauseDuration  time.Duration // Duration spent paused
+
+	ExtraMetrics map[string]MetricStats // Statistics of the other extra metrics, e.g. from FIT developer fields
+}
+
+// MetricStats are the statistics of a single extra metric over a range of points
+type MetricStats struct {
+	Average float64 `json:"average"`
+	Min     float64 `json:"min"`
+	Max     float64 `json:"max"`
+}
+
+// rangeDedicatedMetrics are the extra metrics with dedicated range statistics
+// (or which make no sense to average); all other metrics are aggregated in
+// MapDataRangeStats.ExtraMetrics
+var rangeDedicatedMetrics = map[string]bool{
+	"elevation":        true,
+	"speed":            true,
+	"cadence":          true,
+	"heart-rate":       true,
+	"respiration-rate": true,
+	"power":            true,
+	"temperature":      true,
+	"heading":          true,
 }
 
 // MapCenter is the center of the workout
@@ -244,7 +281,7 @@ func shouldAddState(address *geo.Address) bool {
 
 func (m *MapData) Save(db *gorm.DB) error {
 	return db.Transaction(func(tx *gorm.DB) error {
-		if err := tx.Omit("Climbs", "Details", "Details.Points").Save(m).Error; err != nil {
+		if err := tx.Omit("Climbs", "Lengths", "Sets", "Details", "Details.Points").Save(m).Error; err != nil {
 			return err
 		}
 
@@ -263,6 +300,25 @@ func (m *MapData) Save(db *gorm.DB) error {
 			}
 		}
 
+		for i := range m.Lengths {
+			m.Lengths[i].MapDataID = m.ID
+			m.Lengths[i].SortOrder = i
+		}
+
+		if err := tx.Where("map_data_id = ?", m.ID).Delete(&WorkoutLength{}).Error; err != nil {
+			return err
+		}
+
+		if len(m.Lengths) > 0 {
+			if err := tx.CreateInBatches(&m.Lengths, mapDataClimbsInsertBatchSize).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := m.saveSets(tx); err != nil {
+			return err
+		}
+
 		if m.Details != nil {
 			if m.Details.MapDataID == 0 {
 				m.Details.MapDataID = m.ID
@@ -300,7 +356,14 @@ func PreloadWorkoutData(db *gorm.DB) *gorm.DB {
 		}).
 		Preload("Data.Climbs", func(tx *gorm.DB) *gorm.DB {
 			return tx.Order("sort_order ASC")
-		})
+		}).
+		Preload("Data.Lengths", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("sort_order ASC")
+		}).
+		Preload("Data.Sets", func(tx *gorm.DB) *gorm.DB {
+			return tx.Order("sort_order ASC")
+		}).
+		Preload("Data.Sets.Exercise")
 }
 
 func PreloadWorkoutDetails(db *gorm.DB) *gorm.DB {
@@ -329,6 +392,7 @@ func (m *MapPoint) EnhancedElevation() float64 {
 
 // StatsForRange aggregates statistics for a slice of points identified by start and end indices (inclusive).
 // Returns false when the provided range is invalid or the details contain no points.
+// The grade-adjusted speed and pace decoupling depend on the workout type, see Workout.StatsForRange.
 func (d *MapDataDetails) StatsForRange(startIdx, endIdx int) (MapDataRangeStats, bool) {
 	stats := MapDataRangeStats{}
 
@@ -345,6 +409,9 @@ func (d *MapDataDetails) StatsForRange(startIdx, endIdx int) (MapDataRangeStats,
 	aggregator.processMetrics(points, startIdx, endIdx)
 	aggregator.processDurations(points, startIdx, endIdx)
 	aggregator.finalize()
+	stats.updateNormalizedPower(points[startIdx : endIdx+1])
+	stats.updateDecoupling(points[startIdx:endIdx+1], false)
+
 	return stats, true
 }
 
@@ -388,6 +455,15 @@ type rangeAggregator struct {
 
 	minSpeed   float64
 	foundSpeed bool
+
+	extra map[string]*metricAggregate
+}
+
+type metricAggregate struct {
+	sum   float64
+	count int
+	min   float64
+	max   float64
 }
 
 func newRangeAggregator(stats *MapDataRangeStats, startIdx int) *rangeAggregator {
@@ -406,6 +482,7 @@ func (r *rangeAggregator) processMetrics(points []MapPoint, startIdx, endIdx int
 		r.handleRespirationRate(p)
 		r.handlePower(p)
 		r.handleTemperature(p)
+		r.handleExtraMetrics(p)
 	}
 }
 
@@ -533,6 +610,29 @@ func (r *rangeAggregator) handleTemperature(p MapPoint) {
 	r.maxTemp = max(r.maxTemp, temp)
 }
 
+func (r *rangeAggregator) handleExtraMetrics(p MapPoint) {
+	for name, v := range p.ExtraMetrics {
+		if rangeDedicatedMetrics[name] || math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+
+		if r.extra == nil {
+			r.extra = map[string]*metricAggregate{}
+		}
+
+		a, ok := r.extra[name]
+		if !ok {
+			r.extra[name] = &metricAggregate{sum: v, count: 1, min: v, max: v}
+			continue
+		}
+
+		a.sum += v
+		a.count++
+		a.min = min(a.min, v)
+		a.max = max(a.max, v)
+	}
+}
+
 func (r *rangeAggregator) processDurations(points []MapPoint, startIdx, endIdx int) {
 	for i := startIdx; i <= endIdx; i++ {
 		p := points[i]
@@ -615,6 +715,18 @@ func (r *rangeAggregator) finalize() {
 	if r.foundSpeed {
 		r.stats.MinSpeed = r.minSpeed
 	}
+
+	if len(r.extra) > 0 {
+		r.stats.ExtraMetrics = make(map[string]MetricStats, len(r.extra))
+
+		for name, a := range r.extra {
+			r.stats.ExtraMetrics[name] = MetricStats{
+				Average: a.sum / float64(a.count),
+				Min:     a.min,
+				Max:     a.max,
+			}
+		}
+	}
 }
 
 func (m *MapPoint) DistanceTo(m2 *MapPoint) float64 {
@@ -130,7 +130,7 @@ func SmoothSlopeGrades(points []MapPoint, windowMeters float64) {
 				continue
 			}
 
-			elevDiff := points[j].Elevation - points[i].Elevation
+			elevDiff := points[j].EnhancedElevation() - points[i].EnhancedElevation()
 			slope := elevDiff / distDiff
 
 			weight := 1.0 / distFromCenter
@@ -164,7 +164,7 @@ func DetectSignificantSegments(points []MapPoint, kind SlopeKind) []Segment {
 		currentPoint := &points[i]
 
 		distDiff := currentPoint.TotalDistance - prevPoint.TotalDistance
-		elevDiff := (currentPoint.Elevation - prevPoint.Elevation)
+		elevDiff := (currentPoint.EnhancedElevation() - prevPoint.EnhancedElevation())
 
 		slope := currentPoint.SlopeGrade
 
@@ -240,7 +240,7 @@ func (d *Detector) validateAndAppendSegment(segmentPoints []*MapPoint) {
 		length += segmentPoints[i].Distance
 		duration += segmentPoints[i].Duration
 
-		elevDiff := segmentPoints[i].Elevation - segmentPoints[i-1].Elevation
+		elevDiff := segmentPoints[i].EnhancedElevation() - segmentPoints[i-1].EnhancedElevation()
 		if (d.kind == SlopeKindClimb && elevDiff > 0) || (d.kind == SlopeKindDescent && elevDiff < 0) {
 			gain += math.Abs(elevDiff)
 		}
@@ -41,8 +41,15 @@ type BreakdownItem struct {
 
 	AveragePower float64 `json:"averagePower"`
 	MaxPower     float64 `json:"maxPower"`
-	IsBest       bool    `json:"isBest"`  // Whether this item is the best of the list
-	IsWorst      bool    `json:"isWorst"` // Whether this item is the worst of the list
+
+	GradeAdjustedSpeed float64 `json:"gradeAdjustedSpeed"` // The average speed while moving, adjusted for the slope
+	PaceDecoupling     float64 `json:"paceDecoupling"`     // The drop of grade-adjusted speed per heart beat from the first to the second half, in percent
+	PowerDecoupling    float64 `json:"powerDecoupling"`    // The drop of power per heart beat from the first to the second half, in percent
+
+	IsBest  bool `json:"isBest"`  // Whether this item is the best of the list
+	IsWorst bool `json:"isWorst"` // Whether this item is the worst of the list
+
+	ExtraMetrics map[string]MetricStats `json:"extraMetrics,omitempty"` // Statistics of the other extra metrics
 }
 
 func (bi *BreakdownItem) createNext(fp *MapPoint) BreakdownItem {
@@ -103,6 +110,12 @@ func (bi *BreakdownItem) applyRangeStats(stats MapDataRangeStats) {
 
 	bi.AveragePower = stats.AveragePower
 	bi.MaxPower = stats.MaxPower
+
+	bi.GradeAdjustedSpeed = stats.GradeAdjustedSpeed
+	bi.PaceDecoupling = stats.PaceDecoupling
+	bi.PowerDecoupling = stats.PowerDecoupling
+
+	bi.ExtraMetrics = stats.ExtraMetrics
 }
 
 //gocyclo:ignore
@@ -153,7 +166,7 @@ func (w *Workout) statisticsWithUnit(count float64, unit string) []BreakdownItem
 			nextItem.EndIndex = i
 			nextItem.LastPoint = &points[i]
 			nextItem.CalcultateSpeed()
-			if stats, ok := w.Data.Details.StatsForRange(nextItem.StartIndex, nextItem.EndIndex); ok {
+			if stats, ok := w.StatsForRange(nextItem.StartIndex, nextItem.EndIndex); ok {
 				nextItem.applyRangeStats(stats)
 			}
 			items = append(items, nextItem)
@@ -180,7 +193,7 @@ func (w *Workout) statisticsWithUnit(count float64, unit string) []BreakdownItem
 
 	if nextItem.FirstPoint != nil {
 		nextItem.CalcultateSpeed()
-		if stats, ok := w.Data.Details.StatsForRange(nextItem.StartIndex, nextItem.EndIndex); ok {
+		if stats, ok := w.StatsForRange(nextItem.StartIndex, nextItem.EndIndex); ok {
 			nextItem.applyRangeStats(stats)
 		}
 		items = append(items, nextItem)
@@ -206,6 +219,8 @@ func (w *Workout) StatisticsPer(count float64, unit string) (WorkoutBreakdown, e
 		wb.Items = w.statisticsWithUnit(count*templatehelpers.MeterPerKM, "distance")
 	case "mi":
 		wb.Items = w.statisticsWithUnit(count*templatehelpers.MeterPerMile, "distance")
+	case "yd":
+		wb.Items = w.statisticsWithUnit(count*MeterPerYard, "distance")
 	case "sec":
 		wb.Items = w.statisticsWithUnit(count*float64(time.Second), "duration")
 	case "min":
@@ -0,0 +1,60 @@
+package repository
+
+import (
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"gorm.io/gorm"
+)
+
+type ArchiveImport interface {
+	ListByUserID(userID uint64) ([]*model.ArchiveImport, error)
+	GetByUserID(userID uint64, id uint64) (*model.ArchiveImport, error)
+	GetByID(id uint64) (*model.ArchiveImport, error)
+	Save(archiveImport *model.ArchiveImport) error
+	Delete(archiveImport *model.ArchiveImport) error
+}
+
+type archiveImportRepository struct {
+	db *gorm.DB
+}
+
+func NewArchiveImport(db *gorm.DB) ArchiveImport {
+	return &archiveImportRepository{db: db}
+}
+
+func (r *archiveImportRepository) ListByUserID(userID uint64) ([]*model.ArchiveImport, error) {
+	archiveImports := make([]*model.ArchiveImport, 0)
+
+	if err := r.db.Where(&model.ArchiveImport{UserID: userID}).Order("created_at DESC").Find(&archiveImports).Error; err != nil {
+		return nil, err
+	}
+
+	return archiveImports, nil
+}
+
+func (r *archiveImportRepository) GetByUserID(userID uint64, id uint64) (*model.ArchiveImport, error) {
+	var archiveImport model.ArchiveImport
+
+	if err := r.db.Where(&model.ArchiveImport{UserID: userID}).First(&archiveImport, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &archiveImport, nil
+}
+
+func (r *archiveImportRepository) GetByID(id uint64) (*model.ArchiveImport, error) {
+	var archiveImport model.ArchiveImport
+
+	if err := r.db.First(&archiveImport, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &archiveImport, nil
+}
+
+func (r *archiveImportRepository) Save(archiveImport *model.ArchiveImport) error {
+	return archiveImport.Save(r.db)
+}
+
+func (r *archiveImportRepository) Delete(archiveImport *model.ArchiveImport) error {
+	return archiveImport.Delete(r.db)
+}
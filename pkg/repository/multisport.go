@@ -0,0 +1,47 @@
+package repository
+
+import (
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"gorm.io/gorm"
+)
+
+type Multisport interface {
+	ListByUserID(userID uint64) ([]*model.Multisport, error)
+	GetByUserID(userID uint64, id uint64) (*model.Multisport, error)
+}
+
+type multisportRepository struct {
+	db *gorm.DB
+}
+
+func NewMultisport(db *gorm.DB) Multisport {
+	return &multisportRepository{db: db}
+}
+
+func (r *multisportRepository) ListByUserID(userID uint64) ([]*model.Multisport, error) {
+	multisports := make([]*model.Multisport, 0)
+
+	if err := r.preloadLegs().Where(&model.Multisport{UserID: userID}).Order("date DESC").Find(&multisports).Error; err != nil {
+		return nil, err
+	}
+
+	return multisports, nil
+}
+
+func (r *multisportRepository) GetByUserID(userID uint64, id uint64) (*model.Multisport, error) {
+	var multisport model.Multisport
+
+	if err := r.preloadLegs().Where(&model.Multisport{UserID: userID}).First(&multisport, id).Error; err != nil {
+		return nil, err
+	}
+
+	return &multisport, nil
+}
+
+func (r *multisportRepository) preloadLegs() *gorm.DB {
+	return r.db.
+		Preload("Legs", func(db *gorm.DB) *gorm.DB {
+			return db.Order("date ASC")
+		}).
+		Preload("Legs.Data")
+}
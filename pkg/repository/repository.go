@@ -5,9 +5,11 @@ import "gorm.io/gorm"
 type Repositories struct {
 	APOutbox         APOutbox
 	APOutboxDelivery APOutboxDelivery
+	ArchiveImport    ArchiveImport
 	Equipment        Equipment
 	Follower         Follower
 	Measurement      Measurement
+	Multisport       Multisport
 	RouteSegment     RouteSegment
 	User             User
 	Workout          Workout
@@ -19,9 +21,11 @@ func New(db *gorm.DB) *Repositories {
 	return &Repositories{
 		APOutbox:         NewAPOutbox(db),
 		APOutboxDelivery: NewAPOutboxDelivery(db),
+		ArchiveImport:    NewArchiveImport(db),
 		Equipment:        NewEquipment(db),
 		Follower:         NewFollower(db),
 		Measurement:      NewMeasurement(db),
+		Multisport:       NewMultisport(db),
 		RouteSegment:     NewRouteSegment(db),
 		User:             NewUser(db),
 		Workout:          NewWorkout(db),
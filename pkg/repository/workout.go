@@ -1,6 +1,8 @@
 package repository
 
 import (
+	"time"
+
 	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
 	"gorm.io/gorm"
 )
@@ -12,6 +14,7 @@ type Workout interface {
 	ListByUserAndFilters(userID uint64, filters *model.WorkoutFilters, limit int, offset int) ([]*model.Workout, error)
 	GetByIDForRead(id uint64, withRouteSegmentMatches bool) (*model.Workout, error)
 	GetDetailsByID(id uint64) (*model.Workout, error)
+	ExistsByUserIDNearDate(userID uint64, date time.Time, margin time.Duration) (bool, error)
 }
 
 type workoutRepository struct {
@@ -113,3 +116,19 @@ func (r *workoutRepository) GetDetailsByID(id uint64) (*model.Workout, error) {
 
 	return &workout, nil
 }
+
+// ExistsByUserIDNearDate returns whether the user has a workout starting
+// within the margin of the date
+func (r *workoutRepository) ExistsByUserIDNearDate(userID uint64, date time.Time, margin time.Duration) (bool, error) {
+	var count int64
+
+	q := r.db.Model(&model.Workout{}).
+		Where("user_id = ?", userID).
+		Where("date BETWEEN ? AND ?", date.Add(-margin), date.Add(margin))
+
+	if err := q.Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWorkoutRepository_ExistsByUserIDNearDate(t *testing.T) {
+	db := createRepositoryMemoryDB(t)
+	owner := createRepositoryUser(t, db, "near-owner", "Near Owner", "near-owner-key")
+	other := createRepositoryUser(t, db, "near-other", "Near Other", "near-other-key")
+	workout := createRepositoryWorkout(t, db, owner)
+
+	repo := NewWorkout(db)
+
+	exists, err := repo.ExistsByUserIDNearDate(owner.ID, workout.Date.Add(30*time.Second), time.Minute)
+	require.NoError(t, err)
+	assert.True(t, exists)
+
+	exists, err = repo.ExistsByUserIDNearDate(owner.ID, workout.Date.Add(2*time.Minute), time.Minute)
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	exists, err = repo.ExistsByUserIDNearDate(other.ID, workout.Date, time.Minute)
+	require.NoError(t, err)
+	assert.False(t, exists)
+}
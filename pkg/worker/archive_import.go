@@ -0,0 +1,212 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/importers"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/vgarvardt/gue/v6"
+)
+
+const JobImportArchive = "import_archive"
+
+// archiveImportProgressInterval is the number of processed entries after
+// which the progress of an archive import is saved
+const archiveImportProgressInterval = 25
+
+// archiveImportDuplicateMargin is how close the start of an existing workout
+// should be to consider an imported workout a duplicate; different devices
+// and services report a slightly different start for the same activity
+const archiveImportDuplicateMargin = time.Minute
+
+// EnqueueArchiveImport enqueues a job to import an uploaded export archive.
+func EnqueueArchiveImport(ctx context.Context, c *container.Container, archiveImportID uint64) error {
+	raw, err := json.Marshal(idArgs{ID: archiveImportID})
+	if err != nil {
+		return err
+	}
+
+	return c.Enqueue(ctx, &gue.Job{Queue: MainQueue, Type: JobImportArchive, Args: raw})
+}
+
+func makeImportArchiveHandler(c *container.Container, logger *slog.Logger) gue.WorkFunc {
+	return func(ctx context.Context, j *gue.Job) error {
+		var args idArgs
+		if err := json.Unmarshal(j.Args, &args); err != nil {
+			return fmt.Errorf("import_archive: unmarshal args: %w", err)
+		}
+
+		l := logger.With("archive_import_id", args.ID)
+
+		ai, err := c.ArchiveImportRepo().GetByID(args.ID)
+		if err != nil {
+			return fmt.Errorf("import_archive: get archive import %d: %w", args.ID, err)
+		}
+
+		if ai.Finished() {
+			return nil
+		}
+
+		u, err := c.UserRepo().GetByID(ai.UserID)
+		if err != nil {
+			return fmt.Errorf("import_archive: get user %d: %w", ai.UserID, err)
+		}
+
+		importArchive(ctx, c, l.With("user", u.Username, "program", ai.Program), u, ai)
+
+		// The archive is removed after the import, so retrying makes no sense;
+		// the outcome is stored on the archive import itself
+		return c.ArchiveImportRepo().Save(ai)
+	}
+}
+
+func importArchive(ctx context.Context, c *container.Container, l *slog.Logger, u *model.User, ai *model.ArchiveImport) {
+	l.Info("Importing archive")
+
+	ai.Status = model.ArchiveImportStatusRunning
+	if err := c.ArchiveImportRepo().Save(ai); err != nil {
+		l.Error("Failed to save archive import", "error", err)
+	}
+
+	sink := &archiveImportSink{ctx: ctx, c: c, logger: l, user: u, archiveImport: ai}
+
+	if err := importers.ImportArchive(ctx, importers.ArchiveProgram(ai.Program), ai.Path, sink); err != nil {
+		l.Error("Archive import failed", "error", err)
+
+		ai.Status = model.ArchiveImportStatusFailed
+		ai.Error = err.Error()
+	} else {
+		ai.Status = model.ArchiveImportStatusDone
+	}
+
+	if err := os.Remove(ai.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		l.Error("Failed to remove archive", "path", ai.Path, "error", err)
+	}
+
+	l.Info("Finished archive import", "workouts", ai.Workouts, "skipped", ai.Skipped, "failed", ai.Failed)
+}
+
+// archiveImportSink stores everything read from an archive for the user,
+// and keeps track of the progress on the archive import
+type archiveImportSink struct {
+	ctx           context.Context
+	c             *container.Container
+	logger        *slog.Logger
+	user          *model.User
+	archiveImport *model.ArchiveImport
+	processed     int
+}
+
+func (s *archiveImportSink) AddWorkout(aw *importers.ArchiveWorkout) {
+	defer s.progress()
+
+	db := s.c.GetDB()
+
+	workoutType := aw.Type
+	if workoutType == "" {
+		workoutType = model.WorkoutTypeAutoDetect
+	}
+
+	equipment := s.equipment(aw.Equipment)
+
+	for _, w := range model.PrepareParsedWorkouts(s.user, workoutType, aw.Notes, aw.Entry, aw.Workouts) {
+		if aw.Name != "" {
+			if w.Multisport != nil {
+				// The legs keep the name of their sport
+				w.Multisport.Name = aw.Name
+			} else {
+				w.Name = aw.Name
+			}
+		}
+
+		exists, err := s.c.WorkoutRepo().ExistsByUserIDNearDate(s.user.ID, w.Date, archiveImportDuplicateMargin)
+		if err != nil {
+			s.archiveImport.AddError(aw.Entry, err)
+			continue
+		}
+
+		if exists {
+			s.archiveImport.Skipped++
+			continue
+		}
+
+		w.Commute = aw.Commute
+
+		// The user's defaults and track cleaning apply as to any other import
+		o := &model.WorkoutOverrides{Visibility: aw.Visibility, Equipment: equipment}
+
+		if err := s.user.CreateWorkout(db, w, o); err != nil {
+			if errors.Is(err, model.ErrWorkoutAlreadyExists) {
+				s.archiveImport.Skipped++
+				continue
+			}
+
+			s.archiveImport.AddError(aw.Entry, err)
+
+			continue
+		}
+
+		if err := EnqueueWorkoutUpdate(s.ctx, s.c, w.ID); err != nil {
+			s.logger.Error("Failed to enqueue workout update after import", "workout_id", w.ID, "error", err)
+		}
+
+		s.archiveImport.Workouts++
+	}
+}
+
+func (s *archiveImportSink) AddMeasurement(entry string, date time.Time, update func(m *model.Measurement)) {
+	defer s.progress()
+
+	if _, err := SaveMeasurement(s.ctx, s.c, s.user, date, update); err != nil {
+		s.archiveImport.AddError(entry, err)
+		return
+	}
+
+	s.archiveImport.Measurements++
+}
+
+func (s *archiveImportSink) Skip(_ string) {
+	defer s.progress()
+
+	s.archiveImport.Skipped++
+}
+
+func (s *archiveImportSink) Fail(entry string, err error) {
+	defer s.progress()
+
+	s.logger.Warn("Could not import archive entry", "entry", entry, "error", err)
+	s.archiveImport.AddError(entry, err)
+}
+
+// equipment returns the user's equipment matching the names; nil when none
+// of the names match
+func (s *archiveImportSink) equipment(names []string) []*model.Equipment {
+	var equipment []*model.Equipment
+
+	for _, n := range names {
+		if e := s.user.EquipmentByName(n); e != nil {
+			equipment = append(equipment, e)
+		}
+	}
+
+	return equipment
+}
+
+func (s *archiveImportSink) progress() {
+	s.processed++
+
+	if s.processed%archiveImportProgressInterval != 0 {
+		return
+	}
+
+	if err := s.c.ArchiveImportRepo().Save(s.archiveImport); err != nil {
+		s.logger.Error("Failed to save archive import progress", "error", err)
+	}
+}
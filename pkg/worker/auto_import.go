@@ -8,8 +8,6 @@ import (
 	"log/slog"
 	"os"
 	"path/filepath"
-	"slices"
-	"strings"
 	"time"
 
 	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
@@ -101,6 +99,15 @@ func importFile(ctx context.Context, c *container.Container, logger *slog.Logger
 		return err
 	}
 
+	ms, err := converters.ParseMeasurements(path, dat)
+	if err == nil {
+		return importMeasurements(ctx, c, logger, u, ms)
+	}
+
+	if !errors.Is(err, converters.ErrNotMeasurementFile) {
+		return err
+	}
+
 	ws, addErr := u.AddWorkout(db, model.WorkoutTypeAutoDetect, "", path, dat)
 	if len(addErr) > 0 {
 		return addErr[0]
@@ -121,6 +128,24 @@ func importFile(ctx context.Context, c *container.Container, logger *slog.Logger
 	return nil
 }
 
+// importMeasurements merges the daily measurements of a weight-scale or
+// monitoring file into the user's measurements
+func importMeasurements(ctx context.Context, c *container.Container, logger *slog.Logger, u *model.User, ms []*model.Measurement) error {
+	if len(ms) == 0 {
+		return ErrNothingImported
+	}
+
+	for _, m := range ms {
+		if err := MergeMeasurement(ctx, c, u, m); err != nil {
+			return err
+		}
+	}
+
+	logger.Info("Finished import of measurements.", "days", len(ms))
+
+	return nil
+}
+
 func moveImportFile(logger *slog.Logger, dir, path, statusDir string) error {
 	destDir := filepath.Join(dir, statusDir)
 	destPath := filepath.Join(destDir, filepath.Base(path))
@@ -151,5 +176,5 @@ func fileCanBeImported(p string, i os.FileInfo) bool {
 		return false
 	}
 
-	return slices.Contains(converters.SupportedFileTypes, strings.ToLower(filepath.Ext(p)))
+	return converters.IsSupportedFile(filepath.Base(p))
 }
@@ -0,0 +1,55 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+	"github.com/vgarvardt/gue/v6"
+)
+
+const JobCorrectElevation = "correct_elevation"
+
+type elevationCorrectionArgs struct {
+	Mode model.ElevationCorrection `json:"mode"`
+}
+
+// EnqueueElevationCorrection enqueues a job to change how the elevation of
+// all workouts is corrected with the elevation model.
+func EnqueueElevationCorrection(ctx context.Context, c *container.Container, mode model.ElevationCorrection) error {
+	raw, err := json.Marshal(elevationCorrectionArgs{Mode: mode})
+	if err != nil {
+		return err
+	}
+
+	return c.Enqueue(ctx, &gue.Job{Queue: MainQueue, Type: JobCorrectElevation, Args: raw})
+}
+
+func makeCorrectElevationHandler(c *container.Container, logger *slog.Logger) gue.WorkFunc {
+	return func(ctx context.Context, j *gue.Job) error {
+		var args elevationCorrectionArgs
+		if err := json.Unmarshal(j.Args, &args); err != nil {
+			return fmt.Errorf("correct_elevation: unmarshal args: %w", err)
+		}
+
+		l := logger.With("mode", args.Mode)
+
+		ids, err := model.SetElevationCorrectionForAll(c.GetDB(), args.Mode)
+		if err != nil {
+			return fmt.Errorf("correct_elevation: %w", err)
+		}
+
+		l.Info("Correcting the elevation of workouts", "workouts", len(ids))
+
+		for _, id := range ids {
+			if err := EnqueueWorkoutUpdate(ctx, c, id); err != nil {
+				l.Error("Failed to enqueue workout update", "workout_id", id, "error", err)
+			}
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,61 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/jovandeginste/workout-tracker/v2/pkg/container"
+	"github.com/jovandeginste/workout-tracker/v2/pkg/model"
+)
+
+// SaveMeasurement updates and saves the user's measurement of the date, and
+// enqueues the workouts whose heart rate or power zones changed with it for an
+// update. Call this wherever a measurement is created or updated.
+func SaveMeasurement(ctx context.Context, c *container.Container, u *model.User, date time.Time, update func(m *model.Measurement)) (*model.Measurement, error) {
+	m, err := c.MeasurementRepo().GetByUserIDForDateOrNew(u.ID, date)
+	if err != nil {
+		return nil, err
+	}
+
+	before := *m
+
+	update(m)
+
+	if err := c.MeasurementRepo().Save(m); err != nil {
+		return nil, err
+	}
+
+	if err := RefreshMeasurementWorkouts(ctx, c, u, &before, m); err != nil {
+		return nil, err
+	}
+
+	return m, nil
+}
+
+// MergeMeasurement merges the non-zero values of the measurement into the
+// user's measurement of the same date, see SaveMeasurement
+func MergeMeasurement(ctx context.Context, c *container.Container, u *model.User, from *model.Measurement) error {
+	_, err := SaveMeasurement(ctx, c, u, time.Time(from.Date), func(m *model.Measurement) {
+		m.MergeNonZero(*from)
+	})
+
+	return err
+}
+
+// RefreshMeasurementWorkouts marks the workouts whose heart rate or power
+// zones changed between both measurements dirty, and enqueues them for an
+// update
+func RefreshMeasurementWorkouts(ctx context.Context, c *container.Container, u *model.User, before, after *model.Measurement) error {
+	ids, err := u.MarkMeasurementWorkoutsDirty(c.GetDB(), before, after)
+	if err != nil {
+		return err
+	}
+
+	for _, id := range ids {
+		if err := EnqueueWorkoutUpdate(ctx, c, id); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -73,6 +73,8 @@ func New(c *container.Container) (*Worker, error) {
 		JobUpdateRouteSegment: makeUpdateRouteSegmentHandler(c, logger),
 		JobAutoImport:         makeAutoImportHandler(c, logger),
 		JobDeliverActivityPub: makeDeliverActivityPubHandler(c, logger),
+		JobImportArchive:      makeImportArchiveHandler(c, logger),
+		JobCorrectElevation:   makeCorrectElevationHandler(c, logger),
 	}
 
 	geoWM := gue.WorkMap{